@@ -56,6 +56,26 @@ var BlueprintResource = customresource.CustomResource{
 	Kind:    reflect.TypeOf(Blueprint{}).Name(),
 }
 
+// BlueprintLibraryResource is a CRD for blueprint libraries.
+var BlueprintLibraryResource = customresource.CustomResource{
+	Name:    consts.BlueprintLibraryResourceName,
+	Plural:  consts.BlueprintLibraryResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1.NamespaceScoped,
+	Kind:    reflect.TypeOf(BlueprintLibrary{}).Name(),
+}
+
+// BlueprintRevisionResource is a CRD for blueprint revisions.
+var BlueprintRevisionResource = customresource.CustomResource{
+	Name:    consts.BlueprintRevisionResourceName,
+	Plural:  consts.BlueprintRevisionResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1.NamespaceScoped,
+	Kind:    reflect.TypeOf(BlueprintRevision{}).Name(),
+}
+
 // ProfileResource is a CRD for blueprints.
 var ProfileResource = customresource.CustomResource{
 	Name:    consts.ProfileResourceName,
@@ -93,6 +113,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ActionSetList{},
 		&Blueprint{},
 		&BlueprintList{},
+		&BlueprintLibrary{},
+		&BlueprintLibraryList{},
+		&BlueprintRevision{},
+		&BlueprintRevisionList{},
 		&Profile{},
 		&ProfileList{},
 		&RepositoryServer{},
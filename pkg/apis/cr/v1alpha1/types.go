@@ -92,6 +92,9 @@ type ActionSpec struct {
 	Object ObjectReference `json:"object"`
 	// Blueprint with instructions on how to execute this action.
 	Blueprint string `json:"blueprint,omitempty"`
+	// BlueprintRevision pins the action to a revision of the Blueprint, e.g.
+	// the one a backup was created with, instead of its current actions.
+	BlueprintRevision string `json:"blueprintRevision,omitempty"`
 	// Artifacts will be passed as inputs into this phase.
 	Artifacts map[string]Artifact `json:"artifacts,omitempty"`
 	// ConfigMaps that we'll get and pass into the blueprint.
@@ -138,6 +141,8 @@ type ActionStatus struct {
 	Object ObjectReference `json:"object"`
 	// Blueprint with instructions on how to execute this action.
 	Blueprint string `json:"blueprint"`
+	// BlueprintRevision is the revision of the Blueprint the action runs.
+	BlueprintRevision string `json:"blueprintRevision,omitempty"`
 	// Phases are sub-actions an are executed sequentially.
 	Phases []Phase `json:"phases,omitempty"`
 	// Artifacts created by this phase.
@@ -145,6 +150,34 @@ type ActionStatus struct {
 	// DeferPhase is the phase that is executed at the end of an action
 	// irrespective of the status of other phases in the action
 	DeferPhase Phase `json:"deferPhase,omitempty"`
+	// Quiesces are the application quiesces of this action that are not undone yet.
+	Quiesces []QuiesceStatus `json:"quiesces,omitempty"`
+}
+
+// QuiesceStatus records an application quiesce created by the Quiesce function.
+// The controller unquiesces the application when the lease expires, when the
+// action ends or after a controller restart.
+type QuiesceStatus struct {
+	// ID identifies the quiesce within the action.
+	ID string `json:"id"`
+	// Handler quiesces the application. One of fsfreeze, scaleToZero or exec.
+	Handler string `json:"handler"`
+	// Namespace of the application.
+	Namespace string `json:"namespace"`
+	// Pod and Container in which the fsfreeze and exec handlers run commands.
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	// Paths are the mount points frozen by the fsfreeze handler.
+	Paths []string `json:"paths,omitempty"`
+	// UnquiesceCommand is run by the exec handler to unquiesce the application.
+	UnquiesceCommand []string `json:"unquiesceCommand,omitempty"`
+	// Kind and Name of the workload scaled by the scaleToZero handler.
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Replicas is the replica count restored by the scaleToZero handler.
+	Replicas int32 `json:"replicas,omitempty"`
+	// ExpiresAt is the end of the lease of the quiesce.
+	ExpiresAt metav1.Time `json:"expiresAt"`
 }
 
 // ActionProgress provides information on the combined progress
@@ -267,6 +300,41 @@ type BlueprintAction struct {
 	// A DeferPhase is executed regardless of the statuses of the other phases of the action.
 	// A DeferPhase can be used for cleanup operations at the end of an action.
 	DeferPhase *BlueprintPhase `json:"deferPhase,omitempty"`
+	// Extends refers to an action of another Blueprint or of a BlueprintLibrary
+	// this action inherits from. The Phases of this action with the name of an
+	// inherited phase override it, the other ones are appended.
+	Extends *BlueprintReference `json:"extends,omitempty"`
+	// Parameters declares the Options of the ActionSet the action accepts.
+	// If it is set, the options that are not declared are rejected.
+	Parameters []BlueprintParameter `json:"parameters,omitempty"`
+}
+
+// ParameterType is the type of the value of a BlueprintParameter
+type ParameterType string
+
+const (
+	ParameterTypeString  ParameterType = "string"
+	ParameterTypeInteger ParameterType = "integer"
+	ParameterTypeBoolean ParameterType = "boolean"
+)
+
+// BlueprintParameter declares a parameter of a BlueprintAction, which is set
+// with an option of the ActionSet.
+type BlueprintParameter struct {
+	// Name of the parameter, i.e. the key of the option.
+	Name string `json:"name"`
+	// Type of the value of the parameter, "string", "integer" or "boolean".
+	// Defaults to "string".
+	Type ParameterType `json:"type,omitempty"`
+	// Required specifies whether the option must be set.
+	Required bool `json:"required,omitempty"`
+	// Default is the value of the option if it is not set. An empty value
+	// means that the parameter has no default.
+	Default string `json:"default,omitempty"`
+	// Enum is the list of allowed values of the option.
+	Enum []string `json:"enum,omitempty"`
+	// Description describes the parameter.
+	Description string `json:"description,omitempty"`
 }
 
 // BlueprintPhase is a an individual unit of execution.
@@ -280,6 +348,26 @@ type BlueprintPhase struct {
 	ObjectRefs map[string]ObjectReference `json:"objects,omitempty"`
 	// Args represents a map of named arguments that the controller will pass to the Kanister function.
 	Args map[string]interface{} `json:"args"`
+	// Ref refers to a phase of another Blueprint or of a BlueprintLibrary that
+	// is used instead of Func. The Args and ObjectRefs of this phase override
+	// the ones of the referenced phase.
+	Ref *BlueprintReference `json:"ref,omitempty"`
+}
+
+// BlueprintReference refers to an action or a phase of a Blueprint or of a
+// BlueprintLibrary in the namespace of the referencing Blueprint.
+type BlueprintReference struct {
+	// Kind of the referenced resource, Blueprint or BlueprintLibrary.
+	// Defaults to BlueprintLibrary.
+	Kind string `json:"kind,omitempty"`
+	// Name of the referenced resource. If empty, the reference refers to the
+	// Blueprint or BlueprintLibrary that contains it.
+	Name string `json:"name,omitempty"`
+	// Action is the name of the referenced action.
+	Action string `json:"action,omitempty"`
+	// Phase is the name of the referenced phase, in the Action if it is set,
+	// or else in the Phases of the BlueprintLibrary.
+	Phase string `json:"phase,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -296,6 +384,59 @@ type BlueprintList struct {
 // +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
+// BlueprintLibrary describes phases and actions that Blueprints can refer to.
+type BlueprintLibrary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Phases is the list of phases that can be referred to by name.
+	Phases []BlueprintPhase `json:"phases,omitempty"`
+	// Actions is the map of actions that can be referred to or extended.
+	Actions map[string]*BlueprintAction `json:"actions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BlueprintLibraryList is the definition of a list of BlueprintLibraries.
+type BlueprintLibraryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	// Items is the list of BlueprintLibraries.
+	Items []*BlueprintLibrary `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BlueprintRevision is an immutable copy of the actions of a Blueprint, with
+// their references to other Blueprints and BlueprintLibraries resolved. The
+// controller creates a revision when it runs an action of a Blueprint whose
+// actions changed, and records it in the status of the ActionSet.
+type BlueprintRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Blueprint is the name of the Blueprint of the revision.
+	Blueprint string `json:"blueprint"`
+	// Hash is the hash of the Actions, which identifies the revision.
+	Hash string `json:"hash"`
+	// Actions are the actions of the Blueprint.
+	Actions map[string]*BlueprintAction `json:"actions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BlueprintRevisionList is the definition of a list of BlueprintRevisions.
+type BlueprintRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	// Items is the list of BlueprintRevisions.
+	Items []*BlueprintRevision `json:"items"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
 // Profile captures information about a storage location for backup artifacts and
 // corresponding credentials, that will be made available to a Blueprint phase.
 type Profile struct {
@@ -336,6 +477,28 @@ type Location struct {
 	Prefix string `json:"prefix"`
 	// Region represents the region of the bucket specified above.
 	Region string `json:"region"`
+	// ObjectLock, if set, protects the backup objects written to the Location
+	// from deletion using S3 Object Lock. Only supported for "S3Compliant"
+	// locations whose bucket was created with Object Lock enabled.
+	ObjectLock *ObjectLock `json:"objectLock,omitempty"`
+}
+
+// ObjectLockMode
+type ObjectLockMode string
+
+const (
+	ObjectLockModeGovernance ObjectLockMode = "governance"
+	ObjectLockModeCompliance ObjectLockMode = "compliance"
+)
+
+// ObjectLock
+type ObjectLock struct {
+	// Mode specifies the retention mode applied to the backup objects.
+	// Supported values are "governance" and "compliance".
+	Mode ObjectLockMode `json:"mode"`
+	// RetentionPeriod specifies how long, after being written, the backup
+	// objects are retained, e.g. "720h".
+	RetentionPeriod metav1.Duration `json:"retentionPeriod"`
 }
 
 // CredentialType
@@ -242,6 +242,13 @@ func (in *ActionStatus) DeepCopyInto(out *ActionStatus) {
 		}
 	}
 	in.DeferPhase.DeepCopyInto(&out.DeferPhase)
+	if in.Quiesces != nil {
+		in, out := &in.Quiesces, &out.Quiesces
+		*out = make([]QuiesceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -355,6 +362,18 @@ func (in *BlueprintAction) DeepCopyInto(out *BlueprintAction) {
 		in, out := &in.DeferPhase, &out.DeferPhase
 		*out = (*in).DeepCopy()
 	}
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = new(BlueprintReference)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]BlueprintParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -368,6 +387,91 @@ func (in *BlueprintAction) DeepCopy() *BlueprintAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintLibrary) DeepCopyInto(out *BlueprintLibrary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]BlueprintPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make(map[string]*BlueprintAction, len(*in))
+		for key, val := range *in {
+			var outVal *BlueprintAction
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(BlueprintAction)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintLibrary.
+func (in *BlueprintLibrary) DeepCopy() *BlueprintLibrary {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintLibrary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueprintLibrary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintLibraryList) DeepCopyInto(out *BlueprintLibraryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]*BlueprintLibrary, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(BlueprintLibrary)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintLibraryList.
+func (in *BlueprintLibraryList) DeepCopy() *BlueprintLibraryList {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintLibraryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueprintLibraryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlueprintList) DeepCopyInto(out *BlueprintList) {
 	*out = *in
@@ -405,6 +509,27 @@ func (in *BlueprintList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintParameter) DeepCopyInto(out *BlueprintParameter) {
+	*out = *in
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintParameter.
+func (in *BlueprintParameter) DeepCopy() *BlueprintParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintPhase.
 func (in *BlueprintPhase) DeepCopy() *BlueprintPhase {
 	if in == nil {
@@ -415,6 +540,100 @@ func (in *BlueprintPhase) DeepCopy() *BlueprintPhase {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintReference) DeepCopyInto(out *BlueprintReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintReference.
+func (in *BlueprintReference) DeepCopy() *BlueprintReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintRevision) DeepCopyInto(out *BlueprintRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make(map[string]*BlueprintAction, len(*in))
+		for key, val := range *in {
+			var outVal *BlueprintAction
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(BlueprintAction)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintRevision.
+func (in *BlueprintRevision) DeepCopy() *BlueprintRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueprintRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintRevisionList) DeepCopyInto(out *BlueprintRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]*BlueprintRevision, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(BlueprintRevision)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintRevisionList.
+func (in *BlueprintRevisionList) DeepCopy() *BlueprintRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BlueprintRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CacheSizeSettings) DeepCopyInto(out *CacheSizeSettings) {
 	*out = *in
@@ -588,6 +807,11 @@ func (in *KopiaServerSecretRef) DeepCopy() *KopiaServerSecretRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Location) DeepCopyInto(out *Location) {
 	*out = *in
+	if in.ObjectLock != nil {
+		in, out := &in.ObjectLock, &out.ObjectLock
+		*out = new(ObjectLock)
+		**out = **in
+	}
 	return
 }
 
@@ -601,6 +825,23 @@ func (in *Location) DeepCopy() *Location {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectLock) DeepCopyInto(out *ObjectLock) {
+	*out = *in
+	out.RetentionPeriod = in.RetentionPeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectLock.
+func (in *ObjectLock) DeepCopy() *ObjectLock {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectLock)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectReference) DeepCopyInto(out *ObjectReference) {
 	*out = *in
@@ -652,7 +893,7 @@ func (in *Profile) DeepCopyInto(out *Profile) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Location = in.Location
+	in.Location.DeepCopyInto(&out.Location)
 	in.Credential.DeepCopyInto(&out.Credential)
 	return
 }
@@ -712,6 +953,33 @@ func (in *ProfileList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuiesceStatus) DeepCopyInto(out *QuiesceStatus) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnquiesceCommand != nil {
+		in, out := &in.UnquiesceCommand, &out.UnquiesceCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuiesceStatus.
+func (in *QuiesceStatus) DeepCopy() *QuiesceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QuiesceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Repository) DeepCopyInto(out *Repository) {
 	*out = *in
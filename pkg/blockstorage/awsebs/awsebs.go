@@ -25,6 +25,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/jpillora/backoff"
 	"github.com/pkg/errors"
 
@@ -40,6 +41,7 @@ import (
 
 var _ blockstorage.Provider = (*EbsStorage)(nil)
 var _ zone.Mapper = (*EbsStorage)(nil)
+var _ blockstorage.GroupSnapshotter = (*EbsStorage)(nil)
 
 // EbsStorage implements blockstorage.Provider
 type EbsStorage struct {
@@ -278,6 +280,11 @@ func (s *EbsStorage) SnapshotCopy(ctx context.Context, from, to blockstorage.Sna
 	if err != nil {
 		return nil, errors.Wrapf(err, "Could not get EC2 client")
 	}
+	return s.copySnapshot(ctx, ec2Cli, from, to)
+}
+
+// copySnapshot copies snapshot 'from' using ec2Cli, which must be a client of the destination region.
+func (s *EbsStorage) copySnapshot(ctx context.Context, ec2Cli *EC2, from, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
 	// Include a presigned URL when the regions are different. Include it
 	// independent of whether or not the snapshot is encrypted.
 	var presignedURL *string
@@ -339,9 +346,76 @@ func (s *EbsStorage) SnapshotCopy(ctx context.Context, from, to blockstorage.Sna
 	return rs, nil
 }
 
-// SnapshotCopyWithArgs is part of blockstorage.Provider
+// SnapshotCopyWithArgs copies snapshot 'from' into the AWS account of the credentials provided
+// in args (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and optionally role). The source snapshot
+// is shared with the destination account while the copy is in progress.
 func (s *EbsStorage) SnapshotCopyWithArgs(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot, args map[string]string) (*blockstorage.Snapshot, error) {
-	return nil, errors.New("Copy Snapshot with Args not implemented")
+	if to.Region == "" {
+		return nil, errors.New("Destination snapshot Region must be specified")
+	}
+	if to.ID != "" {
+		return nil, errors.Errorf("Snapshot %v destination ID must be empty", to)
+	}
+	dstConfig := make(map[string]string, len(args)+1)
+	for k, v := range args {
+		dstConfig[k] = v
+	}
+	dstConfig[awsconfig.ConfigRegion] = to.Region
+	awsConfig, region, err := awsconfig.GetConfig(ctx, dstConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not get destination AWS config")
+	}
+	accountID, err := getAccountID(ctx, awsConfig.Copy(), region)
+	if err != nil {
+		return nil, err
+	}
+	// Sharing must be done from the source region.
+	srcCli, err := newEC2Client(from.Region, s.Ec2Cli.Config.Copy())
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not get EC2 client")
+	}
+	if err = shareSnapshot(ctx, srcCli, from.ID, accountID, ec2.OperationTypeAdd); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := shareSnapshot(ctx, srcCli, from.ID, accountID, ec2.OperationTypeRemove); err != nil {
+			log.WithError(err).Print("Failed to stop sharing snapshot", field.M{"SnapshotID": from.ID, "AccountID": accountID})
+		}
+	}()
+	// Copy operation must be initiated from the destination account and region.
+	dstCli, err := newEC2Client(region, awsConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not get EC2 client")
+	}
+	return s.copySnapshot(ctx, dstCli, from, to)
+}
+
+// shareSnapshot adds or removes the permission of accountID to create volumes from snapshot snapID
+func shareSnapshot(ctx context.Context, ec2Cli *EC2, snapID, accountID, operation string) error {
+	msai := &ec2.ModifySnapshotAttributeInput{
+		SnapshotId:    aws.String(snapID),
+		Attribute:     aws.String(ec2.SnapshotAttributeNameCreateVolumePermission),
+		OperationType: aws.String(operation),
+		UserIds:       []*string{aws.String(accountID)},
+	}
+	if _, err := ec2Cli.ModifySnapshotAttributeWithContext(ctx, msai); err != nil {
+		return errors.Wrapf(err, "Failed to modify create volume permission of snapshot %s", snapID)
+	}
+	return nil
+}
+
+// getAccountID returns the AWS account ID of the credentials in config
+func getAccountID(ctx context.Context, config *aws.Config, region string) (string, error) {
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create session")
+	}
+	stsCli := sts.New(sess, aws.NewConfig().WithRegion(region).WithMaxRetries(maxRetries))
+	gcio, err := stsCli.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get destination account ID")
+	}
+	return aws.StringValue(gcio.Account), nil
 }
 
 // SnapshotCreate is part of blockstorage.Provider
@@ -375,6 +449,113 @@ func (s *EbsStorage) SnapshotCreate(ctx context.Context, volume blockstorage.Vol
 	return ms, nil
 }
 
+// SnapshotGroupCreate is part of blockstorage.GroupSnapshotter
+// SnapshotGroupCreate uses CreateSnapshots to take crash-consistent snapshots of
+// volumes attached to the same instance.
+func (s *EbsStorage) SnapshotGroupCreate(ctx context.Context, volumes []blockstorage.Volume, tags map[string]string) ([]*blockstorage.Snapshot, error) {
+	if len(volumes) == 0 {
+		return nil, errors.New("No volumes to snapshot")
+	}
+	volIDs := make([]*string, 0, len(volumes))
+	for _, volume := range volumes {
+		volIDs = append(volIDs, aws.String(volume.ID))
+	}
+	dvo, err := s.Ec2Cli.DescribeVolumesWithContext(ctx, &ec2.DescribeVolumesInput{VolumeIds: volIDs})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get volumes")
+	}
+	instanceID, err := groupInstanceID(dvo.Volumes)
+	if err != nil {
+		return nil, err
+	}
+	dio, err := s.Ec2Cli.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: []*string{aws.String(instanceID)}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get instance %s", instanceID)
+	}
+	if len(dio.Reservations) == 0 || len(dio.Reservations[0].Instances) == 0 {
+		return nil, errors.Errorf("Instance %s not found", instanceID)
+	}
+	csi := &ec2.CreateSnapshotsInput{
+		InstanceSpecification: groupInstanceSpecification(dio.Reservations[0].Instances[0], volumes),
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+				Tags:         mapToEC2Tags(ktags.GetTags(tags)),
+			},
+		},
+	}
+	log.Print("Snapshotting EBS volumes", field.M{"instance_id": instanceID, "volume_count": len(volumes)})
+	cso, err := s.Ec2Cli.CreateSnapshotsWithContext(ctx, csi)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create snapshots")
+	}
+	byVolume := make(map[string]*ec2.SnapshotInfo, len(cso.Snapshots))
+	for _, si := range cso.Snapshots {
+		byVolume[aws.StringValue(si.VolumeId)] = si
+	}
+	snaps := make([]*blockstorage.Snapshot, 0, len(volumes))
+	for i := range volumes {
+		si, ok := byVolume[volumes[i].ID]
+		if !ok {
+			return nil, errors.Errorf("No snapshot created for volume %s", volumes[i].ID)
+		}
+		rs := s.snapshotParse(ctx, &ec2.Snapshot{
+			Encrypted:  si.Encrypted,
+			SnapshotId: si.SnapshotId,
+			StartTime:  si.StartTime,
+			Tags:       si.Tags,
+			VolumeId:   si.VolumeId,
+			VolumeSize: si.VolumeSize,
+		})
+		volume := volumes[i]
+		rs.Volume = &volume
+		snaps = append(snaps, rs)
+	}
+	return snaps, nil
+}
+
+// groupInstanceID returns the instance to which all volumes are attached
+func groupInstanceID(volumes []*ec2.Volume) (string, error) {
+	var instanceID string
+	for _, vol := range volumes {
+		if len(vol.Attachments) != 1 {
+			return "", errors.Wrapf(blockstorage.ErrGroupSnapshotNotSupported, "Volume %s is not attached to a single instance", aws.StringValue(vol.VolumeId))
+		}
+		id := aws.StringValue(vol.Attachments[0].InstanceId)
+		if instanceID != "" && id != instanceID {
+			return "", errors.Wrap(blockstorage.ErrGroupSnapshotNotSupported, "Volumes are attached to different instances")
+		}
+		instanceID = id
+	}
+	return instanceID, nil
+}
+
+// groupInstanceSpecification excludes the volumes of the instance which are not part of the group
+func groupInstanceSpecification(instance *ec2.Instance, volumes []blockstorage.Volume) *ec2.InstanceSpecification {
+	inGroup := make(map[string]bool, len(volumes))
+	for _, volume := range volumes {
+		inGroup[volume.ID] = true
+	}
+	is := &ec2.InstanceSpecification{
+		InstanceId:        instance.InstanceId,
+		ExcludeBootVolume: aws.Bool(true),
+	}
+	for _, bdm := range instance.BlockDeviceMappings {
+		if bdm.Ebs == nil {
+			continue
+		}
+		volID := aws.StringValue(bdm.Ebs.VolumeId)
+		isBoot := aws.StringValue(bdm.DeviceName) == aws.StringValue(instance.RootDeviceName)
+		switch {
+		case inGroup[volID] && isBoot:
+			is.ExcludeBootVolume = aws.Bool(false)
+		case !inGroup[volID] && !isBoot:
+			is.ExcludeDataVolumeIds = append(is.ExcludeDataVolumeIds, aws.String(volID))
+		}
+	}
+	return is
+}
+
 // SnapshotCreateWaitForCompletion is part of blockstorage.Provider
 func (s *EbsStorage) SnapshotCreateWaitForCompletion(ctx context.Context, snap *blockstorage.Snapshot) error {
 	if s.Ec2Cli.DryRun {
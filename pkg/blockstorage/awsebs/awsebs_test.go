@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
 	. "gopkg.in/check.v1"
 
 	kaws "github.com/kanisterio/kanister/pkg/aws"
@@ -110,3 +111,52 @@ func (s AWSEBSSuite) TestGetRegions(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(regions, NotNil)
 }
+
+func (s AWSEBSSuite) TestGroupInstanceID(c *C) {
+	attached := func(volID string, instanceIDs ...string) *ec2.Volume {
+		vol := &ec2.Volume{VolumeId: aws.String(volID)}
+		for _, id := range instanceIDs {
+			vol.Attachments = append(vol.Attachments, &ec2.VolumeAttachment{InstanceId: aws.String(id)})
+		}
+		return vol
+	}
+	id, err := groupInstanceID([]*ec2.Volume{attached("vol-1", "i-1"), attached("vol-2", "i-1")})
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, "i-1")
+
+	for _, vols := range [][]*ec2.Volume{
+		{attached("vol-1", "i-1"), attached("vol-2", "i-2")},
+		{attached("vol-1", "i-1"), attached("vol-2")},
+		{attached("vol-1", "i-1", "i-2")},
+	} {
+		_, err = groupInstanceID(vols)
+		c.Assert(errors.Cause(err), Equals, blockstorage.ErrGroupSnapshotNotSupported)
+	}
+}
+
+func (s AWSEBSSuite) TestGroupInstanceSpecification(c *C) {
+	bdm := func(device, volID string) *ec2.InstanceBlockDeviceMapping {
+		return &ec2.InstanceBlockDeviceMapping{
+			DeviceName: aws.String(device),
+			Ebs:        &ec2.EbsInstanceBlockDevice{VolumeId: aws.String(volID)},
+		}
+	}
+	instance := &ec2.Instance{
+		InstanceId:     aws.String("i-1"),
+		RootDeviceName: aws.String("/dev/xvda"),
+		BlockDeviceMappings: []*ec2.InstanceBlockDeviceMapping{
+			bdm("/dev/xvda", "vol-root"),
+			bdm("/dev/xvdb", "vol-1"),
+			bdm("/dev/xvdc", "vol-2"),
+			bdm("/dev/xvdd", "vol-3"),
+		},
+	}
+	is := groupInstanceSpecification(instance, []blockstorage.Volume{{ID: "vol-1"}, {ID: "vol-3"}})
+	c.Assert(aws.StringValue(is.InstanceId), Equals, "i-1")
+	c.Assert(aws.BoolValue(is.ExcludeBootVolume), Equals, true)
+	c.Assert(aws.StringValueSlice(is.ExcludeDataVolumeIds), DeepEquals, []string{"vol-2"})
+
+	is = groupInstanceSpecification(instance, []blockstorage.Volume{{ID: "vol-root"}, {ID: "vol-1"}, {ID: "vol-2"}, {ID: "vol-3"}})
+	c.Assert(aws.BoolValue(is.ExcludeBootVolume), Equals, false)
+	c.Assert(is.ExcludeDataVolumeIds, HasLen, 0)
+}
@@ -16,8 +16,14 @@ package blockstorage
 
 import (
 	"context"
+
+	"github.com/pkg/errors"
 )
 
+// ErrGroupSnapshotNotSupported is returned by GroupSnapshotter when the volumes
+// can not be snapshotted as a group, e.g. because they are not attached to the same node
+var ErrGroupSnapshotNotSupported = errors.New("Group snapshots are not supported for the volumes")
+
 // Provider abstracts actions on underlying storage
 type Provider interface {
 	// Type returns the underlying storage type
@@ -50,3 +56,19 @@ type RestoreTargeter interface {
 	// If not globally restorable, returns a map of the regions and zones to which snapshot can be restored.
 	SnapshotRestoreTargets(context.Context, *Snapshot) (global bool, regionsAndZones map[string][]string, err error)
 }
+
+// SnapshotCutWaiter is implemented by providers whose SnapshotCreate returns
+// before the point in time of the snapshot is established
+type SnapshotCutWaiter interface {
+	// SnapshotWaitForCut waits until the point in time of the snapshot is
+	// established. Writes to the volume after it returns are not captured.
+	SnapshotWaitForCut(ctx context.Context, snap *Snapshot) error
+}
+
+// GroupSnapshotter is implemented by providers which can snapshot multiple
+// volumes at the same point in time
+type GroupSnapshotter interface {
+	// SnapshotGroupCreate creates crash-consistent snapshots of the volumes.
+	// The snapshots are returned in the order of the volumes.
+	SnapshotGroupCreate(ctx context.Context, volumes []Volume, tags map[string]string) ([]*Snapshot, error)
+}
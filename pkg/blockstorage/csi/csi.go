@@ -0,0 +1,450 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package csi implements a blockstorage.Provider for volumes managed by any
+// CSI driver that supports VolumeSnapshots. Volumes are PersistentVolumeClaims
+// and snapshots are VolumeSnapshots; both are identified by "namespace/name".
+package csi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/strfmt"
+	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	kubevolume "github.com/kanisterio/kanister/pkg/kube/volume"
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+var _ blockstorage.Provider = (*CSIProvider)(nil)
+var _ blockstorage.SnapshotCutWaiter = (*CSIProvider)(nil)
+
+const (
+	// NamespaceKey is the config key for the namespace in which volumes are
+	// created by VolumeCreate, and to which VolumesList and SnapshotsList
+	// are restricted. All namespaces are used if it is not set.
+	NamespaceKey = "CSINamespace"
+	// VolumeSnapshotClassKey is the config key for the VolumeSnapshotClass
+	// used to create snapshots. If it is not set, the default
+	// VolumeSnapshotClass of the volume's CSI driver is used.
+	VolumeSnapshotClassKey = "CSIVolumeSnapshotClass"
+
+	// PVCNameTag is the tag used by VolumeCreateFromSnapshot to name the
+	// restored PersistentVolumeClaim
+	PVCNameTag = "pvcname"
+
+	// DriverAttr is the Volume attribute that holds the name of the CSI driver
+	DriverAttr = "driver"
+	// VolumeSnapshotClassAttr is the Volume attribute that holds the name of
+	// the VolumeSnapshotClass of a snapshot
+	VolumeSnapshotClassAttr = "volumeSnapshotClass"
+
+	defaultSnapshotClassAnnotation = "snapshot.storage.kubernetes.io/is-default-class"
+	snapshotNamePrefix             = "kanister-snapshot-"
+	pvcNamePrefix                  = "kanister-pvc-"
+	idSeparator                    = "/"
+)
+
+// cephDrivers are the CSI drivers for which volumes are of type blockstorage.TypeCeph
+var cephDrivers = []string{
+	"rbd.csi.ceph.com",
+	"cephfs.csi.ceph.com",
+}
+
+// CSIProvider provides blockstorage.Provider on top of the VolumeSnapshot APIs
+type CSIProvider struct {
+	storageType        blockstorage.Type
+	namespace          string
+	snapshotClass      string
+	groupSnapshotClass string
+	kubeCli            kubernetes.Interface
+	dynCli             dynamic.Interface
+	snapshotter        snapshot.Snapshotter
+}
+
+// NewProvider returns a CSI backed provider of the given storage type
+func NewProvider(storageType blockstorage.Type, config map[string]string) (blockstorage.Provider, error) {
+	kubeCli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create kubernetes client")
+	}
+	dynCli, err := kube.NewDynamicClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create dynamic client")
+	}
+	snapshotter, err := snapshot.NewSnapshotter(kubeCli, dynCli)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create snapshotter")
+	}
+	return newProvider(storageType, config, kubeCli, dynCli, snapshotter), nil
+}
+
+func newProvider(storageType blockstorage.Type, config map[string]string, kubeCli kubernetes.Interface, dynCli dynamic.Interface, snapshotter snapshot.Snapshotter) *CSIProvider {
+	return &CSIProvider{
+		storageType:        storageType,
+		namespace:          config[NamespaceKey],
+		snapshotClass:      config[VolumeSnapshotClassKey],
+		groupSnapshotClass: config[VolumeGroupSnapshotClassKey],
+		kubeCli:            kubeCli,
+		dynCli:             dynCli,
+		snapshotter:        snapshotter,
+	}
+}
+
+// StorageType returns the blockstorage type of volumes provisioned by the
+// given CSI driver
+func StorageType(driver string) blockstorage.Type {
+	for _, d := range cephDrivers {
+		if d == driver {
+			return blockstorage.TypeCeph
+		}
+	}
+	return blockstorage.TypeGeneric
+}
+
+// ID returns the identifier of the PersistentVolumeClaim or VolumeSnapshot
+// with the given namespace and name
+func ID(namespace, name string) string {
+	return namespace + idSeparator + name
+}
+
+// SplitID returns the namespace and name of a PersistentVolumeClaim or
+// VolumeSnapshot from its identifier
+func SplitID(id string) (string, string, error) {
+	s := strings.Split(id, idSeparator)
+	if len(s) != 2 || s[0] == "" || s[1] == "" {
+		return "", "", errors.Errorf("Invalid identifier %s, expected <namespace>/<name>", id)
+	}
+	return s[0], s[1], nil
+}
+
+// Type returns the storage type of the provider
+func (p *CSIProvider) Type() blockstorage.Type {
+	return p.storageType
+}
+
+// VolumeCreate creates a dynamically provisioned PersistentVolumeClaim using
+// volume.VolumeType as the storage class
+func (p *CSIProvider) VolumeCreate(ctx context.Context, volume blockstorage.Volume) (*blockstorage.Volume, error) {
+	namespace := p.namespace
+	if namespace == "" {
+		return nil, errors.Errorf("%s must be set to create volumes", NamespaceKey)
+	}
+	size, err := resource.ParseQuantity(fmt.Sprintf("%d", volume.SizeInBytes))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unable to parse volume size %d", volume.SizeInBytes)
+	}
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pvcNamePrefix,
+			Labels:       blockstorage.SanitizeTags(blockstorage.KeyValueToMap(volume.Tags)),
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if volume.VolumeType != "" {
+		pvc.Spec.StorageClassName = &volume.VolumeType
+	}
+	pvc, err = p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create PVC")
+	}
+	return p.volumeFromPVC(ctx, pvc, volume.Az), nil
+}
+
+// VolumeCreateFromSnapshot restores the VolumeSnapshot into a new
+// PersistentVolumeClaim in the namespace of the snapshot. The claim is named
+// after the PVCNameTag tag, if set.
+func (p *CSIProvider) VolumeCreateFromSnapshot(ctx context.Context, snapshot blockstorage.Snapshot, tags map[string]string) (*blockstorage.Volume, error) {
+	namespace, name, err := SplitID(snapshot.ID)
+	if err != nil {
+		return nil, err
+	}
+	args := &kubevolume.CreatePVCFromSnapshotArgs{
+		KubeCli:      p.kubeCli,
+		DynCli:       p.dynCli,
+		Namespace:    namespace,
+		VolumeName:   tags[PVCNameTag],
+		SnapshotName: name,
+		Labels:       blockstorage.SanitizeTags(ktags.GetTags(tags)),
+		GroupVersion: p.snapshotter.GroupVersion(ctx),
+	}
+	if snapshot.Volume != nil {
+		args.StorageClassName = snapshot.Volume.VolumeType
+	}
+	pvcName, err := kubevolume.CreatePVCFromSnapshot(ctx, args)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create PVC from snapshot %s", snapshot.ID)
+	}
+	var az string
+	if snapshot.Volume != nil {
+		az = snapshot.Volume.Az
+	}
+	return p.VolumeGet(ctx, ID(namespace, pvcName), az)
+}
+
+// VolumeDelete deletes the PersistentVolumeClaim
+func (p *CSIProvider) VolumeDelete(ctx context.Context, volume *blockstorage.Volume) error {
+	namespace, name, err := SplitID(volume.ID)
+	if err != nil {
+		return err
+	}
+	return kubevolume.DeletePVC(p.kubeCli, namespace, name)
+}
+
+// VolumeGet returns the PersistentVolumeClaim with the given identifier
+func (p *CSIProvider) VolumeGet(ctx context.Context, id string, zone string) (*blockstorage.Volume, error) {
+	namespace, name, err := SplitID(id)
+	if err != nil {
+		return nil, err
+	}
+	pvc, err := p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get PVC %s", id)
+	}
+	return p.volumeFromPVC(ctx, pvc, zone), nil
+}
+
+// SnapshotCopy is not supported, VolumeSnapshots are local to the cluster
+func (p *CSIProvider) SnapshotCopy(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
+	return nil, errors.Errorf("SnapshotCopy is not supported for storage type %s", p.storageType)
+}
+
+// SnapshotCopyWithArgs is not supported, VolumeSnapshots are local to the cluster
+func (p *CSIProvider) SnapshotCopyWithArgs(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot, args map[string]string) (*blockstorage.Snapshot, error) {
+	return nil, errors.Errorf("SnapshotCopyWithArgs is not supported for storage type %s", p.storageType)
+}
+
+// SnapshotCreate creates a VolumeSnapshot of the PersistentVolumeClaim. It
+// does not wait for the snapshot to be ready to use.
+func (p *CSIProvider) SnapshotCreate(ctx context.Context, volume blockstorage.Volume, tags map[string]string) (*blockstorage.Snapshot, error) {
+	namespace, pvcName, err := SplitID(volume.ID)
+	if err != nil {
+		return nil, err
+	}
+	snapshotClass, err := p.volumeSnapshotClass(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+	name := snapshotNamePrefix + rand.String(8)
+	if err := p.snapshotter.Create(ctx, name, namespace, pvcName, &snapshotClass, false, ktags.GetTags(tags)); err != nil {
+		return nil, err
+	}
+	snap, err := p.SnapshotGet(ctx, ID(namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	snap.Volume = &volume
+	return snap, nil
+}
+
+// SnapshotCreateWaitForCompletion waits until the VolumeSnapshot is ready to use
+func (p *CSIProvider) SnapshotCreateWaitForCompletion(ctx context.Context, snap *blockstorage.Snapshot) error {
+	namespace, name, err := SplitID(snap.ID)
+	if err != nil {
+		return err
+	}
+	return p.snapshotter.WaitOnReadyToUse(ctx, name, namespace)
+}
+
+// SnapshotWaitForCut waits until the VolumeSnapshot has been cut, which the
+// snapshot controller records as its creation time, or is ready to use
+func (p *CSIProvider) SnapshotWaitForCut(ctx context.Context, snap *blockstorage.Snapshot) error {
+	namespace, name, err := SplitID(snap.ID)
+	if err != nil {
+		return err
+	}
+	err = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		vs, err := p.snapshotter.Get(ctx, name, namespace)
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to get VolumeSnapshot %s", name)
+		}
+		if vs.Status == nil {
+			return false, nil
+		}
+		if vs.Status.Error != nil && vs.Status.Error.Message != nil {
+			return false, errors.Errorf("VolumeSnapshot %s failed: %s", name, *vs.Status.Error.Message)
+		}
+		ready := vs.Status.ReadyToUse != nil && *vs.Status.ReadyToUse
+		return vs.Status.CreationTime != nil || ready, nil
+	})
+	return errors.Wrapf(err, "VolumeSnapshot %s was not cut", name)
+}
+
+// SnapshotDelete deletes the VolumeSnapshot
+func (p *CSIProvider) SnapshotDelete(ctx context.Context, snap *blockstorage.Snapshot) error {
+	namespace, name, err := SplitID(snap.ID)
+	if err != nil {
+		return err
+	}
+	_, err = p.snapshotter.Delete(ctx, name, namespace)
+	return err
+}
+
+// SnapshotGet returns the VolumeSnapshot with the given identifier
+func (p *CSIProvider) SnapshotGet(ctx context.Context, id string) (*blockstorage.Snapshot, error) {
+	namespace, name, err := SplitID(id)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := p.snapshotter.Get(ctx, name, namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get snapshot %s", id)
+	}
+	return p.snapshotFromVolumeSnapshot(vs), nil
+}
+
+// SetTags sets the tags as labels on the PersistentVolumeClaim of a Volume.
+// VolumeSnapshots are labeled when they are created.
+func (p *CSIProvider) SetTags(ctx context.Context, resource interface{}, tags map[string]string) error {
+	vol, ok := resource.(*blockstorage.Volume)
+	if !ok {
+		return errors.Errorf("Unsupported resource type %T", resource)
+	}
+	namespace, name, err := SplitID(vol.ID)
+	if err != nil {
+		return err
+	}
+	pvc, err := p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get PVC %s", vol.ID)
+	}
+	pvc.SetLabels(ktags.Union(pvc.GetLabels(), blockstorage.SanitizeTags(tags)))
+	if _, err = p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "Failed to set tags on PVC %s", vol.ID)
+	}
+	vol.Tags = blockstorage.MapToKeyValue(pvc.GetLabels())
+	return nil
+}
+
+// VolumesList lists the PersistentVolumeClaims labeled with the tags
+func (p *CSIProvider) VolumesList(ctx context.Context, tags map[string]string, zone string) ([]*blockstorage.Volume, error) {
+	pvcs, err := p.kubeCli.CoreV1().PersistentVolumeClaims(p.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(blockstorage.SanitizeTags(tags)).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list PVCs")
+	}
+	vols := make([]*blockstorage.Volume, 0, len(pvcs.Items))
+	for i := range pvcs.Items {
+		vols = append(vols, p.volumeFromPVC(ctx, &pvcs.Items[i], zone))
+	}
+	return vols, nil
+}
+
+// SnapshotsList lists the VolumeSnapshots labeled with the tags
+func (p *CSIProvider) SnapshotsList(ctx context.Context, tags map[string]string) ([]*blockstorage.Snapshot, error) {
+	vss, err := p.snapshotter.List(ctx, p.namespace, blockstorage.SanitizeTags(tags))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list snapshots")
+	}
+	snaps := make([]*blockstorage.Snapshot, 0, len(vss.Items))
+	for i := range vss.Items {
+		snaps = append(snaps, p.snapshotFromVolumeSnapshot(&vss.Items[i]))
+	}
+	return snaps, nil
+}
+
+// volumeSnapshotClass returns the configured VolumeSnapshotClass or the
+// default one for the driver of the volume's storage class
+func (p *CSIProvider) volumeSnapshotClass(ctx context.Context, volume blockstorage.Volume) (string, error) {
+	if p.snapshotClass != "" {
+		return p.snapshotClass, nil
+	}
+	if volume.VolumeType == "" {
+		return "", errors.Errorf("Storage class of volume %s is unknown, %s must be set", volume.ID, VolumeSnapshotClassKey)
+	}
+	sc, err := p.snapshotter.GetVolumeSnapshotClass(ctx, defaultSnapshotClassAnnotation, "true", volume.VolumeType)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to find default VolumeSnapshotClass for storage class %s", volume.VolumeType)
+	}
+	return sc, nil
+}
+
+func (p *CSIProvider) volumeFromPVC(ctx context.Context, pvc *v1.PersistentVolumeClaim, zone string) *blockstorage.Volume {
+	vol := &blockstorage.Volume{
+		Az:           zone,
+		CreationTime: blockstorage.TimeStamp(strfmt.DateTime(pvc.CreationTimestamp.Time)),
+		ID:           ID(pvc.Namespace, pvc.Name),
+		Tags:         blockstorage.MapToKeyValue(pvc.GetLabels()),
+		Type:         p.storageType,
+		Attributes:   map[string]string{},
+	}
+	if pvc.Spec.StorageClassName != nil {
+		vol.VolumeType = *pvc.Spec.StorageClassName
+	}
+	size, ok := pvc.Status.Capacity[v1.ResourceStorage]
+	if !ok {
+		size = pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	}
+	vol.SizeInBytes = size.Value()
+	if pvc.Spec.VolumeName == "" {
+		return vol
+	}
+	// The driver is informational, the volume is still usable without it
+	pv, err := p.kubeCli.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err == nil && pv.Spec.CSI != nil {
+		vol.Attributes[DriverAttr] = pv.Spec.CSI.Driver
+	}
+	return vol
+}
+
+func (p *CSIProvider) snapshotFromVolumeSnapshot(vs *snapv1.VolumeSnapshot) *blockstorage.Snapshot {
+	snap := &blockstorage.Snapshot{
+		CreationTime: blockstorage.TimeStamp(strfmt.DateTime(vs.CreationTimestamp.Time)),
+		ID:           ID(vs.Namespace, vs.Name),
+		Tags:         blockstorage.MapToKeyValue(vs.GetLabels()),
+		Type:         p.storageType,
+		Volume: &blockstorage.Volume{
+			Type:       p.storageType,
+			Attributes: map[string]string{},
+		},
+	}
+	if vs.Status != nil {
+		if vs.Status.CreationTime != nil {
+			snap.CreationTime = blockstorage.TimeStamp(strfmt.DateTime(vs.Status.CreationTime.Time))
+		}
+		if vs.Status.RestoreSize != nil {
+			snap.SizeInBytes = vs.Status.RestoreSize.Value()
+			snap.Volume.SizeInBytes = snap.SizeInBytes
+		}
+	}
+	if vs.Spec.Source.PersistentVolumeClaimName != nil {
+		snap.Volume.ID = ID(vs.Namespace, *vs.Spec.Source.PersistentVolumeClaimName)
+	}
+	if vs.Spec.VolumeSnapshotClassName != nil {
+		snap.Volume.Attributes[VolumeSnapshotClassAttr] = *vs.Spec.VolumeSnapshotClassName
+	}
+	return snap
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type CSISuite struct{}
+
+var _ = Suite(&CSISuite{})
+
+const (
+	testNamespace     = "test-ns"
+	testPVC           = "test-pvc"
+	testStorageClass  = "test-sc"
+	testSnapshotClass = "test-vsc"
+)
+
+func (s *CSISuite) newTestProvider(c *C, config map[string]string) *CSIProvider {
+	ctx := context.Background()
+	sc := testStorageClass
+	cli := fake.NewSimpleClientset(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPVC,
+			Namespace: testNamespace,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &sc,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	})
+	dynCli := dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		snapshot.VolSnapGVR: "VolumeSnapshotList",
+	})
+	p := newProvider(blockstorage.TypeGeneric, config, cli, dynCli, snapshot.NewSnapshotStable(cli, dynCli))
+	_, err := p.VolumeGet(ctx, ID(testNamespace, testPVC), "")
+	c.Assert(err, IsNil)
+	return p
+}
+
+func (s *CSISuite) TestSplitID(c *C) {
+	for _, tc := range []struct {
+		id        string
+		namespace string
+		name      string
+		checker   Checker
+	}{
+		{id: "ns/name", namespace: "ns", name: "name", checker: IsNil},
+		{id: "name", checker: NotNil},
+		{id: "ns/", checker: NotNil},
+		{id: "a/b/c", checker: NotNil},
+	} {
+		ns, name, err := SplitID(tc.id)
+		c.Check(err, tc.checker)
+		c.Check(ns, Equals, tc.namespace)
+		c.Check(name, Equals, tc.name)
+	}
+}
+
+func (s *CSISuite) TestStorageType(c *C) {
+	c.Assert(StorageType("rbd.csi.ceph.com"), Equals, blockstorage.TypeCeph)
+	c.Assert(StorageType("driver.longhorn.io"), Equals, blockstorage.TypeGeneric)
+}
+
+func (s *CSISuite) TestVolumeGet(c *C) {
+	ctx := context.Background()
+	p := s.newTestProvider(c, map[string]string{})
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, testPVC), "zone")
+	c.Assert(err, IsNil)
+	c.Assert(vol.ID, Equals, ID(testNamespace, testPVC))
+	c.Assert(vol.Az, Equals, "zone")
+	c.Assert(vol.VolumeType, Equals, testStorageClass)
+	c.Assert(vol.SizeInBytes, Equals, int64(1024*1024*1024))
+	c.Assert(vol.Type, Equals, blockstorage.TypeGeneric)
+
+	_, err = p.VolumeGet(ctx, ID(testNamespace, "missing"), "")
+	c.Assert(err, NotNil)
+}
+
+func (s *CSISuite) TestSetTags(c *C) {
+	ctx := context.Background()
+	p := s.newTestProvider(c, map[string]string{})
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, testPVC), "")
+	c.Assert(err, IsNil)
+	err = p.SetTags(ctx, vol, map[string]string{"pvcname": testPVC})
+	c.Assert(err, IsNil)
+
+	vols, err := p.VolumesList(ctx, map[string]string{"pvcname": testPVC}, "")
+	c.Assert(err, IsNil)
+	c.Assert(vols, HasLen, 1)
+	c.Assert(vols[0].ID, Equals, vol.ID)
+
+	err = p.SetTags(ctx, &blockstorage.Snapshot{}, nil)
+	c.Assert(err, NotNil)
+}
+
+func (s *CSISuite) TestSnapshotCreateGetDelete(c *C) {
+	ctx := context.Background()
+	p := s.newTestProvider(c, map[string]string{VolumeSnapshotClassKey: testSnapshotClass})
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, testPVC), "")
+	c.Assert(err, IsNil)
+
+	snap, err := p.SnapshotCreate(ctx, *vol, map[string]string{"pvcname": testPVC})
+	c.Assert(err, IsNil)
+	c.Assert(snap.Volume.VolumeType, Equals, testStorageClass)
+	ns, _, err := SplitID(snap.ID)
+	c.Assert(err, IsNil)
+	c.Assert(ns, Equals, testNamespace)
+
+	got, err := p.SnapshotGet(ctx, snap.ID)
+	c.Assert(err, IsNil)
+	c.Assert(got.ID, Equals, snap.ID)
+	c.Assert(got.Volume.ID, Equals, vol.ID)
+	c.Assert(got.Volume.Attributes[VolumeSnapshotClassAttr], Equals, testSnapshotClass)
+	c.Assert(blockstorage.KeyValueToMap(got.Tags)["pvcname"], Equals, testPVC)
+
+	snaps, err := p.SnapshotsList(ctx, map[string]string{"pvcname": testPVC})
+	c.Assert(err, IsNil)
+	c.Assert(snaps, HasLen, 1)
+	snaps, err = p.SnapshotsList(ctx, map[string]string{"pvcname": "other"})
+	c.Assert(err, IsNil)
+	c.Assert(snaps, HasLen, 0)
+
+	err = p.SnapshotDelete(ctx, snap)
+	c.Assert(err, IsNil)
+	_, err = p.SnapshotGet(ctx, snap.ID)
+	c.Assert(err, NotNil)
+}
+
+func (s *CSISuite) TestSnapshotWaitForCut(c *C) {
+	ctx := context.Background()
+	p := s.newTestProvider(c, map[string]string{VolumeSnapshotClassKey: testSnapshotClass})
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, testPVC), "")
+	c.Assert(err, IsNil)
+	snap, err := p.SnapshotCreate(ctx, *vol, nil)
+	c.Assert(err, IsNil)
+
+	// The snapshot controller did not cut the snapshot yet
+	tctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	c.Assert(p.SnapshotWaitForCut(tctx, snap), NotNil)
+
+	_, name, err := SplitID(snap.ID)
+	c.Assert(err, IsNil)
+	vs, err := p.dynCli.Resource(snapshot.VolSnapGVR).Namespace(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	err = unstructured.SetNestedField(vs.Object, time.Now().UTC().Format(time.RFC3339), "status", "creationTime")
+	c.Assert(err, IsNil)
+	_, err = p.dynCli.Resource(snapshot.VolSnapGVR).Namespace(testNamespace).Update(ctx, vs, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(p.SnapshotWaitForCut(ctx, snap), IsNil)
+}
+
+func (s *CSISuite) TestSnapshotCopyUnsupported(c *C) {
+	p := s.newTestProvider(c, map[string]string{})
+	_, err := p.SnapshotCopy(context.Background(), blockstorage.Snapshot{}, blockstorage.Snapshot{})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,394 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csi
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+var _ blockstorage.GroupSnapshotter = (*CSIProvider)(nil)
+
+const (
+	// VolumeGroupSnapshotClassKey is the config key for the
+	// VolumeGroupSnapshotClass used to create group snapshots. If it is not
+	// set, the default VolumeGroupSnapshotClass of the CSI driver is used.
+	VolumeGroupSnapshotClassKey = "CSIVolumeGroupSnapshotClass"
+
+	// GroupSnapshotLabel is set on the PersistentVolumeClaims selected by a
+	// VolumeGroupSnapshot while it is created
+	GroupSnapshotLabel = "kanister.io/group-snapshot"
+
+	defaultGroupSnapshotClassAnnotation = "groupsnapshot.storage.kubernetes.io/is-default-class"
+	groupSnapshotNamePrefix             = "kanister-group-snapshot-"
+)
+
+var (
+	// VolumeGroupSnapshotGVR specifies GVR schema for VolumeGroupSnapshots
+	VolumeGroupSnapshotGVR = schema.GroupVersionResource{Group: "groupsnapshot.storage.k8s.io", Version: "v1alpha1", Resource: "volumegroupsnapshots"}
+	// VolumeGroupSnapshotContentGVR specifies GVR schema for VolumeGroupSnapshotContents
+	VolumeGroupSnapshotContentGVR = schema.GroupVersionResource{Group: "groupsnapshot.storage.k8s.io", Version: "v1alpha1", Resource: "volumegroupsnapshotcontents"}
+	// VolumeGroupSnapshotClassGVR specifies GVR schema for VolumeGroupSnapshotClasses
+	VolumeGroupSnapshotClassGVR = schema.GroupVersionResource{Group: "groupsnapshot.storage.k8s.io", Version: "v1alpha1", Resource: "volumegroupsnapshotclasses"}
+)
+
+// SnapshotGroupCreate creates a VolumeGroupSnapshot of PersistentVolumeClaims
+// in the same namespace and waits until it is ready to use. The returned
+// snapshots are the VolumeSnapshots created for each claim. The
+// VolumeGroupSnapshot and its VolumeSnapshots are deleted if it fails.
+func (p *CSIProvider) SnapshotGroupCreate(ctx context.Context, volumes []blockstorage.Volume, tags map[string]string) (snaps []*blockstorage.Snapshot, err error) {
+	if len(volumes) == 0 {
+		return nil, errors.New("No volumes to snapshot")
+	}
+	var namespace string
+	for _, volume := range volumes {
+		ns, _, err := SplitID(volume.ID)
+		if err != nil {
+			return nil, err
+		}
+		if namespace != "" && ns != namespace {
+			return nil, errors.Wrap(blockstorage.ErrGroupSnapshotNotSupported, "Volumes are in different namespaces")
+		}
+		namespace = ns
+	}
+	class, err := p.volumeGroupSnapshotClass(ctx, volumes[0])
+	if err != nil {
+		return nil, err
+	}
+
+	name := groupSnapshotNamePrefix + rand.String(8)
+	// VolumeGroupSnapshots select the claims by label
+	defer func() {
+		if err := p.labelGroupClaims(ctx, volumes, ""); err != nil {
+			log.WithError(err).Print("Failed to remove group snapshot label", field.M{"VolumeGroupSnapshot": name})
+		}
+	}()
+	if err := p.labelGroupClaims(ctx, volumes, name); err != nil {
+		return nil, err
+	}
+
+	labels := blockstorage.SanitizeTags(ktags.GetTags(tags))
+	vgs := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": VolumeGroupSnapshotGVR.GroupVersion().String(),
+			"kind":       "VolumeGroupSnapshot",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespace,
+				"labels":    toInterfaceMap(labels),
+			},
+			"spec": map[string]interface{}{
+				"volumeGroupSnapshotClassName": class,
+				"source": map[string]interface{}{
+					"selector": map[string]interface{}{
+						"matchLabels": map[string]interface{}{
+							GroupSnapshotLabel: name,
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := p.dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(namespace).Create(ctx, vgs, metav1.CreateOptions{}); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create VolumeGroupSnapshot %s", name)
+	}
+	var members []groupSnapshotMember
+	defer func() {
+		if err != nil {
+			p.deleteGroupSnapshot(namespace, name, members)
+		}
+	}()
+	if vgs, err = p.waitOnGroupSnapshot(ctx, namespace, name); err != nil {
+		return nil, err
+	}
+	members = groupSnapshotMembers(vgs)
+	claims, err := p.groupSnapshotClaims(ctx, namespace, vgs, members, volumes)
+	if err != nil {
+		return nil, err
+	}
+
+	byVolume := make(map[string]*blockstorage.Snapshot, len(members))
+	for _, m := range members {
+		if err = p.labelVolumeSnapshot(ctx, namespace, m.snapshot, labels); err != nil {
+			return nil, err
+		}
+		var snap *blockstorage.Snapshot
+		if snap, err = p.SnapshotGet(ctx, ID(namespace, m.snapshot)); err != nil {
+			return nil, err
+		}
+		byVolume[claims[m.snapshot]] = snap
+	}
+	snaps = make([]*blockstorage.Snapshot, 0, len(volumes))
+	for i := range volumes {
+		snap, ok := byVolume[volumes[i].ID]
+		if !ok {
+			err = errors.Errorf("No snapshot created for volume %s by VolumeGroupSnapshot %s", volumes[i].ID, name)
+			return nil, err
+		}
+		volume := volumes[i]
+		snap.Volume = &volume
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// deleteGroupSnapshot deletes the VolumeGroupSnapshot and its VolumeSnapshots
+// after a failure. It uses a new context, since the failure may be the
+// expiration of the context of the request.
+func (p *CSIProvider) deleteGroupSnapshot(namespace, name string, members []groupSnapshotMember) {
+	ctx := context.Background()
+	if err := p.dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		log.WithError(err).Print("Failed to delete VolumeGroupSnapshot", field.M{"VolumeGroupSnapshot": name})
+	}
+	for _, m := range members {
+		if _, err := p.snapshotter.Delete(ctx, m.snapshot, namespace); err != nil {
+			log.WithError(err).Print("Failed to delete VolumeSnapshot of VolumeGroupSnapshot", field.M{"VolumeGroupSnapshot": name, "VolumeSnapshot": m.snapshot})
+		}
+	}
+}
+
+// volumeGroupSnapshotClass returns the configured VolumeGroupSnapshotClass or
+// the default one for the CSI driver of the volume
+func (p *CSIProvider) volumeGroupSnapshotClass(ctx context.Context, volume blockstorage.Volume) (string, error) {
+	if p.groupSnapshotClass != "" {
+		return p.groupSnapshotClass, nil
+	}
+	classes, err := p.dynCli.Resource(VolumeGroupSnapshotClassGVR).List(ctx, metav1.ListOptions{})
+	if apierrors.IsNotFound(err) {
+		return "", errors.Wrap(blockstorage.ErrGroupSnapshotNotSupported, "VolumeGroupSnapshots are not available in the cluster")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to list VolumeGroupSnapshotClasses")
+	}
+	driver := volume.Attributes[DriverAttr]
+	for _, class := range classes.Items {
+		d, _, _ := unstructured.NestedString(class.Object, "driver")
+		if d == driver && class.GetAnnotations()[defaultGroupSnapshotClassAnnotation] == "true" {
+			return class.GetName(), nil
+		}
+	}
+	return "", errors.Wrapf(blockstorage.ErrGroupSnapshotNotSupported, "No default VolumeGroupSnapshotClass found for driver %s", driver)
+}
+
+// waitOnGroupSnapshot waits until the VolumeGroupSnapshot is ready to use and
+// returns it
+func (p *CSIProvider) waitOnGroupSnapshot(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+	var vgs *unstructured.Unstructured
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		vgs, err = p.dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to get VolumeGroupSnapshot %s", name)
+		}
+		if msg, ok, _ := unstructured.NestedString(vgs.Object, "status", "error", "message"); ok && msg != "" {
+			return false, errors.Errorf("VolumeGroupSnapshot %s failed: %s", name, msg)
+		}
+		ready, _, _ := unstructured.NestedBool(vgs.Object, "status", "readyToUse")
+		return ready, nil
+	})
+	return vgs, errors.Wrapf(err, "VolumeGroupSnapshot %s did not complete", name)
+}
+
+// groupSnapshotMember is a VolumeSnapshot of a VolumeGroupSnapshot and the
+// claim it was taken from, if the status of the group pairs them
+type groupSnapshotMember struct {
+	snapshot string
+	pvc      string
+}
+
+// groupSnapshotMembers returns the VolumeSnapshots listed in the status of a
+// VolumeGroupSnapshot
+func groupSnapshotMembers(vgs *unstructured.Unstructured) []groupSnapshotMember {
+	var members []groupSnapshotMember
+	// Later API versions pair each claim with its snapshot
+	refs, _, _ := unstructured.NestedSlice(vgs.Object, "status", "pvcVolumeSnapshotRefList")
+	for _, ref := range refs {
+		if m, ok := ref.(map[string]interface{}); ok {
+			snapshot, _, _ := unstructured.NestedString(m, "volumeSnapshotRef", "name")
+			pvc, _, _ := unstructured.NestedString(m, "persistentVolumeClaimRef", "name")
+			if snapshot != "" {
+				members = append(members, groupSnapshotMember{snapshot: snapshot, pvc: pvc})
+			}
+		}
+	}
+	if len(members) != 0 {
+		return members
+	}
+	refs, _, _ = unstructured.NestedSlice(vgs.Object, "status", "volumeSnapshotRefList")
+	for _, ref := range refs {
+		if m, ok := ref.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				members = append(members, groupSnapshotMember{snapshot: name})
+			}
+		}
+	}
+	return members
+}
+
+// groupSnapshotClaims returns the IDs of the volumes of the VolumeSnapshots of
+// a VolumeGroupSnapshot. The snapshot controller creates the VolumeSnapshots
+// from their VolumeSnapshotContents, so if the status of the group does not
+// pair them with their claims, they are paired through the handles of the
+// snapshots and volumes listed by the VolumeGroupSnapshotContent.
+func (p *CSIProvider) groupSnapshotClaims(ctx context.Context, namespace string, vgs *unstructured.Unstructured, members []groupSnapshotMember, volumes []blockstorage.Volume) (map[string]string, error) {
+	claims := make(map[string]string, len(members))
+	paired := true
+	for _, m := range members {
+		if m.pvc == "" {
+			paired = false
+			break
+		}
+		claims[m.snapshot] = ID(namespace, m.pvc)
+	}
+	if paired {
+		return claims, nil
+	}
+
+	contentName, _, _ := unstructured.NestedString(vgs.Object, "status", "boundVolumeGroupSnapshotContentName")
+	if contentName == "" {
+		return nil, errors.Errorf("VolumeGroupSnapshot %s is not bound to a VolumeGroupSnapshotContent", vgs.GetName())
+	}
+	vgsc, err := p.dynCli.Resource(VolumeGroupSnapshotContentGVR).Get(ctx, contentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get VolumeGroupSnapshotContent %s", contentName)
+	}
+	volumeHandles := make(map[string]string)
+	pairs, _, _ := unstructured.NestedSlice(vgsc.Object, "status", "volumeSnapshotHandlePairList")
+	for _, pair := range pairs {
+		if m, ok := pair.(map[string]interface{}); ok {
+			snapshotHandle, _, _ := unstructured.NestedString(m, "snapshotHandle")
+			volumeHandle, _, _ := unstructured.NestedString(m, "volumeHandle")
+			volumeHandles[snapshotHandle] = volumeHandle
+		}
+	}
+
+	volumeIDs := make(map[string]string, len(volumes))
+	for _, volume := range volumes {
+		handle, err := p.volumeHandle(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		volumeIDs[handle] = volume.ID
+	}
+
+	vsGVR := p.snapshotter.GroupVersion(ctx).WithResource("volumesnapshots")
+	vscGVR := p.snapshotter.GroupVersion(ctx).WithResource("volumesnapshotcontents")
+	for _, m := range members {
+		vs, err := p.dynCli.Resource(vsGVR).Namespace(namespace).Get(ctx, m.snapshot, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get VolumeSnapshot %s", m.snapshot)
+		}
+		vscName, _, _ := unstructured.NestedString(vs.Object, "status", "boundVolumeSnapshotContentName")
+		if vscName == "" {
+			vscName, _, _ = unstructured.NestedString(vs.Object, "spec", "source", "volumeSnapshotContentName")
+		}
+		vsc, err := p.dynCli.Resource(vscGVR).Get(ctx, vscName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get VolumeSnapshotContent %s of VolumeSnapshot %s", vscName, m.snapshot)
+		}
+		snapshotHandle, _, _ := unstructured.NestedString(vsc.Object, "status", "snapshotHandle")
+		if id, ok := volumeIDs[volumeHandles[snapshotHandle]]; ok {
+			claims[m.snapshot] = id
+		}
+	}
+	return claims, nil
+}
+
+// volumeHandle returns the CSI handle of the PersistentVolume of a claim
+func (p *CSIProvider) volumeHandle(ctx context.Context, volume blockstorage.Volume) (string, error) {
+	namespace, name, err := SplitID(volume.ID)
+	if err != nil {
+		return "", err
+	}
+	pvc, err := p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get PVC %s", volume.ID)
+	}
+	pv, err := p.kubeCli.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get PV of PVC %s", volume.ID)
+	}
+	if pv.Spec.CSI == nil {
+		return "", errors.Errorf("PV %s of PVC %s is not a CSI volume", pv.GetName(), volume.ID)
+	}
+	return pv.Spec.CSI.VolumeHandle, nil
+}
+
+func (p *CSIProvider) labelVolumeSnapshot(ctx context.Context, namespace, name string, labels map[string]string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode labels")
+	}
+	gvr := p.snapshotter.GroupVersion(ctx).WithResource("volumesnapshots")
+	if _, err = p.dynCli.Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return errors.Wrapf(err, "Failed to label VolumeSnapshot %s", name)
+	}
+	return nil
+}
+
+// labelGroupClaims sets the GroupSnapshotLabel of the claims to group or
+// removes it if group is empty
+func (p *CSIProvider) labelGroupClaims(ctx context.Context, volumes []blockstorage.Volume, group string) error {
+	for _, volume := range volumes {
+		namespace, name, err := SplitID(volume.ID)
+		if err != nil {
+			return err
+		}
+		pvc, err := p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get PVC %s", volume.ID)
+		}
+		labels := pvc.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		if group == "" {
+			delete(labels, GroupSnapshotLabel)
+		} else {
+			labels[GroupSnapshotLabel] = group
+		}
+		pvc.SetLabels(labels)
+		if _, err = p.kubeCli.CoreV1().PersistentVolumeClaims(namespace).Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+			return errors.Wrapf(err, "Failed to label PVC %s", volume.ID)
+		}
+	}
+	return nil
+}
+
+func toInterfaceMap(m map[string]string) map[string]interface{} {
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
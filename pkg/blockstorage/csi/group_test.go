@@ -0,0 +1,295 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csi
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+)
+
+type GroupSuite struct{}
+
+var _ = Suite(&GroupSuite{})
+
+const (
+	testGroupSnapshotClass = "test-vgsc"
+	failureUnpaired        = "unpaired"
+)
+
+func (s *GroupSuite) newProvider(c *C, pvcs ...string) (*CSIProvider, dynamic.Interface) {
+	objs := make([]runtime.Object, 0, 2*len(pvcs))
+	for _, name := range pvcs {
+		objs = append(objs, &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: testNamespace,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{VolumeName: "pv-" + name},
+		}, &v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv-" + name},
+			Spec: v1.PersistentVolumeSpec{
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{Driver: "test.csi.driver", VolumeHandle: "volume-" + name},
+				},
+			},
+		})
+	}
+	cli := fake.NewSimpleClientset(objs...)
+	dynCli := dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		snapshot.VolSnapGVR:           "VolumeSnapshotList",
+		snapshot.VolSnapContentGVR:    "VolumeSnapshotContentList",
+		VolumeGroupSnapshotGVR:        "VolumeGroupSnapshotList",
+		VolumeGroupSnapshotContentGVR: "VolumeGroupSnapshotContentList",
+		VolumeGroupSnapshotClassGVR:   "VolumeGroupSnapshotClassList",
+	})
+	config := map[string]string{VolumeSnapshotClassKey: testSnapshotClass}
+	return newProvider(blockstorage.TypeGeneric, config, cli, dynCli, snapshot.NewSnapshotStable(cli, dynCli)), dynCli
+}
+
+func (s *GroupSuite) createDefaultClass(c *C, dynCli dynamic.Interface, driver string) {
+	class := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": VolumeGroupSnapshotClassGVR.GroupVersion().String(),
+			"kind":       "VolumeGroupSnapshotClass",
+			"metadata": map[string]interface{}{
+				"name": testGroupSnapshotClass,
+				"annotations": map[string]interface{}{
+					defaultGroupSnapshotClassAnnotation: "true",
+				},
+			},
+			"driver": driver,
+		},
+	}
+	_, err := dynCli.Resource(VolumeGroupSnapshotClassGVR).Create(context.Background(), class, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+}
+
+// completeGroupSnapshot acts as the snapshot controller. Like the controller
+// of the v1alpha1 API, it creates the VolumeSnapshotContents of the labeled
+// claims, the VolumeSnapshots bound to them and the VolumeGroupSnapshotContent
+// pairing the handles of the volumes and snapshots. If pvcRefs is set, the
+// status of the group pairs the claims and snapshots like later API versions.
+// If failure is set, the group fails with that message, or if it is
+// failureUnpaired, the VolumeGroupSnapshotContent does not pair the handles.
+func (s *GroupSuite) completeGroupSnapshot(ctx context.Context, p *CSIProvider, dynCli dynamic.Interface, pvcRefs bool, failure string) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+		vgsList, err := dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(testNamespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		if len(vgsList.Items) == 0 {
+			continue
+		}
+		vgs := vgsList.Items[0]
+		class, _, _ := unstructured.NestedString(vgs.Object, "spec", "volumeGroupSnapshotClassName")
+		if class != testGroupSnapshotClass {
+			return errors.Errorf("Unexpected class %s", class)
+		}
+		if failure != "" && failure != failureUnpaired {
+			if err := unstructured.SetNestedField(vgs.Object, failure, "status", "error", "message"); err != nil {
+				return err
+			}
+			_, err = dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(testNamespace).Update(ctx, &vgs, metav1.UpdateOptions{})
+			return err
+		}
+		pvcs, err := p.kubeCli.CoreV1().PersistentVolumeClaims(testNamespace).List(ctx, metav1.ListOptions{
+			LabelSelector: GroupSnapshotLabel + "=" + vgs.GetName(),
+		})
+		if err != nil {
+			return err
+		}
+		var refs, pairs []interface{}
+		for _, pvc := range pvcs.Items {
+			name := "snap-" + pvc.Name
+			contentName := "snapcontent-" + pvc.Name
+			snapshotHandle := "snapshot-" + pvc.Name
+			vsc := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": snapshot.VolSnapContentGVR.GroupVersion().String(),
+				"kind":       "VolumeSnapshotContent",
+				"metadata":   map[string]interface{}{"name": contentName},
+				"spec": map[string]interface{}{
+					"driver": "test.csi.driver",
+					"source": map[string]interface{}{"snapshotHandle": snapshotHandle},
+				},
+				"status": map[string]interface{}{"snapshotHandle": snapshotHandle, "readyToUse": true},
+			}}
+			if _, err := dynCli.Resource(snapshot.VolSnapContentGVR).Create(ctx, vsc, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			vs := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": snapshot.VolSnapGVR.GroupVersion().String(),
+				"kind":       "VolumeSnapshot",
+				"metadata":   map[string]interface{}{"name": name, "namespace": testNamespace},
+				"spec": map[string]interface{}{
+					"source": map[string]interface{}{"volumeSnapshotContentName": contentName},
+				},
+				"status": map[string]interface{}{"boundVolumeSnapshotContentName": contentName, "readyToUse": true},
+			}}
+			if _, err := dynCli.Resource(snapshot.VolSnapGVR).Namespace(testNamespace).Create(ctx, vs, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			pv, err := p.kubeCli.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if pvcRefs {
+				refs = append(refs, map[string]interface{}{
+					"persistentVolumeClaimRef": map[string]interface{}{"name": pvc.Name},
+					"volumeSnapshotRef":        map[string]interface{}{"name": name},
+				})
+			} else {
+				refs = append(refs, map[string]interface{}{"name": name})
+			}
+			if failure == failureUnpaired {
+				continue
+			}
+			pairs = append(pairs, map[string]interface{}{
+				"volumeHandle":   pv.Spec.CSI.VolumeHandle,
+				"snapshotHandle": snapshotHandle,
+			})
+		}
+		contentName := "groupcontent-" + vgs.GetName()
+		vgsc := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": VolumeGroupSnapshotContentGVR.GroupVersion().String(),
+			"kind":       "VolumeGroupSnapshotContent",
+			"metadata":   map[string]interface{}{"name": contentName},
+			"status":     map[string]interface{}{"volumeSnapshotHandlePairList": pairs, "readyToUse": true},
+		}}
+		if _, err := dynCli.Resource(VolumeGroupSnapshotContentGVR).Create(ctx, vgsc, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		refList := "volumeSnapshotRefList"
+		if pvcRefs {
+			refList = "pvcVolumeSnapshotRefList"
+		}
+		if err := unstructured.SetNestedSlice(vgs.Object, refs, "status", refList); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(vgs.Object, contentName, "status", "boundVolumeGroupSnapshotContentName"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(vgs.Object, true, "status", "readyToUse"); err != nil {
+			return err
+		}
+		_, err = dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(testNamespace).Update(ctx, &vgs, metav1.UpdateOptions{})
+		return err
+	}
+}
+
+func (s *GroupSuite) TestSnapshotGroupCreate(c *C) {
+	for _, pvcRefs := range []bool{false, true} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		p, dynCli := s.newProvider(c, "pvc-1", "pvc-2", "pvc-3")
+		s.createDefaultClass(c, dynCli, "test.csi.driver")
+
+		var volumes []blockstorage.Volume
+		for _, name := range []string{"pvc-2", "pvc-1"} {
+			vol, err := p.VolumeGet(ctx, ID(testNamespace, name), "")
+			c.Assert(err, IsNil)
+			volumes = append(volumes, *vol)
+		}
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- s.completeGroupSnapshot(ctx, p, dynCli, pvcRefs, "") }()
+		snaps, err := p.SnapshotGroupCreate(ctx, volumes, map[string]string{"testkey": "testval"})
+		c.Assert(err, IsNil)
+		c.Assert(<-errCh, IsNil)
+
+		c.Assert(snaps, HasLen, 2)
+		c.Assert(snaps[0].ID, Equals, ID(testNamespace, "snap-pvc-2"))
+		c.Assert(snaps[1].ID, Equals, ID(testNamespace, "snap-pvc-1"))
+		c.Assert(snaps[0].Volume.ID, Equals, volumes[0].ID)
+		c.Assert(blockstorage.KeyValueToMap(snaps[0].Tags)["testkey"], Equals, "testval")
+
+		// The claims are no longer selected by the group
+		for _, name := range []string{"pvc-1", "pvc-2"} {
+			pvc, err := p.kubeCli.CoreV1().PersistentVolumeClaims(testNamespace).Get(ctx, name, metav1.GetOptions{})
+			c.Assert(err, IsNil)
+			_, ok := pvc.GetLabels()[GroupSnapshotLabel]
+			c.Assert(ok, Equals, false)
+		}
+	}
+}
+
+func (s *GroupSuite) TestSnapshotGroupCreateCleanup(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	p, dynCli := s.newProvider(c, "pvc-1", "pvc-2")
+	s.createDefaultClass(c, dynCli, "test.csi.driver")
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, "pvc-1"), "")
+	c.Assert(err, IsNil)
+
+	// The group fails
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.completeGroupSnapshot(ctx, p, dynCli, false, "out of quota") }()
+	_, err = p.SnapshotGroupCreate(ctx, []blockstorage.Volume{*vol}, nil)
+	c.Assert(err, ErrorMatches, ".*failed: out of quota.*")
+	c.Assert(<-errCh, IsNil)
+	vgsList, err := dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(testNamespace).List(ctx, metav1.ListOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(vgsList.Items, HasLen, 0)
+
+	// The snapshots cannot be paired with the claims
+	go func() { errCh <- s.completeGroupSnapshot(ctx, p, dynCli, false, failureUnpaired) }()
+	_, err = p.SnapshotGroupCreate(ctx, []blockstorage.Volume{*vol}, nil)
+	c.Assert(err, ErrorMatches, "No snapshot created for volume test-ns/pvc-1 by VolumeGroupSnapshot .*")
+	c.Assert(<-errCh, IsNil)
+	vgsList, err = dynCli.Resource(VolumeGroupSnapshotGVR).Namespace(testNamespace).List(ctx, metav1.ListOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(vgsList.Items, HasLen, 0)
+	vsList, err := dynCli.Resource(snapshot.VolSnapGVR).Namespace(testNamespace).List(ctx, metav1.ListOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(vsList.Items, HasLen, 0)
+}
+
+func (s *GroupSuite) TestSnapshotGroupCreateNotSupported(c *C) {
+	ctx := context.Background()
+	p, dynCli := s.newProvider(c, "pvc-1")
+	vol, err := p.VolumeGet(ctx, ID(testNamespace, "pvc-1"), "")
+	c.Assert(err, IsNil)
+
+	// No default class for the driver
+	_, err = p.SnapshotGroupCreate(ctx, []blockstorage.Volume{*vol}, nil)
+	c.Assert(errors.Cause(err), Equals, blockstorage.ErrGroupSnapshotNotSupported)
+	s.createDefaultClass(c, dynCli, "other.csi.driver")
+	_, err = p.SnapshotGroupCreate(ctx, []blockstorage.Volume{*vol}, nil)
+	c.Assert(errors.Cause(err), Equals, blockstorage.ErrGroupSnapshotNotSupported)
+
+	// Volumes in different namespaces
+	other := *vol
+	other.ID = ID("other-ns", "pvc-1")
+	_, err = p.SnapshotGroupCreate(ctx, []blockstorage.Volume{*vol, other}, nil)
+	c.Assert(errors.Cause(err), Equals, blockstorage.ErrGroupSnapshotNotSupported)
+}
@@ -174,14 +174,133 @@ func (s *GpdStorage) VolumeDelete(ctx context.Context, volume *blockstorage.Volu
 	return s.waitOnOperation(ctx, op, volume.Az)
 }
 
-// SnapshotCopy is part of blockstorage.Provider
+// SnapshotCopy copies snapshot 'from' to 'to' within the same project. GCP snapshots
+// are global resources, 'to.Region' only sets the storage location of the copy.
 func (s *GpdStorage) SnapshotCopy(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
-	return nil, errors.Errorf("Not implemented")
+	return s.snapshotCopy(ctx, s, from, to)
 }
 
-// SnapshotCopyWithArgs is part of blockstorage.Provider
+// SnapshotCopyWithArgs func: args map should contain non-empty projectID(GoogleProjectID)
+// and serviceKey(GoogleServiceKey) of the destination project. The destination service
+// account needs read access to the source snapshot.
 func (s *GpdStorage) SnapshotCopyWithArgs(ctx context.Context, from blockstorage.Snapshot, to blockstorage.Snapshot, args map[string]string) (*blockstorage.Snapshot, error) {
-	return nil, errors.New("Copy Snapshot with Args not implemented")
+	if args[blockstorage.GoogleProjectID] == "" || args[blockstorage.GoogleServiceKey] == "" {
+		return nil, errors.Errorf("Required args %s and %s for snapshot copy not available", blockstorage.GoogleProjectID, blockstorage.GoogleServiceKey)
+	}
+	dst, err := NewProvider(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to initialize destination project provider")
+	}
+	return s.snapshotCopy(ctx, dst.(*GpdStorage), from, to)
+}
+
+// snapshotCopy copies 'from' into the project of 'dst'. GCP cannot copy snapshots
+// directly, so the snapshot is restored into a temporary disk which is then snapshotted.
+func (s *GpdStorage) snapshotCopy(ctx context.Context, dst *GpdStorage, from blockstorage.Snapshot, to blockstorage.Snapshot) (*blockstorage.Snapshot, error) {
+	if to.ID != "" {
+		return nil, errors.Errorf("Snapshot %v destination ID must be empty", to)
+	}
+	snap, err := s.service.Snapshots.Get(s.project, from.ID).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get snapshot %s", from.ID)
+	}
+	diskZone, err := dst.copyZone(ctx, snap.SourceDisk, to.Region)
+	if err != nil {
+		return nil, err
+	}
+	diskID, err := uuid.NewV1()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create UUID")
+	}
+	disk := &compute.Disk{
+		Name:           fmt.Sprintf(volumeNameFmt, diskID.String()),
+		SizeGb:         snap.DiskSizeGb,
+		Labels:         snap.Labels,
+		SourceSnapshot: snap.SelfLink,
+	}
+	op, err := dst.service.Disks.Insert(dst.project, diskZone, disk).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create temporary disk from snapshot %s", from.ID)
+	}
+	// The disk is deleted even if waiting for its creation fails. The
+	// context may be done, so it is not used.
+	defer func() {
+		if err := dst.VolumeDelete(context.Background(), &blockstorage.Volume{ID: disk.Name, Az: diskZone}); err != nil {
+			log.WithError(err).Print("Failed to delete temporary disk", field.M{"VolumeID": disk.Name, "Zone": diskZone})
+		}
+	}()
+	if err = dst.waitOnOperation(ctx, op, diskZone); err != nil {
+		return nil, err
+	}
+
+	snapID, err := uuid.NewV1()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create UUID")
+	}
+	rb := &compute.Snapshot{
+		Name:        fmt.Sprintf(snapshotNameFmt, snapID.String()),
+		Description: "Copy of " + from.ID,
+		Labels:      snap.Labels,
+	}
+	if to.Region != "" {
+		rb.StorageLocations = []string{to.Region}
+	}
+	op, err = dst.service.Disks.CreateSnapshot(dst.project, diskZone, disk.Name, rb).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to copy snapshot %s", from.ID)
+	}
+	if err = dst.waitOnOperation(ctx, op, diskZone); err != nil {
+		return nil, err
+	}
+	if err = dst.waitOnSnapshotID(ctx, rb.Name); err != nil {
+		return nil, errors.Wrapf(err, "Snapshot %s did not complete", rb.Name)
+	}
+	rs, err := dst.SnapshotGet(ctx, rb.Name)
+	if err != nil {
+		return nil, err
+	}
+	if from.Volume != nil {
+		*rs.Volume = *from.Volume
+	}
+	rs.Region = to.Region
+	return rs, nil
+}
+
+// copyZone returns the zone used for the temporary disk of a snapshot copy.
+// It defaults to the zone of the source disk if no region is provided.
+func (s *GpdStorage) copyZone(ctx context.Context, sourceDisk string, region string) (string, error) {
+	if region == "" {
+		var zone string
+		zone, region = zoneFromDiskURL(sourceDisk)
+		if zone != "" {
+			return zone, nil
+		}
+	}
+	if region == "" {
+		return "", errors.Errorf("Cannot determine zone of source disk %s", sourceDisk)
+	}
+	zones, err := s.FromRegion(ctx, region)
+	if err != nil {
+		return "", err
+	}
+	if len(zones) == 0 {
+		return "", errors.Errorf("No zones available in region %s", region)
+	}
+	return zones[0], nil
+}
+
+// zoneFromDiskURL parses the zone or, for regional disks, the region from a disk URL
+func zoneFromDiskURL(diskURL string) (zone string, region string) {
+	parts := strings.Split(diskURL, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		switch parts[i] {
+		case "zones":
+			return parts[i+1], ""
+		case "regions":
+			return "", parts[i+1]
+		}
+	}
+	return "", ""
 }
 
 // SnapshotCreate is part of blockstorage.Provider
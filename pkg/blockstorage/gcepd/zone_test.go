@@ -51,3 +51,27 @@ func (s ZoneSuite) TestZoneWithUnknownNodeZones(c *C) {
 		c.Assert(z, DeepEquals, tc.out)
 	}
 }
+
+func (s ZoneSuite) TestZoneFromDiskURL(c *C) {
+	for _, tc := range []struct {
+		url    string
+		zone   string
+		region string
+	}{
+		{
+			url:  "https://www.googleapis.com/compute/v1/projects/p/zones/us-west2-a/disks/vol-1",
+			zone: "us-west2-a",
+		},
+		{
+			url:    "https://www.googleapis.com/compute/v1/projects/p/regions/us-west2/disks/vol-1",
+			region: "us-west2",
+		},
+		{
+			url: "vol-1",
+		},
+	} {
+		zone, region := zoneFromDiskURL(tc.url)
+		c.Assert(zone, Equals, tc.zone)
+		c.Assert(region, Equals, tc.region)
+	}
+}
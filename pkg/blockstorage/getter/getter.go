@@ -22,6 +22,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	"github.com/kanisterio/kanister/pkg/blockstorage/awsebs"
 	"github.com/kanisterio/kanister/pkg/blockstorage/azure"
+	"github.com/kanisterio/kanister/pkg/blockstorage/csi"
 	"github.com/kanisterio/kanister/pkg/blockstorage/gcepd"
 )
 
@@ -48,6 +49,8 @@ func (*getter) Get(storageType blockstorage.Type, config map[string]string) (blo
 		return gcepd.NewProvider(config)
 	case blockstorage.TypeAD:
 		return azure.NewProvider(context.Background(), config)
+	case blockstorage.TypeGeneric, blockstorage.TypeCeph:
+		return csi.NewProvider(storageType, config)
 	default:
 		return nil, errors.Errorf("Unsupported storage type %v", storageType)
 	}
@@ -62,6 +65,8 @@ func Supported(st blockstorage.Type) bool {
 		return true
 	case blockstorage.TypeAD:
 		return true
+	case blockstorage.TypeGeneric, blockstorage.TypeCeph:
+		return true
 	default:
 		return false
 	}
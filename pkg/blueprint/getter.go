@@ -0,0 +1,128 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blueprint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+)
+
+// ClientGetter gets the Blueprints and BlueprintLibraries referenced by
+// Blueprints from the namespace of the Blueprints.
+type ClientGetter struct {
+	cli       versioned.Interface
+	namespace string
+}
+
+// NewClientGetter returns a ClientGetter for the Blueprints of the namespace.
+func NewClientGetter(cli versioned.Interface, namespace string) *ClientGetter {
+	return &ClientGetter{cli: cli, namespace: namespace}
+}
+
+// GetBlueprint returns the Blueprint with the name.
+func (g *ClientGetter) GetBlueprint(ctx context.Context, name string) (*crv1alpha1.Blueprint, error) {
+	return g.cli.CrV1alpha1().Blueprints(g.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetBlueprintLibrary returns the BlueprintLibrary with the name.
+func (g *ClientGetter) GetBlueprintLibrary(ctx context.Context, name string) (*crv1alpha1.BlueprintLibrary, error) {
+	return g.cli.CrV1alpha1().BlueprintLibraries(g.namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// StaticGetter gets the Blueprints and BlueprintLibraries referenced by
+// Blueprints from the ones it was created with, e.g. read from manifests.
+type StaticGetter struct {
+	blueprints map[string]*crv1alpha1.Blueprint
+	libraries  map[string]*crv1alpha1.BlueprintLibrary
+}
+
+// ReadReferencesFromFiles returns a StaticGetter with the Blueprints and
+// BlueprintLibraries of the YAML or JSON manifests, which can contain several
+// documents.
+func ReadReferencesFromFiles(paths ...string) (*StaticGetter, error) {
+	g := &StaticGetter{
+		blueprints: make(map[string]*crv1alpha1.Blueprint),
+		libraries:  make(map[string]*crv1alpha1.BlueprintLibrary),
+	}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.decode(raw); err != nil {
+			return nil, errors.Wrapf(err, "Failed to read %s", path)
+		}
+	}
+	return g, nil
+}
+
+func (g *StaticGetter) decode(raw []byte) error {
+	r := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(raw)))
+	for {
+		doc, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var tm metav1.TypeMeta
+		if err := yaml.Unmarshal(doc, &tm); err != nil {
+			return err
+		}
+		switch tm.Kind {
+		case "Blueprint":
+			bp := &crv1alpha1.Blueprint{}
+			if err := yaml.Unmarshal(doc, bp); err != nil {
+				return err
+			}
+			g.blueprints[bp.GetName()] = bp
+		case "BlueprintLibrary":
+			lib := &crv1alpha1.BlueprintLibrary{}
+			if err := yaml.Unmarshal(doc, lib); err != nil {
+				return err
+			}
+			g.libraries[lib.GetName()] = lib
+		}
+	}
+}
+
+// GetBlueprint returns the Blueprint with the name.
+func (g *StaticGetter) GetBlueprint(_ context.Context, name string) (*crv1alpha1.Blueprint, error) {
+	bp, ok := g.blueprints[name]
+	if !ok {
+		return nil, errors.Errorf("Blueprint %s not found", name)
+	}
+	return bp, nil
+}
+
+// GetBlueprintLibrary returns the BlueprintLibrary with the name.
+func (g *StaticGetter) GetBlueprintLibrary(_ context.Context, name string) (*crv1alpha1.BlueprintLibrary, error) {
+	lib, ok := g.libraries[name]
+	if !ok {
+		return nil, errors.Errorf("BlueprintLibrary %s not found", name)
+	}
+	return lib, nil
+}
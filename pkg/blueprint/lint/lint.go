@@ -0,0 +1,175 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint reports risky patterns in Blueprints that are valid but
+// likely to cause trouble, e.g. commands printing secrets.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// IgnoreAnnotation is the annotation of Blueprints suppressing findings. Its
+// value is a comma separated list of rule names, optionally followed by
+// `:<action>` or `:<action>/<phase>` to only suppress the findings of an
+// action or a phase, e.g. `latest-image,secret-in-command:backup/dump`.
+const IgnoreAnnotation = "lint.kanister.io/ignore"
+
+// Severity is the severity of the findings of a Rule.
+type Severity int
+
+// Severities of the findings, in increasing order
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the Severity with the name.
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if n == name {
+			return Severity(i), nil
+		}
+	}
+	return 0, errors.Errorf("Unknown severity %s, expected one of %v", name, severityNames)
+}
+
+// Finding is a risky pattern found by a Rule. Phase is empty if the finding
+// concerns the whole action, and Action if it concerns the whole Blueprint.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Action   string
+	Phase    string
+	Message  string
+}
+
+func (f Finding) String() string {
+	loc := ""
+	switch {
+	case f.Phase != "":
+		loc = fmt.Sprintf("action %s, phase %s: ", f.Action, f.Phase)
+	case f.Action != "":
+		loc = fmt.Sprintf("action %s: ", f.Action)
+	}
+	return fmt.Sprintf("%s: %s%s (%s)", f.Severity, loc, f.Message, f.Rule)
+}
+
+// Rule checks a Blueprint for a risky pattern. Check only needs to set the
+// Action, Phase and Message of the findings.
+type Rule interface {
+	Name() string
+	Description() string
+	Severity() Severity
+	Check(bp *crv1alpha1.Blueprint) []Finding
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = make(map[string]Rule)
+)
+
+// Register adds a Rule to the rules run by default.
+func Register(r Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if r == nil {
+		panic("lint: Register rule is nil")
+	}
+	if _, dup := rules[r.Name()]; dup {
+		panic("lint: Register called twice for rule " + r.Name())
+	}
+	rules[r.Name()] = r
+}
+
+// Rules returns the registered rules sorted by name.
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	rs := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool { return rs[i].Name() < rs[j].Name() })
+	return rs
+}
+
+// Lint runs the rules on the Blueprint and returns the findings that are not
+// suppressed by its IgnoreAnnotation, sorted by action and phase. References
+// to other Blueprints should be resolved beforehand so that inherited phases
+// are checked too.
+func Lint(bp *crv1alpha1.Blueprint, rs []Rule) []Finding {
+	ignored := parseIgnored(bp.GetAnnotations()[IgnoreAnnotation])
+	var findings []Finding
+	for _, r := range rs {
+		for _, f := range r.Check(bp) {
+			f.Rule = r.Name()
+			f.Severity = r.Severity()
+			if ignored.match(f) {
+				continue
+			}
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Action != findings[j].Action {
+			return findings[i].Action < findings[j].Action
+		}
+		return findings[i].Phase < findings[j].Phase
+	})
+	return findings
+}
+
+type ignoreList []Finding
+
+func parseIgnored(annotation string) ignoreList {
+	var l ignoreList
+	for _, entry := range strings.Split(annotation, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		var f Finding
+		f.Rule, f.Action, _ = strings.Cut(entry, ":")
+		f.Action, f.Phase, _ = strings.Cut(f.Action, "/")
+		l = append(l, f)
+	}
+	return l
+}
+
+func (l ignoreList) match(f Finding) bool {
+	for _, i := range l {
+		if i.Rule == f.Rule && (i.Action == "" || i.Action == f.Action) && (i.Phase == "" || i.Phase == f.Phase) {
+			return true
+		}
+	}
+	return false
+}
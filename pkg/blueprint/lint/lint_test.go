@@ -0,0 +1,158 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type LintSuite struct{}
+
+var _ = Suite(&LintSuite{})
+
+func kubeTask(name, image string, command ...interface{}) crv1alpha1.BlueprintPhase {
+	return crv1alpha1.BlueprintPhase{
+		Name: name,
+		Func: "KubeTask",
+		Args: map[string]interface{}{"namespace": "ns", "image": image, "command": command},
+	}
+}
+
+func scale(name string, replicas interface{}) *crv1alpha1.BlueprintPhase {
+	return &crv1alpha1.BlueprintPhase{
+		Name: name,
+		Func: "ScaleWorkload",
+		Args: map[string]interface{}{"replicas": replicas},
+	}
+}
+
+func (s *LintSuite) TestRules(c *C) {
+	for _, tc := range []struct {
+		rule     Rule
+		action   *crv1alpha1.BlueprintAction
+		findings []Finding
+	}{
+		{
+			rule: secretInCommandRule{},
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				kubeTask("redirect", "busybox:1.36", "sh", "-c", `echo "{{ .Secrets.db.Data.password | toString }}" > /tmp/password`),
+				kubeTask("export", "busybox:1.36", "sh", "-c", "set +o xtrace\nexport PW={{ .Profile.Credential.Secret.Data.key }}\nset -o xtrace\nrun"),
+				kubeTask("other", "busybox:1.36", "sh", "-c", `echo {{ .Object.metadata.name }}`),
+				kubeTask("print", "busybox:1.36", "sh", "-c", `echo "{{ .Secrets.db.Data.password | toString }}"`),
+				kubeTask("flag", "busybox:1.36", "bash", "-ex", "-c", `PW={{ .Profile.Credential.KeyPair.Secret }} run`),
+				kubeTask("set", "busybox:1.36", "bash", "-c", "set -euxo pipefail\nPW={{ .RepositoryServer.Credentials.ServerTLS.Data.key }} run"),
+			}},
+			findings: []Finding{
+				{Action: "action", Phase: "print", Message: "Command prints a secret"},
+				{Action: "action", Phase: "flag", Message: "Command refers to a secret while shell tracing is enabled, which prints it"},
+				{Action: "action", Phase: "set", Message: "Command refers to a secret while shell tracing is enabled, which prints it"},
+			},
+		},
+		{
+			rule: scaleDownWithoutDeferRule{},
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				*scale("down", 0), *scale("downAgain", "0"), *scale("up", 1),
+			}},
+			findings: []Finding{
+				{Action: "action", Phase: "down", Message: "Workload is scaled to 0 replicas without a ScaleWorkload deferPhase scaling it back up if the action fails"},
+				{Action: "action", Phase: "downAgain", Message: "Workload is scaled to 0 replicas without a ScaleWorkload deferPhase scaling it back up if the action fails"},
+			},
+		},
+		{
+			rule: scaleDownWithoutDeferRule{},
+			action: &crv1alpha1.BlueprintAction{
+				Phases:     []crv1alpha1.BlueprintPhase{*scale("down", 0)},
+				DeferPhase: scale("up", "{{ .Phases.down.Output.originalReplicaCount }}"),
+			},
+		},
+		{
+			rule: latestImageRule{},
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				kubeTask("tag", "ghcr.io/kanisterio/kanister-tools:0.99.0"),
+				kubeTask("port", "localhost:5000/tools"),
+				kubeTask("latest", "busybox:latest"),
+				kubeTask("digest", "busybox@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79"),
+				kubeTask("template", "{{ .Options.image }}"),
+			}},
+			findings: []Finding{
+				{Action: "action", Phase: "port", Message: "Image localhost:5000/tools is not pinned to a tag other than latest"},
+				{Action: "action", Phase: "latest", Message: "Image busybox:latest is not pinned to a tag other than latest"},
+			},
+		},
+	} {
+		bp := &crv1alpha1.Blueprint{Actions: map[string]*crv1alpha1.BlueprintAction{"action": tc.action}}
+		c.Check(tc.rule.Check(bp), DeepEquals, tc.findings, Commentf("rule %s", tc.rule.Name()))
+	}
+}
+
+func (s *LintSuite) TestDeleteWithoutArtifacts(c *C) {
+	bp := &crv1alpha1.Blueprint{Actions: map[string]*crv1alpha1.BlueprintAction{
+		"backup": {
+			OutputArtifacts: map[string]crv1alpha1.Artifact{"backup": {KeyValue: map[string]string{"path": "/backup"}}},
+		},
+		"delete": {Phases: []crv1alpha1.BlueprintPhase{kubeTask("delete", "busybox:1.36", "rm", "-rf", "/backup")}},
+	}}
+	c.Assert(deleteWithoutArtifactsRule{}.Check(bp), DeepEquals, []Finding{{
+		Action:  "delete",
+		Message: "Action does not use the input artifacts, so it cannot delete the data of the backup",
+	}})
+	bp.Actions["delete"].Phases[0] = kubeTask("delete", "busybox:1.36", "rm", "-rf", "{{ .ArtifactsIn.backup.KeyValue.path }}")
+	c.Assert(deleteWithoutArtifactsRule{}.Check(bp), HasLen, 0)
+}
+
+func (s *LintSuite) TestLint(c *C) {
+	bp := &crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"restore": {Phases: []crv1alpha1.BlueprintPhase{kubeTask("restore", "busybox")}},
+			"backup": {Phases: []crv1alpha1.BlueprintPhase{
+				kubeTask("dump", "busybox", "sh", "-c", "echo {{ .Secrets.db.Data.password }}"),
+				kubeTask("upload", "busybox"),
+			}},
+		},
+	}
+	c.Assert(Lint(bp, Rules()), DeepEquals, []Finding{
+		{Rule: "latest-image", Severity: SeverityWarning, Action: "backup", Phase: "dump", Message: "Image busybox is not pinned to a tag other than latest"},
+		{Rule: "secret-in-command", Severity: SeverityError, Action: "backup", Phase: "dump", Message: "Command prints a secret"},
+		{Rule: "latest-image", Severity: SeverityWarning, Action: "backup", Phase: "upload", Message: "Image busybox is not pinned to a tag other than latest"},
+		{Rule: "latest-image", Severity: SeverityWarning, Action: "restore", Phase: "restore", Message: "Image busybox is not pinned to a tag other than latest"},
+	})
+	c.Assert(Lint(bp, Rules())[1].String(), Equals, "error: action backup, phase dump: Command prints a secret (secret-in-command)")
+
+	bp.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{
+		IgnoreAnnotation: "latest-image:backup, secret-in-command:backup/upload",
+	}}
+	c.Assert(Lint(bp, Rules()), HasLen, 2)
+	bp.Annotations[IgnoreAnnotation] = "latest-image:restore/restore,secret-in-command"
+	c.Assert(Lint(bp, Rules()), HasLen, 2)
+	bp.Annotations[IgnoreAnnotation] = "latest-image,secret-in-command"
+	c.Assert(Lint(bp, Rules()), HasLen, 0)
+}
+
+func (s *LintSuite) TestSeverity(c *C) {
+	for _, sev := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		parsed, err := ParseSeverity(sev.String())
+		c.Assert(err, IsNil)
+		c.Assert(parsed, Equals, sev)
+	}
+	_, err := ParseSeverity("fatal")
+	c.Assert(err, ErrorMatches, `Unknown severity fatal, expected one of \[info warning error\]`)
+}
@@ -0,0 +1,301 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/utils/strings/slices"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/function"
+)
+
+func init() {
+	Register(secretInCommandRule{})
+	Register(scaleDownWithoutDeferRule{})
+	Register(deleteWithoutArtifactsRule{})
+	Register(latestImageRule{})
+}
+
+const (
+	commandArg = "command"
+	imageArg   = "image"
+)
+
+// phaseRef is a phase of an action of a Blueprint.
+type phaseRef struct {
+	action string
+	phase  crv1alpha1.BlueprintPhase
+}
+
+// phases returns the phases of the actions of the Blueprint, including their
+// deferPhase, sorted by action.
+func phases(bp *crv1alpha1.Blueprint) []phaseRef {
+	names := make([]string, 0, len(bp.Actions))
+	for name := range bp.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var ps []phaseRef
+	for _, name := range names {
+		a := bp.Actions[name]
+		if a == nil {
+			continue
+		}
+		for _, p := range a.Phases {
+			ps = append(ps, phaseRef{action: name, phase: p})
+		}
+		if a.DeferPhase != nil {
+			ps = append(ps, phaseRef{action: name, phase: *a.DeferPhase})
+		}
+	}
+	return ps
+}
+
+// hasArgument returns true if the function registered with the name supports
+// the argument.
+func hasArgument(funcName, arg string) bool {
+	f := kanister.KanisterFuncForName(funcName, kanister.DefaultVersion)
+	return f != nil && slices.Contains(f.Arguments(), arg)
+}
+
+// argStrings returns the strings contained in the value of an argument.
+func argStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var ss []string
+		for _, e := range v {
+			ss = append(ss, argStrings(e)...)
+		}
+		return ss
+	case []string:
+		return v
+	case map[string]interface{}:
+		var ss []string
+		for _, e := range v {
+			ss = append(ss, argStrings(e)...)
+		}
+		return ss
+	}
+	return nil
+}
+
+var (
+	secretRE = `\{\{[^}]*\.(Secrets|Credentials?)\b[^}]*\}\}`
+	// printSecretRE matches echo or printf statements printing a secret to
+	// the output rather than redirecting it to a file or a pipe
+	printSecretRE = regexp.MustCompile(`(?m)\b(echo|printf)\b[^\n;|&>]*` + secretRE + `[^\n;|&>]*(&&|;|$)`)
+	refSecretRE   = regexp.MustCompile(secretRE)
+	xtraceRE      = regexp.MustCompile(`\bset\s+([-+])([a-z]*x[a-z]*|o\s+xtrace)\b`)
+	shellFlagRE   = regexp.MustCompile(`^-[a-z]*x[a-z]*$`)
+)
+
+// secretInCommandRule reports commands that print the secrets or credentials
+// they refer to, which end up in the logs of the pods and of the controller.
+type secretInCommandRule struct{}
+
+func (secretInCommandRule) Name() string { return "secret-in-command" }
+
+func (secretInCommandRule) Description() string {
+	return "Commands must not print secrets or credentials, e.g. with echo or shell tracing"
+}
+
+func (secretInCommandRule) Severity() Severity { return SeverityError }
+
+func (secretInCommandRule) Check(bp *crv1alpha1.Blueprint) []Finding {
+	var findings []Finding
+	for _, p := range phases(bp) {
+		if !hasArgument(p.phase.Func, commandArg) {
+			continue
+		}
+		if msg := checkCommand(argStrings(p.phase.Args[commandArg])); msg != "" {
+			findings = append(findings, Finding{
+				Action:  p.action,
+				Phase:   p.phase.Name,
+				Message: msg,
+			})
+		}
+	}
+	return findings
+}
+
+// checkCommand returns why the command prints a secret, if it does. Secrets
+// referred to while shell tracing is enabled are printed by the shell.
+func checkCommand(cmd []string) string {
+	traced := false
+	for i, s := range cmd {
+		if printSecretRE.MatchString(s) {
+			return "Command prints a secret"
+		}
+		if i > 0 && strings.HasSuffix(cmd[0], "sh") && shellFlagRE.MatchString(s) {
+			traced = true
+			continue
+		}
+		// Check the parts of the script between the set -x and set +x
+		start := 0
+		for _, m := range xtraceRE.FindAllStringSubmatchIndex(s, -1) {
+			if traced && refSecretRE.MatchString(s[start:m[0]]) {
+				return "Command refers to a secret while shell tracing is enabled, which prints it"
+			}
+			traced, start = s[m[2]:m[3]] == "-", m[1]
+		}
+		if traced && refSecretRE.MatchString(s[start:]) {
+			return "Command refers to a secret while shell tracing is enabled, which prints it"
+		}
+	}
+	return ""
+}
+
+// scaleDownWithoutDeferRule reports actions scaling workloads to 0 replicas
+// without a deferPhase scaling them back up, which leaves them down if the
+// action fails.
+type scaleDownWithoutDeferRule struct{}
+
+func (scaleDownWithoutDeferRule) Name() string { return "scale-down-without-defer" }
+
+func (scaleDownWithoutDeferRule) Description() string {
+	return "Actions scaling a workload to 0 replicas must scale it back up in their deferPhase"
+}
+
+func (scaleDownWithoutDeferRule) Severity() Severity { return SeverityWarning }
+
+func (scaleDownWithoutDeferRule) Check(bp *crv1alpha1.Blueprint) []Finding {
+	var findings []Finding
+	for _, p := range phases(bp) {
+		if p.phase.Func != function.ScaleWorkloadFuncName || !isZero(p.phase.Args[function.ScaleWorkloadReplicas]) {
+			continue
+		}
+		a := bp.Actions[p.action]
+		if a.DeferPhase != nil && a.DeferPhase.Func == function.ScaleWorkloadFuncName {
+			continue
+		}
+		findings = append(findings, Finding{
+			Action:  p.action,
+			Phase:   p.phase.Name,
+			Message: "Workload is scaled to 0 replicas without a ScaleWorkload deferPhase scaling it back up if the action fails",
+		})
+	}
+	return findings
+}
+
+func isZero(v interface{}) bool {
+	switch v := v.(type) {
+	case int:
+		return v == 0
+	case int64:
+		return v == 0
+	case float64:
+		return v == 0
+	case string:
+		return strings.TrimSpace(v) == "0"
+	}
+	return false
+}
+
+// deleteWithoutArtifactsRule reports delete actions that do not use the
+// artifacts of the backups, and therefore cannot delete the backup they are
+// run for.
+type deleteWithoutArtifactsRule struct{}
+
+const deleteAction = "delete"
+
+var artifactsInRE = regexp.MustCompile(`\{\{[^}]*\.ArtifactsIn\b`)
+
+func (deleteWithoutArtifactsRule) Name() string { return "delete-without-artifacts" }
+
+func (deleteWithoutArtifactsRule) Description() string {
+	return "The delete action must use the output artifacts of the backup it deletes"
+}
+
+func (deleteWithoutArtifactsRule) Severity() Severity { return SeverityWarning }
+
+func (deleteWithoutArtifactsRule) Check(bp *crv1alpha1.Blueprint) []Finding {
+	a, ok := bp.Actions[deleteAction]
+	if !ok || a == nil || len(a.InputArtifactNames) != 0 || !hasOutputArtifacts(bp) {
+		return nil
+	}
+	for _, p := range phases(bp) {
+		if p.action != deleteAction {
+			continue
+		}
+		for _, arg := range p.phase.Args {
+			for _, s := range argStrings(arg) {
+				if artifactsInRE.MatchString(s) {
+					return nil
+				}
+			}
+		}
+	}
+	return []Finding{{
+		Action:  deleteAction,
+		Message: "Action does not use the input artifacts, so it cannot delete the data of the backup",
+	}}
+}
+
+func hasOutputArtifacts(bp *crv1alpha1.Blueprint) bool {
+	for _, a := range bp.Actions {
+		if a != nil && len(a.OutputArtifacts) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// latestImageRule reports images without a tag or with the `latest` tag,
+// which change without notice and make the actions not reproducible.
+type latestImageRule struct{}
+
+func (latestImageRule) Name() string { return "latest-image" }
+
+func (latestImageRule) Description() string {
+	return "Images must have a tag other than latest or a digest"
+}
+
+func (latestImageRule) Severity() Severity { return SeverityWarning }
+
+func (latestImageRule) Check(bp *crv1alpha1.Blueprint) []Finding {
+	var findings []Finding
+	for _, p := range phases(bp) {
+		image, ok := p.phase.Args[imageArg].(string)
+		if !ok || !hasArgument(p.phase.Func, imageArg) || strings.Contains(image, "{{") {
+			continue
+		}
+		if tag := imageTag(image); tag == "" || tag == "latest" {
+			findings = append(findings, Finding{
+				Action:  p.action,
+				Phase:   p.phase.Name,
+				Message: fmt.Sprintf("Image %s is not pinned to a tag other than latest", image),
+			})
+		}
+	}
+	return findings
+}
+
+// imageTag returns the tag of the image reference, or its digest.
+func imageTag(image string) string {
+	if _, digest, ok := strings.Cut(image, "@"); ok {
+		return digest
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, _ := strings.Cut(name, ":")
+	return tag
+}
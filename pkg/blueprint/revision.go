@@ -0,0 +1,139 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blueprint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/consts"
+)
+
+// RevisionBlueprintLabel is the label of BlueprintRevisions with the name of
+// their Blueprint.
+const RevisionBlueprintLabel = consts.LabelPrefix + "blueprint"
+
+// revisionHashLen is the length of the prefix of the hash in revision names.
+const revisionHashLen = 10
+
+// NewRevision returns the BlueprintRevision of the actions of the Blueprint,
+// whose references must be resolved beforehand with kanister.ResolveBlueprint.
+// It is named after the Blueprint and the hash of its actions.
+func NewRevision(bp *crv1alpha1.Blueprint) (*crv1alpha1.BlueprintRevision, error) {
+	hash, err := actionsHash(bp.Actions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to hash the actions of blueprint %s", bp.GetName())
+	}
+	return &crv1alpha1.BlueprintRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", bp.GetName(), hash[:revisionHashLen]),
+			Namespace: bp.GetNamespace(),
+			Labels:    map[string]string{RevisionBlueprintLabel: bp.GetName()},
+		},
+		Blueprint: bp.GetName(),
+		Hash:      hash,
+		Actions:   bp.DeepCopy().Actions,
+	}, nil
+}
+
+// VerifyRevision checks that the actions of the revision have its hash, i.e.
+// that they were not modified since the revision was created.
+func VerifyRevision(rev *crv1alpha1.BlueprintRevision) error {
+	hash, err := actionsHash(rev.Actions)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to hash the actions of revision %s", rev.GetName())
+	}
+	if hash != rev.Hash {
+		return errors.Errorf("Actions of revision %s do not match its hash %s", rev.GetName(), rev.Hash)
+	}
+	return nil
+}
+
+func actionsHash(actions map[string]*crv1alpha1.BlueprintAction) (string, error) {
+	// The keys of the maps are sorted, so the same actions have the same hash
+	raw, err := json.Marshal(actions)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// FromRevision returns the Blueprint with the actions of the revision.
+func FromRevision(rev *crv1alpha1.BlueprintRevision) *crv1alpha1.Blueprint {
+	return &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rev.Blueprint,
+			Namespace: rev.GetNamespace(),
+		},
+		Actions: rev.DeepCopy().Actions,
+	}
+}
+
+// EnsureRevision creates the BlueprintRevision of the Blueprint if it does not
+// exist yet, and returns its name. The references of the Blueprint must be
+// resolved beforehand.
+func EnsureRevision(ctx context.Context, cli versioned.Interface, bp *crv1alpha1.Blueprint) (string, error) {
+	rev, err := NewRevision(bp)
+	if err != nil {
+		return "", err
+	}
+	revs := cli.CrV1alpha1().BlueprintRevisions(bp.GetNamespace())
+	_, err = revs.Create(ctx, rev, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		// The name only has a prefix of the hash, check that the existing
+		// revision has the same actions
+		existing, gErr := revs.Get(ctx, rev.GetName(), metav1.GetOptions{})
+		if gErr != nil {
+			return "", errors.Wrapf(gErr, "Failed to get revision %s of blueprint %s", rev.GetName(), bp.GetName())
+		}
+		if existing.Hash != rev.Hash {
+			return "", errors.Errorf("Revision %s of blueprint %s exists with another hash %s", rev.GetName(), bp.GetName(), existing.Hash)
+		}
+		if err := VerifyRevision(existing); err != nil {
+			return "", err
+		}
+		return rev.GetName(), nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to create revision %s of blueprint %s", rev.GetName(), bp.GetName())
+	}
+	return rev.GetName(), nil
+}
+
+// GetRevision returns the Blueprint with the actions of its revision. It fails
+// if the actions of the revision do not match its hash.
+func GetRevision(ctx context.Context, cli versioned.Interface, namespace, blueprint, revision string) (*crv1alpha1.Blueprint, error) {
+	rev, err := cli.CrV1alpha1().BlueprintRevisions(namespace).Get(ctx, revision, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get revision %s of blueprint %s", revision, blueprint)
+	}
+	if blueprint != "" && rev.Blueprint != blueprint {
+		return nil, errors.Errorf("Revision %s is a revision of blueprint %s, not %s", revision, rev.Blueprint, blueprint)
+	}
+	if err := VerifyRevision(rev); err != nil {
+		return nil, err
+	}
+	return FromRevision(rev), nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blueprint
+
+import (
+	"context"
+	"testing"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type RevisionSuite struct{}
+
+var _ = Suite(&RevisionSuite{})
+
+func (s *RevisionSuite) TestRevision(c *C) {
+	ctx := context.Background()
+	cli := crfake.NewSimpleClientset()
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "ns"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {Phases: []crv1alpha1.BlueprintPhase{{Name: "dump", Func: "KubeTask"}}},
+		},
+	}
+	rev, err := EnsureRevision(ctx, cli, bp)
+	c.Assert(err, IsNil)
+	c.Assert(rev, Matches, "bp-[0-9a-f]{10}")
+
+	// The same actions have the same revision
+	again, err := EnsureRevision(ctx, cli, bp.DeepCopy())
+	c.Assert(err, IsNil)
+	c.Assert(again, Equals, rev)
+
+	// Updating the Blueprint creates another revision and keeps the first one
+	updated := bp.DeepCopy()
+	updated.Actions["backup"].Phases[0].Name = "export"
+	other, err := EnsureRevision(ctx, cli, updated)
+	c.Assert(err, IsNil)
+	c.Assert(other, Not(Equals), rev)
+
+	got, err := GetRevision(ctx, cli, "ns", "bp", rev)
+	c.Assert(err, IsNil)
+	c.Assert(got.GetName(), Equals, "bp")
+	c.Assert(got.Actions, DeepEquals, bp.Actions)
+
+	revs, err := cli.CrV1alpha1().BlueprintRevisions("ns").List(ctx, metav1.ListOptions{LabelSelector: RevisionBlueprintLabel + "=bp"})
+	c.Assert(err, IsNil)
+	c.Assert(revs.Items, HasLen, 2)
+
+	_, err = GetRevision(ctx, cli, "ns", "other", rev)
+	c.Assert(err, ErrorMatches, "Revision bp-.* is a revision of blueprint bp, not other")
+	_, err = GetRevision(ctx, cli, "ns", "bp", "bp-missing")
+	c.Assert(err, ErrorMatches, "Failed to get revision bp-missing of blueprint bp.*")
+}
+
+func (s *RevisionSuite) TestRevisionCollision(c *C) {
+	ctx := context.Background()
+	bp := &crv1alpha1.Blueprint{ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "ns"}}
+	rev, err := NewRevision(bp)
+	c.Assert(err, IsNil)
+	rev.Hash = "another"
+	cli := crfake.NewSimpleClientset(rev)
+	_, err = EnsureRevision(ctx, cli, bp)
+	c.Assert(err, ErrorMatches, "Revision bp-.* of blueprint bp exists with another hash another")
+}
+
+func (s *RevisionSuite) TestRevisionModified(c *C) {
+	ctx := context.Background()
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "ns"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {Phases: []crv1alpha1.BlueprintPhase{{Name: "dump", Func: "KubeTask"}}},
+		},
+	}
+	rev, err := NewRevision(bp)
+	c.Assert(err, IsNil)
+	c.Assert(VerifyRevision(rev), IsNil)
+
+	// The actions of the revision were edited after it was created
+	rev.Actions["backup"].Phases[0].Func = "KubeExec"
+	c.Assert(VerifyRevision(rev), ErrorMatches, "Actions of revision bp-.* do not match its hash .*")
+	cli := crfake.NewSimpleClientset(rev)
+	_, err = GetRevision(ctx, cli, "ns", "bp", rev.GetName())
+	c.Assert(err, ErrorMatches, "Actions of revision bp-.* do not match its hash .*")
+	_, err = EnsureRevision(ctx, cli, bp)
+	c.Assert(err, ErrorMatches, "Actions of revision bp-.* do not match its hash .*")
+}
@@ -0,0 +1,341 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/pkg/errors"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/ksprig"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// templateScope is what the templates of a phase or of the output artifacts
+// of an action can refer to.
+type templateScope struct {
+	action      *crv1alpha1.BlueprintAction
+	funcVersion string
+	// phases are the phases whose outputs are available
+	phases map[string]crv1alpha1.BlueprintPhase
+	// current is the phase being run, whose secrets are available
+	current *crv1alpha1.BlueprintPhase
+	// deferPhase is set if the output of the deferPhase is available
+	deferPhase bool
+}
+
+// checkTemplates parses the templates in the args and object refs of the
+// phases and in the output artifacts of the action. It checks that they only
+// refer to template params that are available when they are rendered, and
+// to the outputs declared by the functions of the phases.
+func checkTemplates(action *crv1alpha1.BlueprintAction, funcVersion string) error {
+	scope := templateScope{action: action, funcVersion: funcVersion, phases: map[string]crv1alpha1.BlueprintPhase{}}
+	for i := range action.Phases {
+		p := action.Phases[i]
+		scope.current = &p
+		if err := scope.checkPhase(p); err != nil {
+			return err
+		}
+		scope.phases[p.Name] = p
+	}
+	if action.DeferPhase != nil {
+		// The deferPhase runs even if a phase failed, but the blueprint
+		// can still refer to the outputs of the phases that completed
+		scope.current = action.DeferPhase
+		if err := scope.checkPhase(*action.DeferPhase); err != nil {
+			return err
+		}
+		scope.deferPhase = true
+	}
+	scope.current = nil
+	for name, a := range action.OutputArtifacts {
+		for _, s := range artifactTemplates(a) {
+			if err := scope.check(s); err != nil {
+				return errors.Wrapf(err, "Invalid template in output artifact %s", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (s templateScope) checkPhase(p crv1alpha1.BlueprintPhase) error {
+	for name, arg := range p.Args {
+		for _, t := range argTemplates(arg) {
+			if err := s.check(t); err != nil {
+				return errors.Wrapf(err, "Invalid template in arg %s of phase %s", name, p.Name)
+			}
+		}
+	}
+	for name, ref := range p.ObjectRefs {
+		for _, t := range argTemplates(ref) {
+			if err := s.check(t); err != nil {
+				return errors.Wrapf(err, "Invalid template in object %s of phase %s", name, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// check parses the template and checks its references
+func (s templateScope) check(text string) error {
+	t, err := template.New("config").Funcs(ksprig.TxtFuncMap()).Parse(text)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, ref := range templateRefs(t.Tree.Root, true) {
+		if err := s.checkRef(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRef checks a reference like `.Phases.backup.Output.id`
+func (s templateScope) checkRef(ref []string) error {
+	if len(ref) == 0 {
+		return nil
+	}
+	if _, ok := reflect.TypeOf(param.TemplateParams{}).FieldByName(ref[0]); !ok {
+		return errors.Errorf("Unknown template param .%s", ref[0])
+	}
+	if len(ref) == 1 {
+		return nil
+	}
+	name := ref[1]
+	switch ref[0] {
+	case "Phases":
+		p, ok := s.phases[name]
+		if s.current != nil && s.current.Name == name {
+			// The secrets of the phase are available while it runs
+			if len(ref) > 2 && ref[2] == "Secrets" {
+				return checkPhaseSecret(*s.current, ref)
+			}
+			return errors.Errorf("Phase %s refers to its own output", name)
+		}
+		if !ok {
+			return errors.Errorf("Unknown phase %s in .Phases.%s, or the phase runs later", name, strings.Join(ref[1:], "."))
+		}
+		if len(ref) > 2 && ref[2] == "Secrets" {
+			return checkPhaseSecret(p, ref)
+		}
+		if len(ref) > 3 && ref[2] == "Output" {
+			return s.checkOutput(p, ref[3])
+		}
+	case "DeferPhase":
+		if !s.deferPhase {
+			return errors.New("The output of the deferPhase is only available in the output artifacts")
+		}
+		if ref[1] == "Output" && len(ref) > 2 {
+			return s.checkOutput(*s.action.DeferPhase, ref[2])
+		}
+	case "ArtifactsIn":
+		return checkDeclared(name, s.action.InputArtifactNames, "input artifact", "inputArtifactNames")
+	case "ConfigMaps":
+		return checkDeclared(name, s.action.ConfigMapNames, "config map", "configMapNames")
+	case "Secrets":
+		return checkDeclared(name, s.action.SecretNames, "secret", "secretNames")
+	case "Options":
+		return checkDeclared(name, param.ParameterNames(s.action.Parameters), "option", "parameters")
+	}
+	return nil
+}
+
+// checkOutput checks that the function of the phase declares the output
+func (s templateScope) checkOutput(p crv1alpha1.BlueprintPhase, name string) error {
+	f := kanister.KanisterFuncForName(p.Func, s.funcVersion)
+	if f == nil {
+		return nil
+	}
+	outputs, ok := kanister.FuncOutputs(f)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(outputs))
+	for _, o := range outputs {
+		if o.Name == name {
+			return nil
+		}
+		names = append(names, o.Name)
+	}
+	return errors.Errorf("Unknown output %s of phase %s, function %s outputs %v", name, p.Name, p.Func, names)
+}
+
+func checkPhaseSecret(p crv1alpha1.BlueprintPhase, ref []string) error {
+	if len(ref) < 4 {
+		return nil
+	}
+	if _, ok := p.ObjectRefs[ref[3]]; !ok {
+		return errors.Errorf("Unknown secret %s of phase %s, it must be one of the phase objects", ref[3], p.Name)
+	}
+	return nil
+}
+
+// checkDeclared checks the name against the declared names. The declarations
+// are optional, so any name is accepted if there are none.
+func checkDeclared(name string, declared []string, kind, field string) error {
+	if len(declared) == 0 {
+		return nil
+	}
+	for _, d := range declared {
+		if d == name {
+			return nil
+		}
+	}
+	return errors.Errorf("Unknown %s %s, it must be declared in %s %v", kind, name, field, declared)
+}
+
+// templateRefs returns the references to the template params in the nodes.
+// References inside range and with blocks are relative to another value,
+// unless they start at the root `$`.
+func templateRefs(node parse.Node, dotIsRoot bool) [][]string {
+	var refs [][]string
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			refs = append(refs, templateRefs(c, dotIsRoot)...)
+		}
+	case *parse.ActionNode:
+		refs = append(refs, templateRefs(n.Pipe, dotIsRoot)...)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			refs = append(refs, templateRefs(cmd, dotIsRoot)...)
+		}
+	case *parse.CommandNode:
+		refs = append(refs, commandRefs(n, dotIsRoot)...)
+	case *parse.IfNode:
+		refs = append(refs, branchRefs(&n.BranchNode, dotIsRoot, dotIsRoot)...)
+	case *parse.RangeNode:
+		refs = append(refs, branchRefs(&n.BranchNode, dotIsRoot, false)...)
+	case *parse.WithNode:
+		refs = append(refs, branchRefs(&n.BranchNode, dotIsRoot, false)...)
+	case *parse.FieldNode:
+		if dotIsRoot {
+			refs = append(refs, n.Ident)
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			refs = append(refs, n.Ident[1:])
+		}
+	case *parse.ChainNode:
+		// e.g. (.Phases).backup
+		refs = append(refs, templateRefs(n.Node, dotIsRoot)...)
+	}
+	return refs
+}
+
+func branchRefs(n *parse.BranchNode, dotIsRoot, bodyDotIsRoot bool) [][]string {
+	refs := templateRefs(n.Pipe, dotIsRoot)
+	refs = append(refs, templateRefs(n.List, bodyDotIsRoot)...)
+	// The else branch of range and with is run with the original dot
+	refs = append(refs, templateRefs(n.ElseList, dotIsRoot)...)
+	return refs
+}
+
+// commandRefs also resolves `index .Phases "backup" "Output" "id"`
+func commandRefs(n *parse.CommandNode, dotIsRoot bool) [][]string {
+	var refs [][]string
+	for _, arg := range n.Args {
+		refs = append(refs, templateRefs(arg, dotIsRoot)...)
+	}
+	if len(n.Args) < 3 {
+		return refs
+	}
+	if id, ok := n.Args[0].(*parse.IdentifierNode); !ok || id.Ident != "index" {
+		return refs
+	}
+	var ref []string
+	switch base := n.Args[1].(type) {
+	case *parse.FieldNode:
+		if !dotIsRoot {
+			return refs
+		}
+		ref = append(ref, base.Ident...)
+	case *parse.VariableNode:
+		if len(base.Ident) < 2 || base.Ident[0] != "$" {
+			return refs
+		}
+		ref = append(ref, base.Ident[1:]...)
+	default:
+		return refs
+	}
+	for _, arg := range n.Args[2:] {
+		s, ok := arg.(*parse.StringNode)
+		if !ok {
+			break
+		}
+		ref = append(ref, s.Text)
+	}
+	return append(refs, ref)
+}
+
+// argTemplates returns the strings in an arg, which are all rendered
+func argTemplates(arg interface{}) []string {
+	var ts []string
+	val := reflect.ValueOf(arg)
+	if !val.IsValid() {
+		return nil
+	}
+	switch val.Kind() {
+	case reflect.String:
+		if strings.Contains(val.String(), "{{") {
+			ts = append(ts, val.String())
+		}
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			ts = append(ts, argTemplates(val.Index(i).Interface())...)
+		}
+	case reflect.Map:
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			ts = append(ts, argTemplates(k.Interface())...)
+			ts = append(ts, argTemplates(val.MapIndex(k).Interface())...)
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if val.Type().Field(i).IsExported() {
+				ts = append(ts, argTemplates(val.Field(i).Interface())...)
+			}
+		}
+	case reflect.Interface, reflect.Ptr:
+		if !val.IsNil() {
+			ts = append(ts, argTemplates(val.Elem().Interface())...)
+		}
+	}
+	return ts
+}
+
+func artifactTemplates(a crv1alpha1.Artifact) []string {
+	var ts []string
+	for _, v := range a.KeyValue {
+		ts = append(ts, v)
+	}
+	if a.KopiaSnapshot != "" {
+		ts = append(ts, a.KopiaSnapshot)
+	}
+	return ts
+}
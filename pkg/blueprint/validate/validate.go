@@ -15,6 +15,7 @@
 package validate
 
 import (
+	"context"
 	"fmt"
 
 	kanister "github.com/kanisterio/kanister/pkg"
@@ -30,19 +31,27 @@ const (
 )
 
 // Do takes a blueprint and validates if the function names in phases are correct
-// and all the required arguments for the kanister functions are provided. This doesn't
-// check anything with template params yet.
+// and all the required arguments for the kanister functions are provided. It also
+// checks that the parameters of the actions are consistent, and that the
+// templates only refer to the template params available to them and to the
+// outputs declared by the functions. References to other Blueprints or
+// BlueprintLibraries must be resolved beforehand with kanister.ResolveBlueprint.
 func Do(bp *crv1alpha1.Blueprint, funcVersion string) error {
-	for name, action := range bp.Actions {
+	resolved, err := kanister.ResolveBlueprint(context.Background(), nil, bp)
+	if err != nil {
+		utils.PrintStage("resolution of references", utils.Fail)
+		return errors.Wrap(err, BPValidationErr)
+	}
+	for name, action := range resolved.Actions {
 		// GetPhases also checks if the function names referred in the action are correct
-		phases, err := kanister.GetPhases(*bp, name, funcVersion, param.TemplateParams{})
+		phases, err := kanister.GetPhases(*resolved, name, funcVersion, param.TemplateParams{})
 		if err != nil {
 			utils.PrintStage(fmt.Sprintf("validation of action %s", name), utils.Fail)
 			return errors.Wrapf(err, "%s action %s", BPValidationErr, name)
 		}
 
 		// validate deferPhase's argument
-		deferPhase, err := kanister.GetDeferPhase(*bp, name, funcVersion, param.TemplateParams{})
+		deferPhase, err := kanister.GetDeferPhase(*resolved, name, funcVersion, param.TemplateParams{})
 		if err != nil {
 			utils.PrintStage(fmt.Sprintf("validation of action %s", name), utils.Fail)
 			return errors.Wrapf(err, "%s action %s", BPValidationErr, name)
@@ -65,6 +74,17 @@ func Do(bp *crv1alpha1.Blueprint, funcVersion string) error {
 			}
 			utils.PrintStage(fmt.Sprintf("validation of phase %s in action %s", phase.Name(), name), utils.Pass)
 		}
+
+		if err := param.ValidateParameters(action.Parameters); err != nil {
+			utils.PrintStage(fmt.Sprintf("validation of parameters in action %s", name), utils.Fail)
+			return errors.Wrapf(err, "%s action %s", BPValidationErr, name)
+		}
+
+		if err := checkTemplates(action, funcVersion); err != nil {
+			utils.PrintStage(fmt.Sprintf("validation of templates in action %s", name), utils.Fail)
+			return errors.Wrapf(err, "%s action %s", BPValidationErr, name)
+		}
+		utils.PrintStage(fmt.Sprintf("validation of templates in action %s", name), utils.Pass)
 	}
 
 	return validatePhaseNames(bp)
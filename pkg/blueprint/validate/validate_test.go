@@ -98,6 +98,34 @@ func (v *ValidateBlueprint) TestValidate(c *C) {
 			},
 			err: IsNil,
 		},
+		{
+			// containers replace image and command
+			backupPhases: []crv1alpha1.BlueprintPhase{
+				{
+					Func: "KubeTask",
+					Name: "15",
+					Args: map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "task", "image": "busybox"},
+						},
+					},
+				},
+			},
+			err: IsNil,
+		},
+		{
+			// containers can not be empty
+			backupPhases: []crv1alpha1.BlueprintPhase{
+				{
+					Func: "KubeTask",
+					Name: "16",
+					Args: map[string]interface{}{
+						"containers": "",
+					},
+				},
+			},
+			err: NotNil,
+		},
 		{
 			// function name is incorrect
 			backupPhases: []crv1alpha1.BlueprintPhase{
@@ -425,6 +453,50 @@ func (v *ValidateBlueprint) TestValidatePhaseNames(c *C) {
 	}
 }
 
+func (v *ValidateBlueprint) TestValidateReferences(c *C) {
+	for _, tc := range []struct {
+		ref         *crv1alpha1.BlueprintReference
+		err         Checker
+		errContains string
+	}{
+		{
+			ref: &crv1alpha1.BlueprintReference{Kind: kanister.RefKindBlueprint, Action: "backup", Phase: "quiesce"},
+			err: IsNil,
+		},
+		{
+			ref:         &crv1alpha1.BlueprintReference{Kind: kanister.RefKindBlueprint, Action: "backup", Phase: "unknown"},
+			err:         NotNil,
+			errContains: "Phase unknown referred to by phase requiesce not found in Blueprint",
+		},
+		{
+			ref:         &crv1alpha1.BlueprintReference{Name: "lib", Phase: "quiesce"},
+			err:         NotNil,
+			errContains: "Cannot get BlueprintLibrary lib",
+		},
+	} {
+		bp := blueprint()
+		bp.Actions["backup"].Phases = []crv1alpha1.BlueprintPhase{
+			{
+				Func: "KubeExec",
+				Name: "quiesce",
+				Args: map[string]interface{}{
+					"namespace": "ns",
+					"pod":       "db-0",
+					"command":   []string{"quiesce"},
+				},
+			},
+		}
+		bp.Actions["restore"].Phases = []crv1alpha1.BlueprintPhase{
+			{Name: "requiesce", Ref: tc.ref, Args: map[string]interface{}{"pod": "db-1"}},
+		}
+		err := Do(bp, kanister.DefaultVersion)
+		if err != nil {
+			c.Assert(strings.Contains(err.Error(), tc.errContains), Equals, true, Commentf("%s", err))
+		}
+		c.Assert(err, tc.err)
+	}
+}
+
 func blueprint() *crv1alpha1.Blueprint {
 	return &crv1alpha1.Blueprint{
 		Actions: map[string]*crv1alpha1.BlueprintAction{
@@ -469,3 +541,152 @@ var _ kanister.Func = (*nonDefaultVersionFunc)(nil)
 func init() {
 	_ = kanister.RegisterVersion(&nonDefaultVersionFunc{}, nonDefaultFuncVersion)
 }
+
+func (v *ValidateBlueprint) TestCheckTemplates(c *C) {
+	phase := func(name string, args map[string]interface{}) crv1alpha1.BlueprintPhase {
+		return crv1alpha1.BlueprintPhase{Func: "KubeTask", Name: name, Args: args}
+	}
+	for _, tc := range []struct {
+		action      crv1alpha1.BlueprintAction
+		errContains string
+	}{
+		{
+			action: crv1alpha1.BlueprintAction{
+				InputArtifactNames: []string{"backup"},
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .ArtifactsIn.backup.KeyValue.path }}"}),
+					phase("two", map[string]interface{}{
+						"command": []interface{}{"{{ .Phases.one.Output.id }}", `{{ index .Phases "one" "Output" "id" }}`},
+						"podOverride": map[string]interface{}{
+							"labels": "{{ range .Object.items }}{{ .Undeclared }}{{ $.Time }}{{ end }}",
+						},
+					}),
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{Name: "cleanup", Args: map[string]interface{}{"id": "{{ .Phases.two.Output.id }}"}},
+				OutputArtifacts: map[string]crv1alpha1.Artifact{
+					"out": {KeyValue: map[string]string{"id": "{{ .Phases.one.Output.id }}{{ .DeferPhase.Output.id }}"}},
+				},
+			},
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .Phases.backupp.Output.id }}"}),
+				},
+			},
+			errContains: "Unknown phase backupp",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .Options.anything }}"}),
+				},
+			},
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Parameters: []crv1alpha1.BlueprintParameter{{Name: "retention"}},
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .Options.retention }}{{ .Options.retension }}"}),
+				},
+			},
+			errContains: "Unknown option retension, it must be declared in parameters \\[retention\\]",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": `{{ index .Phases "two" "Output" "id" }}`}),
+					phase("two", nil),
+				},
+			},
+			errContains: "Unknown phase two",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .Phases.one.Output.id }}"}),
+				},
+			},
+			errContains: "refers to its own output",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{{
+					Name:       "one",
+					ObjectRefs: map[string]crv1alpha1.ObjectReference{"creds": {Kind: "Secret", Name: "{{ .Deploymnet.Name }}"}},
+				}},
+			},
+			errContains: "Unknown template param .Deploymnet",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{{
+					Name:       "one",
+					ObjectRefs: map[string]crv1alpha1.ObjectReference{"creds": {Kind: "Secret", Name: "creds"}},
+					Args:       map[string]interface{}{"image": "{{ .Phases.one.Secrets.credz.Data }}"},
+				}},
+			},
+			errContains: "Unknown secret credz of phase one",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				InputArtifactNames: []string{"backup"},
+				ConfigMapNames:     []string{"config"},
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .ConfigMaps.config.Data }}{{ .ArtifactsIn.backupp.KeyValue }}"}),
+				},
+			},
+			errContains: "Unknown input artifact backupp",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Func: "ScaleWorkload", Name: "scale"},
+					phase("one", map[string]interface{}{"image": "{{ .Phases.scale.Output.originalReplicas }}"}),
+				},
+			},
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Func: "ScaleWorkload", Name: "scale"},
+					phase("one", map[string]interface{}{"image": "{{ .Phases.scale.Output.replicas }}"}),
+				},
+			},
+			errContains: "Unknown output replicas of phase scale",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases:     []crv1alpha1.BlueprintPhase{phase("one", nil)},
+				DeferPhase: &crv1alpha1.BlueprintPhase{Func: "DeleteData", Name: "cleanup"},
+				OutputArtifacts: map[string]crv1alpha1.Artifact{
+					"out": {KeyValue: map[string]string{"id": "{{ .DeferPhase.Output.backupID }}"}},
+				},
+			},
+			errContains: "Unknown output backupID of phase cleanup",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .DeferPhase.Output.id }}"}),
+				},
+			},
+			errContains: "only available in the output artifacts",
+		},
+		{
+			action: crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					phase("one", map[string]interface{}{"image": "{{ .Phases.one.Output.id "}),
+				},
+			},
+			errContains: "unclosed action",
+		},
+	} {
+		err := checkTemplates(&tc.action, kanister.DefaultVersion)
+		if tc.errContains == "" {
+			c.Assert(err, IsNil)
+			continue
+		}
+		c.Assert(err, ErrorMatches, ".*"+tc.errContains+".*")
+	}
+}
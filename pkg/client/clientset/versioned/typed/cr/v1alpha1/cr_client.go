@@ -30,6 +30,8 @@ type CrV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ActionSetsGetter
 	BlueprintsGetter
+	BlueprintLibrariesGetter
+	BlueprintRevisionsGetter
 	ProfilesGetter
 	RepositoryServersGetter
 }
@@ -47,6 +49,14 @@ func (c *CrV1alpha1Client) Blueprints(namespace string) BlueprintInterface {
 	return newBlueprints(c, namespace)
 }
 
+func (c *CrV1alpha1Client) BlueprintLibraries(namespace string) BlueprintLibraryInterface {
+	return newBlueprintLibraries(c, namespace)
+}
+
+func (c *CrV1alpha1Client) BlueprintRevisions(namespace string) BlueprintRevisionInterface {
+	return newBlueprintRevisions(c, namespace)
+}
+
 func (c *CrV1alpha1Client) Profiles(namespace string) ProfileInterface {
 	return newProfiles(c, namespace)
 }
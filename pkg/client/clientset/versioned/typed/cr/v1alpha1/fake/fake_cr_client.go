@@ -36,6 +36,14 @@ func (c *FakeCrV1alpha1) Blueprints(namespace string) v1alpha1.BlueprintInterfac
 	return &FakeBlueprints{c, namespace}
 }
 
+func (c *FakeCrV1alpha1) BlueprintLibraries(namespace string) v1alpha1.BlueprintLibraryInterface {
+	return &FakeBlueprintLibraries{c, namespace}
+}
+
+func (c *FakeCrV1alpha1) BlueprintRevisions(namespace string) v1alpha1.BlueprintRevisionInterface {
+	return &FakeBlueprintRevisions{c, namespace}
+}
+
 func (c *FakeCrV1alpha1) Profiles(namespace string) v1alpha1.ProfileInterface {
 	return &FakeProfiles{c, namespace}
 }
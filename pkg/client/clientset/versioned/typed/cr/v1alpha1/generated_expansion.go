@@ -22,6 +22,10 @@ type ActionSetExpansion interface{}
 
 type BlueprintExpansion interface{}
 
+type BlueprintLibraryExpansion interface{}
+
+type BlueprintRevisionExpansion interface{}
+
 type ProfileExpansion interface{}
 
 type RepositoryServerExpansion interface{}
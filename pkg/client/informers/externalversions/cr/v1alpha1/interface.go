@@ -28,6 +28,10 @@ type Interface interface {
 	ActionSets() ActionSetInformer
 	// Blueprints returns a BlueprintInformer.
 	Blueprints() BlueprintInformer
+	// BlueprintLibraries returns a BlueprintLibraryInformer.
+	BlueprintLibraries() BlueprintLibraryInformer
+	// BlueprintRevisions returns a BlueprintRevisionInformer.
+	BlueprintRevisions() BlueprintRevisionInformer
 	// Profiles returns a ProfileInformer.
 	Profiles() ProfileInformer
 	// RepositoryServers returns a RepositoryServerInformer.
@@ -55,6 +59,16 @@ func (v *version) Blueprints() BlueprintInformer {
 	return &blueprintInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BlueprintLibraries returns a BlueprintLibraryInformer.
+func (v *version) BlueprintLibraries() BlueprintLibraryInformer {
+	return &blueprintLibraryInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// BlueprintRevisions returns a BlueprintRevisionInformer.
+func (v *version) BlueprintRevisions() BlueprintRevisionInformer {
+	return &blueprintRevisionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Profiles returns a ProfileInformer.
 func (v *version) Profiles() ProfileInformer {
 	return &profileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
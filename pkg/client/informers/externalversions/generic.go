@@ -57,6 +57,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().ActionSets().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("blueprints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Blueprints().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("blueprintlibraries"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().BlueprintLibraries().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("blueprintrevisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().BlueprintRevisions().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("profiles"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Profiles().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("repositoryservers"):
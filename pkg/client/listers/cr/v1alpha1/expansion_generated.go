@@ -34,6 +34,22 @@ type BlueprintListerExpansion interface{}
 // BlueprintNamespaceLister.
 type BlueprintNamespaceListerExpansion interface{}
 
+// BlueprintLibraryListerExpansion allows custom methods to be added to
+// BlueprintLibraryLister.
+type BlueprintLibraryListerExpansion interface{}
+
+// BlueprintLibraryNamespaceListerExpansion allows custom methods to be added to
+// BlueprintLibraryNamespaceLister.
+type BlueprintLibraryNamespaceListerExpansion interface{}
+
+// BlueprintRevisionListerExpansion allows custom methods to be added to
+// BlueprintRevisionLister.
+type BlueprintRevisionListerExpansion interface{}
+
+// BlueprintRevisionNamespaceListerExpansion allows custom methods to be added to
+// BlueprintRevisionNamespaceLister.
+type BlueprintRevisionNamespaceListerExpansion interface{}
+
 // ProfileListerExpansion allows custom methods to be added to
 // ProfileLister.
 type ProfileListerExpansion interface{}
@@ -36,6 +36,12 @@ const (
 	BlueprintResourceNamePlural = "blueprints"
 	ProfileResourceName         = "profile"
 	ProfileResourceNamePlural   = "profiles"
+
+	BlueprintLibraryResourceName       = "blueprintlibrary"
+	BlueprintLibraryResourceNamePlural = "blueprintlibraries"
+
+	BlueprintRevisionResourceName       = "blueprintrevision"
+	BlueprintRevisionResourceNamePlural = "blueprintrevisions"
 )
 
 // These consts are used to query Repository server API objects
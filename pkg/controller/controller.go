@@ -43,6 +43,7 @@ import (
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blueprint"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned/scheme"
 	"github.com/kanisterio/kanister/pkg/consts"
@@ -52,6 +53,7 @@ import (
 	_ "github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/progress"
+	"github.com/kanisterio/kanister/pkg/quiesce"
 	"github.com/kanisterio/kanister/pkg/reconcile"
 	"github.com/kanisterio/kanister/pkg/validate"
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
@@ -67,6 +69,8 @@ type Controller struct {
 	recorder         record.EventRecorder
 	actionSetTombMap sync.Map
 	metrics          *metrics
+	// quiesceMu serializes the unquiesces of the controller
+	quiesceMu sync.Mutex
 }
 
 // New create controller for watching kanister custom resources created
@@ -110,6 +114,13 @@ func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 	c.osClient = osClient
 	c.recorder = eventer.NewEventRecorder(c.clientset, "Kanister Controller")
 
+	// Listed before watching, so that only the ActionSets of a previous run are considered
+	ass, err := crClient.CrV1alpha1().ActionSets(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to list ActionSets")
+	}
+	go c.unquiesceOrphaned(ctx, ass.Items)
+
 	for cr, o := range map[customresource.CustomResource]runtime.Object{
 		crv1alpha1.ActionSetResource: &crv1alpha1.ActionSet{},
 		crv1alpha1.BlueprintResource: &crv1alpha1.Blueprint{},
@@ -292,6 +303,14 @@ func (c *Controller) onDeleteActionSet(as *crv1alpha1.ActionSet) error {
 	}
 	t.Kill(nil) // TODO: @Deepika Give reason for ActionSet kill
 	c.actionSetTombMap.Delete(asName)
+	// The quiesces cannot be looked up anymore once the ActionSet is gone
+	if as.Status != nil {
+		for _, a := range as.Status.Actions {
+			for i := range a.Quiesces {
+				go c.unquiesce(context.Background(), &a.Quiesces[i])
+			}
+		}
+	}
 	return nil
 }
 
@@ -316,8 +335,8 @@ func (c *Controller) initActionSetStatus(ctx context.Context, as *crv1alpha1.Act
 			break
 		}
 		var bp *crv1alpha1.Blueprint
-		if bp, err = c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(ctx, a.Blueprint, v1.GetOptions{}); err != nil {
-			err = errors.Wrap(err, "Failed to query blueprint")
+		var revision string
+		if bp, revision, err = c.getBlueprint(ctx, as.GetNamespace(), a.Blueprint, a.BlueprintRevision); err != nil {
 			c.logAndErrorEvent(ctx, "Could not get blueprint:", "Error", err, as)
 			break
 		}
@@ -327,6 +346,9 @@ func (c *Controller) initActionSetStatus(ctx context.Context, as *crv1alpha1.Act
 			c.logAndErrorEvent(ctx, "Could not get initial action:", reason, err, as, bp)
 			break
 		}
+		// The actions are run with the revision of the Blueprint they were
+		// initialized with, even if it is updated in the meantime
+		actionStatus.BlueprintRevision = revision
 		actions = append(actions, *actionStatus)
 	}
 	if err != nil {
@@ -344,6 +366,29 @@ func (c *Controller) initActionSetStatus(ctx context.Context, as *crv1alpha1.Act
 	}
 }
 
+// getBlueprint returns the Blueprint with its references to other Blueprints
+// and BlueprintLibraries resolved, and the name of its revision. If the
+// revision is set, the Blueprint has the actions of that revision. Otherwise,
+// the revision of the current actions of the Blueprint is created if needed.
+func (c *Controller) getBlueprint(ctx context.Context, namespace, name, revision string) (*crv1alpha1.Blueprint, string, error) {
+	if revision != "" {
+		bp, err := blueprint.GetRevision(ctx, c.crClient, namespace, name, revision)
+		return bp, revision, err
+	}
+	bp, err := c.crClient.CrV1alpha1().Blueprints(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Failed to query blueprint")
+	}
+	bp, err = kanister.ResolveBlueprint(ctx, blueprint.NewClientGetter(c.crClient, namespace), bp)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Failed to resolve blueprint")
+	}
+	if revision, err = blueprint.EnsureRevision(ctx, c.crClient, bp); err != nil {
+		return nil, "", err
+	}
+	return bp, revision, nil
+}
+
 func (c *Controller) initialActionStatus(a crv1alpha1.ActionSpec, bp *crv1alpha1.Blueprint) (*crv1alpha1.ActionStatus, error) {
 	bpa, ok := bp.Actions[a.Name]
 	if !ok {
@@ -396,8 +441,7 @@ func (c *Controller) handleActionSet(ctx context.Context, t *tomb.Tomb, as *crv1
 
 	for i, a := range as.Status.Actions {
 		var bp *crv1alpha1.Blueprint
-		if bp, err = c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(ctx, a.Blueprint, v1.GetOptions{}); err != nil {
-			err = errors.Wrap(err, "Failed to query blueprint")
+		if bp, _, err = c.getBlueprint(ctx, as.GetNamespace(), a.Blueprint, a.BlueprintRevision); err != nil {
 			c.logAndErrorEvent(ctx, "Could not get blueprint:", "Error", err, as)
 			break
 		}
@@ -438,7 +482,11 @@ func (c *Controller) LoadOrStoreTomb(ctx context.Context, asName string) (*tomb.
 func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1.ActionSet, aIDX int, bp *crv1alpha1.Blueprint) error {
 	action := as.Spec.Actions[aIDX]
 	c.logAndSuccessEvent(ctx, fmt.Sprintf("Executing action %s", action.Name), "Started Action", as)
-	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, c.osClient, action)
+	var params []crv1alpha1.BlueprintParameter
+	if bpa, ok := bp.Actions[action.Name]; ok {
+		params = bpa.Parameters
+	}
+	tp, err := param.New(ctx, c.clientset, c.dynClient, c.crClient, c.osClient, action, params...)
 	if err != nil {
 		c.incrementActionSetResolutionCounterVec(ACTION_SET_COUNTER_VEC_LABEL_RES_FAILURE)
 		return err
@@ -458,6 +506,7 @@ func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1
 	}
 
 	ctx = field.Context(ctx, consts.ActionsetNameKey, as.GetName())
+	ctx = quiesce.WithTracker(ctx, &actionQuiesces{c: c, ns: as.GetNamespace(), name: as.GetName(), aIDX: aIDX})
 	t.Go(func() error {
 		var coreErr error
 		defer func() {
@@ -466,6 +515,8 @@ func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1
 				c.updateActionSetRunningPhase(ctx, aIDX, as, deferPhase.Name())
 				deferErr = c.executeDeferPhase(ctx, deferPhase, tp, bp, action.Name, aIDX, as)
 			}
+			// unquiesce the application if the phases did not
+			c.unquiesceAction(ctx, as, aIDX)
 			// render artifacts only if all the phases are run successfully
 			if deferErr == nil && coreErr == nil {
 				c.renderActionsetArtifacts(ctx, as, aIDX, as.Namespace, as.Name, action.Name, bp, tp, coreErr, deferErr)
@@ -498,6 +549,7 @@ func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1
 				msg = fmt.Sprintf("Failed to init phase params: %#v:", as.Status.Actions[aIDX].Phases[i])
 			}
 
+			var quiesced *crv1alpha1.QuiesceStatus
 			var rf func(*crv1alpha1.ActionSet) error
 			if err != nil {
 				coreErr = err
@@ -514,6 +566,10 @@ func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1
 				coreErr = nil
 				rf = func(ras *crv1alpha1.ActionSet) error {
 					ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateComplete
+					var err error
+					if quiesced, err = recordQuiesce(&ras.Status.Actions[aIDX], output); err != nil {
+						log.Error().WithError(err).Print("Failed to record quiesce")
+					}
 					pp, err := p.Progress()
 					if err != nil {
 						log.Error().WithError(err)
@@ -546,6 +602,9 @@ func (c *Controller) runAction(ctx context.Context, t *tomb.Tomb, as *crv1alpha1
 				coreErr = err
 				return nil
 			}
+			if quiesced != nil {
+				c.watchQuiesceLease(ctx, as, aIDX, quiesced)
+			}
 			param.UpdatePhaseParams(ctx, tp, p.Name(), output)
 			c.logAndSuccessEvent(ctx, fmt.Sprintf("Completed phase %s", p.Name()), "Ended Phase", as)
 		}
@@ -607,6 +666,10 @@ func (c *Controller) executeDeferPhase(ctx context.Context,
 		rf = func(as *crv1alpha1.ActionSet) error {
 			as.Status.Actions[aIDX].DeferPhase.State = crv1alpha1.StateComplete
 			as.Status.Actions[aIDX].DeferPhase.Output = output
+			// the application is unquiesced at the end of the action anyway
+			if _, err := recordQuiesce(&as.Status.Actions[aIDX], output); err != nil {
+				log.Error().WithError(err).Print("Failed to record quiesce")
+			}
 			return nil
 		}
 	}
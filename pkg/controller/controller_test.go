@@ -895,6 +895,51 @@ func (s *ControllerSuite) TestPhaseOutputAsArtifact(c *C) {
 	c.Assert(keyVal, DeepEquals, map[string]string{"key": "myValue"})
 }
 
+func (s *ControllerSuite) TestBlueprintRevision(c *C) {
+	ctx := context.Background()
+	bp := newBPWithOutputArtifact()
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp, err := s.crCli.Blueprints(s.namespace).Create(ctx, bp, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion, testAction)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(ctx, as, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateComplete)
+	c.Assert(err, IsNil)
+
+	// The ActionSet records the revision of the Blueprint it ran
+	as, err = s.crCli.ActionSets(s.namespace).Get(ctx, as.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	revision := as.Status.Actions[0].BlueprintRevision
+	c.Assert(revision, Not(Equals), "")
+	_, err = s.crCli.BlueprintRevisions(s.namespace).Get(ctx, revision, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+
+	// Actions pinned to the revision are not affected by updates of the Blueprint
+	bp, err = s.crCli.Blueprints(s.namespace).Get(ctx, bp.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	bp.Actions[testAction].OutputArtifacts = map[string]crv1alpha1.Artifact{
+		"otherArt": bp.Actions[testAction].OutputArtifacts["myArt"],
+	}
+	_, err = s.crCli.Blueprints(s.namespace).Update(ctx, bp, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+
+	as = testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace, kanister.DefaultVersion, testAction)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as.Spec.Actions[0].BlueprintRevision = revision
+	as, err = s.crCli.ActionSets(s.namespace).Create(ctx, as, metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateComplete)
+	c.Assert(err, IsNil)
+	as, err = s.crCli.ActionSets(s.namespace).Get(ctx, as.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(as.Status.Actions[0].BlueprintRevision, Equals, revision)
+	c.Assert(as.Status.Actions[0].Artifacts, HasLen, 1)
+	c.Assert(as.Status.Actions[0].Artifacts["myArt"].KeyValue, DeepEquals, map[string]string{"key": "myValue"})
+}
+
 func (s *ControllerSuite) TestPhaseOutputAsKopiaSnapshot(c *C) {
 	ctx := context.Background()
 	// Create a blueprint that uses func output as kopia snapshot
@@ -0,0 +1,170 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/quiesce"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+)
+
+// recordQuiesce updates the quiesces of the action with the output of a
+// phase. It returns the quiesce created by the phase, if any.
+func recordQuiesce(status *crv1alpha1.ActionStatus, output map[string]interface{}) (*crv1alpha1.QuiesceStatus, error) {
+	q, unquiesced, err := quiesce.FromOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	if unquiesced != "" {
+		quiesce.Remove(status, unquiesced)
+	}
+	if q != nil {
+		// Replaces the record of the quiesce kept before it was done
+		quiesce.Remove(status, q.ID)
+		status.Quiesces = append(status.Quiesces, *q)
+	}
+	return q, nil
+}
+
+// actionQuiesces keeps the quiesces of an action in the ActionSet status. It
+// is passed to the phases of the action through the context.
+type actionQuiesces struct {
+	c        *Controller
+	ns, name string
+	aIDX     int
+}
+
+var _ quiesce.Tracker = (*actionQuiesces)(nil)
+
+func (a *actionQuiesces) Record(ctx context.Context, q *crv1alpha1.QuiesceStatus) error {
+	a.c.quiesceMu.Lock()
+	defer a.c.quiesceMu.Unlock()
+
+	err := reconcile.ActionSet(ctx, a.c.crClient.CrV1alpha1(), a.ns, a.name, func(ras *crv1alpha1.ActionSet) error {
+		quiesce.Remove(&ras.Status.Actions[a.aIDX], q.ID)
+		ras.Status.Actions[a.aIDX].Quiesces = append(ras.Status.Actions[a.aIDX].Quiesces, *q)
+		return nil
+	})
+	return errors.Wrap(err, "Failed to record quiesce in ActionSet status")
+}
+
+func (a *actionQuiesces) Forget(ctx context.Context, id string) error {
+	a.c.quiesceMu.Lock()
+	defer a.c.quiesceMu.Unlock()
+
+	err := reconcile.ActionSet(ctx, a.c.crClient.CrV1alpha1(), a.ns, a.name, func(ras *crv1alpha1.ActionSet) error {
+		quiesce.Remove(&ras.Status.Actions[a.aIDX], id)
+		return nil
+	})
+	return errors.Wrap(err, "Failed to remove quiesce from ActionSet status")
+}
+
+func (a *actionQuiesces) Unquiesce(ctx context.Context, id string) error {
+	return a.c.unquiesceRecorded(ctx, a.ns, a.name, a.aIDX, id)
+}
+
+// watchQuiesceLease unquiesces the application when the lease of the quiesce
+// expires, unless the quiesce was undone before.
+func (c *Controller) watchQuiesceLease(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int, q *crv1alpha1.QuiesceStatus) {
+	ns, name, id := as.GetNamespace(), as.GetName(), q.ID
+	time.AfterFunc(time.Until(q.ExpiresAt.Time), func() {
+		log.WithContext(ctx).Print("Quiesce lease expired", field.M{"ID": id})
+		// The action could have ended and canceled its context
+		_ = c.unquiesceRecorded(context.Background(), ns, name, aIDX, id)
+	})
+}
+
+// unquiesceAction unquiesces the application for every quiesce the action
+// did not undo.
+func (c *Controller) unquiesceAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) {
+	cur, err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Get(ctx, as.GetName(), v1.GetOptions{})
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Print("Failed to get ActionSet to unquiesce the application")
+		return
+	}
+	if cur.Status == nil || aIDX >= len(cur.Status.Actions) {
+		return
+	}
+	for _, q := range cur.Status.Actions[aIDX].Quiesces {
+		_ = c.unquiesceRecorded(ctx, as.GetNamespace(), as.GetName(), aIDX, q.ID)
+	}
+}
+
+// unquiesceRecorded unquiesces the application and removes the quiesce from
+// the ActionSet status, unless it is not there anymore because it was undone
+// already. The quiesce is removed even if unquiescing failed, since retrying
+// would most likely fail again. The unquiesces are serialized, so that the
+// lease, the end of the action and the Unquiesce function do not undo a
+// quiesce twice.
+func (c *Controller) unquiesceRecorded(ctx context.Context, ns, name string, aIDX int, id string) error {
+	c.quiesceMu.Lock()
+	defer c.quiesceMu.Unlock()
+
+	as, err := c.crClient.CrV1alpha1().ActionSets(ns).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Print("Failed to get ActionSet to unquiesce the application", field.M{"ActionSet": name, "ID": id})
+		return errors.Wrap(err, "Failed to get ActionSet to unquiesce the application")
+	}
+	if as.Status == nil || aIDX >= len(as.Status.Actions) {
+		return nil
+	}
+	q := quiesce.Remove(&as.Status.Actions[aIDX], id)
+	if q == nil {
+		// Already undone
+		return nil
+	}
+	uErr := c.unquiesce(ctx, q)
+	err = reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
+		quiesce.Remove(&ras.Status.Actions[aIDX], id)
+		return nil
+	})
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Print("Failed to remove quiesce from ActionSet status", field.M{"ActionSet": name, "ID": id})
+	}
+	return uErr
+}
+
+func (c *Controller) unquiesce(ctx context.Context, q *crv1alpha1.QuiesceStatus) error {
+	fields := field.M{"ID": q.ID, "Handler": q.Handler, "Namespace": q.Namespace}
+	if err := quiesce.Unquiesce(ctx, c.clientset, q); err != nil {
+		log.WithContext(ctx).WithError(err).Print("Failed to unquiesce the application", fields)
+		return err
+	}
+	log.WithContext(ctx).Print("Unquiesced the application", fields)
+	return nil
+}
+
+// unquiesceOrphaned unquiesces the applications quiesced by ActionSets of a
+// previous controller run, which cannot undo the quiesces anymore.
+func (c *Controller) unquiesceOrphaned(ctx context.Context, ass []*crv1alpha1.ActionSet) {
+	for _, as := range ass {
+		if as.Status == nil {
+			continue
+		}
+		for aIDX, a := range as.Status.Actions {
+			for _, q := range a.Quiesces {
+				_ = c.unquiesceRecorded(ctx, as.GetNamespace(), as.GetName(), aIDX, q.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+	"github.com/kanisterio/kanister/pkg/function"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/poll"
+	"github.com/kanisterio/kanister/pkg/quiesce"
+)
+
+type QuiesceSuite struct{}
+
+var _ = Suite(&QuiesceSuite{})
+
+// newQuiescedController returns a controller with fake clients, a Deployment
+// scaled to zero and an ActionSet whose action recorded the quiesce.
+func newQuiescedController(q crv1alpha1.QuiesceStatus) (*Controller, *crv1alpha1.ActionSet) {
+	var zero int32
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "as", Namespace: "ns"},
+		Spec: &crv1alpha1.ActionSetSpec{Actions: []crv1alpha1.ActionSpec{{
+			Name:      "backup",
+			Blueprint: "bp",
+			Object:    crv1alpha1.ObjectReference{Kind: param.DeploymentKind, Namespace: "ns", Name: "app"},
+		}}},
+		Status: &crv1alpha1.ActionSetStatus{
+			State:   crv1alpha1.StateRunning,
+			Actions: []crv1alpha1.ActionStatus{{Name: "backup", Quiesces: []crv1alpha1.QuiesceStatus{q}}},
+		},
+	}
+	c := &Controller{
+		crClient: crfake.NewSimpleClientset(as),
+		clientset: fake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+		}),
+	}
+	return c, as
+}
+
+func scaleToZeroQuiesce(expiresAt time.Time) crv1alpha1.QuiesceStatus {
+	return crv1alpha1.QuiesceStatus{
+		ID:        "id",
+		Handler:   quiesce.HandlerScaleToZero,
+		Namespace: "ns",
+		Kind:      param.DeploymentKind,
+		Name:      "app",
+		Replicas:  2,
+		ExpiresAt: metav1.NewTime(expiresAt),
+	}
+}
+
+func (s *QuiesceSuite) replicas(c *C, ctrl *Controller) int32 {
+	d, err := ctrl.clientset.AppsV1().Deployments("ns").Get(context.Background(), "app", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	return *d.Spec.Replicas
+}
+
+func (s *QuiesceSuite) quiesces(c *C, ctrl *Controller) []crv1alpha1.QuiesceStatus {
+	as, err := ctrl.crClient.CrV1alpha1().ActionSets("ns").Get(context.Background(), "as", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	return as.Status.Actions[0].Quiesces
+}
+
+func (s *QuiesceSuite) TestQuiesceLeaseExpiry(c *C) {
+	ctx := context.Background()
+	q := scaleToZeroQuiesce(time.Now().Add(100 * time.Millisecond))
+	ctrl, as := newQuiescedController(q)
+	ctrl.watchQuiesceLease(ctx, as, 0, &q)
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	err := poll.Wait(ctxTimeout, func(context.Context) (bool, error) {
+		return len(s.quiesces(c, ctrl)) == 0, nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(s.replicas(c, ctrl), Equals, int32(2))
+
+	// The Unquiesce function of a phase that was running when the lease
+	// expired does not undo the quiesce again
+	err = kube.ScaleDeployment(ctx, ctrl.clientset, "ns", "app", 0, false)
+	c.Assert(err, IsNil)
+	q.ExpiresAt = metav1.NewTime(time.Now().Add(time.Minute))
+	out, err := quiesce.Marshal(&q)
+	c.Assert(err, IsNil)
+	f := kanister.KanisterFuncForName(function.UnquiesceFuncName, kanister.DefaultVersion)
+	tctx := quiesce.WithTracker(ctx, &actionQuiesces{c: ctrl, ns: "ns", name: "as"})
+	output, err := f.Exec(tctx, param.TemplateParams{}, map[string]interface{}{function.UnquiesceQuiesceArg: out})
+	c.Assert(err, IsNil)
+	c.Assert(output, DeepEquals, map[string]interface{}{quiesce.OutputUnquiesced: "id"})
+	c.Assert(s.replicas(c, ctrl), Equals, int32(0))
+}
+
+func (s *QuiesceSuite) TestQuiesceRestartCleanup(c *C) {
+	ctx := context.Background()
+	// A quiesce recorded before the application was quiesced has no lease yet
+	q := scaleToZeroQuiesce(time.Time{})
+	ctrl, as := newQuiescedController(q)
+	ctrl.unquiesceOrphaned(ctx, []*crv1alpha1.ActionSet{as})
+	c.Assert(s.replicas(c, ctrl), Equals, int32(2))
+	c.Assert(s.quiesces(c, ctrl), HasLen, 0)
+
+	// Nothing is left to undo
+	ctrl.unquiesceOrphaned(ctx, []*crv1alpha1.ActionSet{as})
+	c.Assert(s.replicas(c, ctrl), Equals, int32(2))
+}
+
+func (s *QuiesceSuite) TestActionQuiesces(c *C) {
+	ctx := context.Background()
+	q := scaleToZeroQuiesce(time.Time{})
+	ctrl, _ := newQuiescedController(q)
+	t := &actionQuiesces{c: ctrl, ns: "ns", name: "as"}
+
+	// The record of the phase output replaces the pending one
+	pending := q
+	pending.ID = "pending"
+	c.Assert(t.Record(ctx, &pending), IsNil)
+	c.Assert(s.quiesces(c, ctrl), HasLen, 2)
+	status := &crv1alpha1.ActionStatus{Quiesces: s.quiesces(c, ctrl)}
+	pending.ExpiresAt = metav1.NewTime(time.Now().Add(time.Minute))
+	out, err := quiesce.Marshal(&pending)
+	c.Assert(err, IsNil)
+	_, err = recordQuiesce(status, map[string]interface{}{quiesce.OutputQuiesce: out})
+	c.Assert(err, IsNil)
+	c.Assert(status.Quiesces, HasLen, 2)
+	c.Assert(status.Quiesces[1].ExpiresAt.IsZero(), Equals, false)
+
+	c.Assert(t.Forget(ctx, "pending"), IsNil)
+	c.Assert(s.quiesces(c, ctrl), HasLen, 1)
+	c.Assert(t.Unquiesce(ctx, "id"), IsNil)
+	c.Assert(s.quiesces(c, ctrl), HasLen, 0)
+	c.Assert(s.replicas(c, ctrl), Equals, int32(2))
+}
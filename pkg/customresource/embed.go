@@ -2,8 +2,9 @@ package customresource
 
 import "embed"
 
-// embed.go embeds the CRD yamls (actionset, profile, blueprint) with the
-// controller binary so that we can read these manifests in runtime.
+// embed.go embeds the CRD yamls (actionset, profile, blueprint, blueprintlibrary,
+// blueprintrevision) with the controller binary so that we can read these
+// manifests in runtime.
 
 // We need these manfiests at two places, at `pkg/customresource/` and at
 // `helm/kanister-operator/crds`. To make sure we are not duplicating the
@@ -12,6 +13,8 @@ import "embed"
 
 //go:embed actionset.yaml
 //go:embed blueprint.yaml
+//go:embed blueprintlibrary.yaml
+//go:embed blueprintrevision.yaml
 //go:embed profile.yaml
 //go:embed repositoryserver.yaml
 var yamls embed.FS
@@ -16,6 +16,7 @@ package datamover
 
 import (
 	"context"
+	"io"
 
 	"github.com/pkg/errors"
 
@@ -68,6 +69,37 @@ func (p *profile) Push(ctx context.Context, sourcePath, destinationPath string)
 	return locationPush(ctx, p.profile, destinationPath, source)
 }
 
+// Read streams the data at path into target instead of a file or stdout
+func (p *profile) Read(ctx context.Context, target io.Writer, path string) error {
+	if p.profile.Location.Type == crv1alpha1.LocationTypeKopia {
+		kopiaSnap, err := p.unmarshalKopiaSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+		if err := p.connectToKopiaRepositoryServer(ctx); err != nil {
+			return err
+		}
+		return snapshot.Read(ctx, target, kopiaSnap.ID, path, p.profile.Credential.KopiaServerSecret.Password)
+	}
+	return locationPull(ctx, p.profile, path, target)
+}
+
+// Write uploads the data read from source to path. It returns the snapshot
+// info for Kopia profiles and an empty string for other locations.
+func (p *profile) Write(ctx context.Context, source io.Reader, path string) (string, error) {
+	if p.profile.Location.Type == crv1alpha1.LocationTypeKopia {
+		if err := p.connectToKopiaRepositoryServer(ctx); err != nil {
+			return "", err
+		}
+		snapInfo, err := snapshot.Write(ctx, io.NopCloser(source), path, p.profile.Credential.KopiaServerSecret.Password)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to push data using kopia")
+		}
+		return snapshot.MarshalKopiaSnapshot(snapInfo)
+	}
+	return "", locationPush(ctx, p.profile, path, source)
+}
+
 func (p *profile) Delete(ctx context.Context, destinationPath string) error {
 	if p.profile.Location.Type == crv1alpha1.LocationTypeKopia {
 		kopiaSnap, err := p.unmarshalKopiaSnapshot(ctx)
@@ -15,6 +15,8 @@
 package function
 
 import (
+	"encoding/json"
+
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/yaml"
@@ -107,3 +109,30 @@ func GetYamlList(args map[string]interface{}, argName string) ([]string, error)
 	}
 	return nil, errors.Errorf("Invalid %s arg format", argName)
 }
+
+// GetSpecArg decodes an optional arg holding Kubernetes API objects, e.g. a list
+// of containers. The value can be in either of two formats:
+// a YAML formatted string if you are referencing from configmap or from inputArtifacts
+// OR
+// a list or map in the blueprint
+// The result is left untouched if the arg is not present.
+func GetSpecArg(args map[string]interface{}, argName string, result interface{}) error {
+	val, ok := args[argName]
+	if !ok {
+		return nil
+	}
+	var data []byte
+	switch v := val.(type) {
+	case string:
+		data = []byte(v)
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return errors.Wrapf(err, "Failed to encode arg `%s`", argName)
+		}
+	}
+	if err := yaml.UnmarshalStrict(data, result); err != nil {
+		return errors.Wrapf(err, "Failed to decode arg `%s`", argName)
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ package function
 
 import (
 	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kanisterio/kanister/pkg/param"
 )
 
 var _ = Suite(&ArgsTestSuite{})
@@ -78,3 +81,126 @@ func (s *ArgsTestSuite) TestGetYamlList(c *C) {
 		c.Check(valList, DeepEquals, tc.valList, Commentf("Test: %s Failed!", tc.name))
 	}
 }
+
+func (s *ArgsTestSuite) TestGetSpecArg(c *C) {
+	for _, tc := range []struct {
+		name       string
+		args       map[string]interface{}
+		errChecker Checker
+		containers []v1.Container
+	}{
+		{
+			name:       "Missing arg",
+			args:       map[string]interface{}{},
+			errChecker: IsNil,
+		},
+		{
+			name: "Pass key as YAML string",
+			args: map[string]interface{}{
+				"key": "- name: c1\n  image: busybox\n  command: [sh, -c, date]\n",
+			},
+			errChecker: IsNil,
+			containers: []v1.Container{{Name: "c1", Image: "busybox", Command: []string{"sh", "-c", "date"}}},
+		},
+		{
+			name: "Pass key as list",
+			args: map[string]interface{}{
+				"key": []interface{}{
+					map[string]interface{}{
+						"name":         "c1",
+						"image":        "busybox",
+						"volumeMounts": []interface{}{map[string]interface{}{"name": "data", "mountPath": "/data"}},
+					},
+				},
+			},
+			errChecker: IsNil,
+			containers: []v1.Container{{Name: "c1", Image: "busybox", VolumeMounts: []v1.VolumeMount{{Name: "data", MountPath: "/data"}}}},
+		},
+		{
+			name: "Unknown field",
+			args: map[string]interface{}{
+				"key": "- name: c1\n  imag: busybox\n",
+			},
+			errChecker: NotNil,
+		},
+	} {
+		var containers []v1.Container
+		err := GetSpecArg(tc.args, "key", &containers)
+		c.Check(err, tc.errChecker, Commentf("Failed for %s", tc.name))
+		if err == nil {
+			c.Check(containers, DeepEquals, tc.containers, Commentf("Failed for %s", tc.name))
+		}
+	}
+}
+
+func (s *ArgsTestSuite) TestGetStdinFrom(c *C) {
+	tp := param.TemplateParams{Profile: &param.Profile{}}
+	for _, tc := range []struct {
+		name       string
+		tp         param.TemplateParams
+		args       map[string]interface{}
+		errChecker Checker
+		stdinFrom  *StdinFrom
+	}{
+		{
+			name:       "Missing arg",
+			tp:         tp,
+			args:       map[string]interface{}{},
+			errChecker: IsNil,
+		},
+		{
+			name: "Path",
+			tp:   tp,
+			args: map[string]interface{}{
+				StdinFromArg: map[string]interface{}{"path": "backups/dump.sql"},
+			},
+			errChecker: IsNil,
+			stdinFrom:  &StdinFrom{Path: "backups/dump.sql"},
+		},
+		{
+			name: "Kopia snapshot",
+			tp:   tp,
+			args: map[string]interface{}{
+				StdinFromArg: map[string]interface{}{"path": "backups/dump.sql", "kopiaSnapshot": "{}"},
+			},
+			errChecker: IsNil,
+			stdinFrom:  &StdinFrom{Path: "backups/dump.sql", KopiaSnapshot: "{}"},
+		},
+		{
+			name: "Missing path",
+			tp:   tp,
+			args: map[string]interface{}{
+				StdinFromArg: map[string]interface{}{"kopiaSnapshot": "{}"},
+			},
+			errChecker: NotNil,
+		},
+		{
+			name: "Missing profile",
+			args: map[string]interface{}{
+				StdinFromArg: map[string]interface{}{"path": "backups/dump.sql"},
+			},
+			errChecker: NotNil,
+		},
+	} {
+		sf, err := GetStdinFrom(tc.tp, tc.args)
+		c.Check(err, tc.errChecker, Commentf("Failed for %s", tc.name))
+		c.Check(sf, DeepEquals, tc.stdinFrom, Commentf("Failed for %s", tc.name))
+	}
+}
+
+func (s *ArgsTestSuite) TestGetStdoutTo(c *C) {
+	tp := param.TemplateParams{Profile: &param.Profile{}}
+	st, err := GetStdoutTo(tp, map[string]interface{}{})
+	c.Assert(err, IsNil)
+	c.Assert(st, IsNil)
+
+	args := map[string]interface{}{StdoutToArg: map[string]interface{}{"path": "backups/dump.sql"}}
+	st, err = GetStdoutTo(tp, args)
+	c.Assert(err, IsNil)
+	c.Assert(st, DeepEquals, &StdoutTo{Path: "backups/dump.sql"})
+
+	_, err = GetStdoutTo(param.TemplateParams{}, args)
+	c.Assert(err, NotNil)
+	_, err = GetStdoutTo(tp, map[string]interface{}{StdoutToArg: map[string]interface{}{}})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,271 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/kube/volume"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+	"github.com/kanisterio/kanister/pkg/restic"
+)
+
+const (
+	// BackupCSISnapshotDataFuncName gives the function name
+	BackupCSISnapshotDataFuncName = "BackupCSISnapshotData"
+	// BackupCSISnapshotDataNamespaceArg is the namespace of the volume
+	BackupCSISnapshotDataNamespaceArg = "namespace"
+	// BackupCSISnapshotDataVolumeArg is the name of the PVC to back up
+	BackupCSISnapshotDataVolumeArg = "volume"
+	// BackupCSISnapshotDataSnapshotClassArg is the VolumeSnapshotClass of the temporary snapshot
+	BackupCSISnapshotDataSnapshotClassArg = "snapshotClass"
+	// BackupCSISnapshotDataStorageClassArg is the storage class of the temporary PVC
+	BackupCSISnapshotDataStorageClassArg = "storageClass"
+	// BackupCSISnapshotDataArtifactPrefixArg copies the data to the Profile with restic instead of Kopia
+	BackupCSISnapshotDataArtifactPrefixArg = "dataArtifactPrefix"
+	// BackupCSISnapshotDataEncryptionKeyArg is the restic encryption key
+	BackupCSISnapshotDataEncryptionKeyArg = "encryptionKey"
+	// BackupCSISnapshotDataPodOverrideArg contains pod specs to override default pod specs
+	BackupCSISnapshotDataPodOverrideArg = "podOverride"
+
+	backupCSISnapshotDataPrefix = "backup-csi-snapshot-"
+)
+
+func init() {
+	_ = kanister.Register(&backupCSISnapshotDataFunc{})
+}
+
+var _ kanister.Func = (*backupCSISnapshotDataFunc)(nil)
+
+type backupCSISnapshotDataFunc struct {
+	progressPercent string
+}
+
+type backupCSISnapshotDataArgs struct {
+	Namespace     string
+	Volume        string
+	SnapshotClass string
+	StorageClass  string
+	TargetPath    string
+	EncryptionKey string
+	Tags          []string
+	UserHostname  string
+	PodOverride   crv1alpha1.JSONMap
+}
+
+func (*backupCSISnapshotDataFunc) Name() string {
+	return BackupCSISnapshotDataFuncName
+}
+
+func (b *backupCSISnapshotDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	b.progressPercent = progress.StartedPercent
+	defer func() { b.progressPercent = progress.CompletedPercent }()
+
+	var bArgs backupCSISnapshotDataArgs
+	var tagsStr string
+	var err error
+	if err = Arg(args, BackupCSISnapshotDataNamespaceArg, &bArgs.Namespace); err != nil {
+		return nil, err
+	}
+	if err = Arg(args, BackupCSISnapshotDataVolumeArg, &bArgs.Volume); err != nil {
+		return nil, err
+	}
+	if err = Arg(args, BackupCSISnapshotDataSnapshotClassArg, &bArgs.SnapshotClass); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupCSISnapshotDataStorageClassArg, &bArgs.StorageClass, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupCSISnapshotDataArtifactPrefixArg, &bArgs.TargetPath, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupCSISnapshotDataEncryptionKeyArg, &bArgs.EncryptionKey, restic.GeneratePassword()); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupDataUsingKopiaServerSnapshotTagsArg, &tagsStr, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, KopiaRepositoryServerUserHostname, &bArgs.UserHostname, ""); err != nil {
+		return nil, err
+	}
+	if bArgs.PodOverride, err = GetPodSpecOverride(tp, args, BackupCSISnapshotDataPodOverrideArg); err != nil {
+		return nil, err
+	}
+	if tagsStr != "" {
+		bArgs.Tags = strings.Split(tagsStr, ",")
+	}
+	if bArgs.TargetPath != "" {
+		if err = ValidateProfile(tp.Profile); err != nil {
+			return nil, errors.Wrapf(err, "Failed to validate Profile")
+		}
+		bArgs.TargetPath = ResolveArtifactPrefix(bArgs.TargetPath, tp.Profile)
+	}
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	dynCli, err := kube.NewDynamicClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create dynamic Kubernetes client")
+	}
+	snapshotter, err := snapshot.NewSnapshotter(cli, dynCli)
+	if err != nil {
+		return nil, err
+	}
+	return backupCSISnapshotData(ctx, cli, dynCli, snapshotter, tp, bArgs)
+}
+
+func (*backupCSISnapshotDataFunc) RequiredArgs() []string {
+	return []string{
+		BackupCSISnapshotDataNamespaceArg,
+		BackupCSISnapshotDataVolumeArg,
+		BackupCSISnapshotDataSnapshotClassArg,
+	}
+}
+
+func (*backupCSISnapshotDataFunc) Arguments() []string {
+	return []string{
+		BackupCSISnapshotDataNamespaceArg,
+		BackupCSISnapshotDataVolumeArg,
+		BackupCSISnapshotDataSnapshotClassArg,
+		BackupCSISnapshotDataStorageClassArg,
+		BackupCSISnapshotDataArtifactPrefixArg,
+		BackupCSISnapshotDataEncryptionKeyArg,
+		BackupCSISnapshotDataPodOverrideArg,
+		BackupDataUsingKopiaServerSnapshotTagsArg,
+		KopiaRepositoryServerUserHostname,
+	}
+}
+
+func (*backupCSISnapshotDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: BackupCSISnapshotDataNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVC"},
+		{Name: BackupCSISnapshotDataVolumeArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to back up"},
+		{Name: BackupCSISnapshotDataSnapshotClassArg, Type: kanister.ArgTypeString, Description: "VolumeSnapshotClass of the temporary snapshot"},
+		{Name: BackupCSISnapshotDataStorageClassArg, Type: kanister.ArgTypeString, Description: "Storage class of the temporary PVC, defaults to the one of the PVC"},
+		{Name: BackupCSISnapshotDataArtifactPrefixArg, Type: kanister.ArgTypeString, Description: "Copies the data to this path of the Profile with restic instead of Kopia"},
+		encryptionKeyArgSchema(BackupCSISnapshotDataEncryptionKeyArg),
+		podOverrideArgSchema(BackupCSISnapshotDataPodOverrideArg),
+		{Name: BackupDataUsingKopiaServerSnapshotTagsArg, Type: kanister.ArgTypeString, Description: `Tags of the Kopia snapshot, e.g. "key1:value1,key2:value2"`},
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*backupCSISnapshotDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataOutputBackupID, Type: kanister.OutputTypeString, Description: "ID of the Kopia snapshot"},
+		{Name: BackupDataOutputBackupSize, Type: kanister.OutputTypeString, Description: "Logical size of the backup"},
+		{Name: BackupDataOutputBackupPhysicalSize, Type: kanister.OutputTypeString, Description: "Size uploaded to the repository"},
+		{Name: BackupVolumeOutputKopiaSnapshot, Type: kanister.OutputTypeString, Description: "Kopia snapshot info to restore the volume with RestoreVolume"},
+		{Name: CopyVolumeDataOutputBackupRoot, Type: kanister.OutputTypeString, Description: "Path of the volume in the restic snapshot, if dataArtifactPrefix is set"},
+		{Name: CopyVolumeDataOutputBackupArtifactLocation, Type: kanister.OutputTypeString, Description: "Location of the restic repository, if dataArtifactPrefix is set"},
+		{Name: CopyVolumeDataOutputBackupTag, Type: kanister.OutputTypeString, Description: "Tag of the restic snapshot, if dataArtifactPrefix is set"},
+		{Name: CopyVolumeDataOutputBackupFileCount, Type: kanister.OutputTypeString, Description: "Number of files in the restic snapshot, if dataArtifactPrefix is set"},
+		versionOutput,
+	}
+}
+
+func (b *backupCSISnapshotDataFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    b.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
+
+// backupCSISnapshotData snapshots the volume, restores the snapshot to a
+// temporary PVC and copies its data. The snapshot and the PVC are deleted
+// afterwards, even if the backup failed.
+func backupCSISnapshotData(
+	ctx context.Context,
+	cli kubernetes.Interface,
+	dynCli dynamic.Interface,
+	snapshotter snapshot.Snapshotter,
+	tp param.TemplateParams,
+	args backupCSISnapshotDataArgs,
+) (map[string]interface{}, error) {
+	// Fail before creating anything if the credentials are missing
+	var hostname, fingerprint, userPassphrase string
+	if args.TargetPath == "" {
+		var err error
+		if hostname, fingerprint, userPassphrase, err = repositoryServerUserCredentials(tp, args.UserHostname); err != nil {
+			return nil, err
+		}
+	}
+	source, err := cli.CoreV1().PersistentVolumeClaims(args.Namespace).Get(ctx, args.Volume, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve PVC. Namespace %s, Name %s", args.Namespace, args.Volume)
+	}
+
+	snapName := backupCSISnapshotDataPrefix + rand.String(10)
+	// Parent context could already be dead, so cleaning up within new context
+	defer func() {
+		if _, err := snapshotter.Delete(context.Background(), snapName, args.Namespace); err != nil {
+			log.WithContext(ctx).WithError(err).Print("Failed to delete temporary VolumeSnapshot", field.M{"VolumeSnapshot": snapName})
+		}
+	}()
+	if err := snapshotter.Create(ctx, snapName, args.Namespace, args.Volume, &args.SnapshotClass, true, nil); err != nil {
+		return nil, errors.Wrapf(err, "Failed to create VolumeSnapshot of PVC %s", args.Volume)
+	}
+
+	storageClass := args.StorageClass
+	if storageClass == "" && source.Spec.StorageClassName != nil {
+		storageClass = *source.Spec.StorageClassName
+	}
+	pvcName, err := volume.CreatePVCFromSnapshot(ctx, &volume.CreatePVCFromSnapshotArgs{
+		KubeCli:          cli,
+		DynCli:           dynCli,
+		Namespace:        args.Namespace,
+		StorageClassName: storageClass,
+		SnapshotName:     snapName,
+		VolumeMode:       source.Spec.VolumeMode,
+		AccessModes:      source.Spec.AccessModes,
+		GroupVersion:     snapshotter.GroupVersion(ctx),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to restore VolumeSnapshot %s", snapName)
+	}
+	defer func() {
+		if err := cli.CoreV1().PersistentVolumeClaims(args.Namespace).Delete(context.Background(), pvcName, metav1.DeleteOptions{}); err != nil {
+			log.WithContext(ctx).WithError(err).Print("Failed to delete temporary PVC", field.M{"PVC": pvcName})
+		}
+	}()
+
+	// The data is mounted at the path of the source PVC, so that it can be
+	// restored with RestoreData or RestoreVolume like a backup of the PVC
+	if args.TargetPath != "" {
+		mountPoint := fmt.Sprintf(CopyVolumeDataMountPoint, args.Volume)
+		return copyVolumeData(ctx, cli, tp, args.Namespace, pvcName, mountPoint, args.TargetPath, args.EncryptionKey, args.PodOverride)
+	}
+	return backupVolume(ctx, cli, args.Namespace, pvcName, args.Volume, false, hostname, fingerprint, userPassphrase, args.Tags, tp, args.PodOverride)
+}
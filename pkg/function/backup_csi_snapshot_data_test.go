@@ -0,0 +1,79 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type BackupCSISnapshotDataSuite struct{}
+
+var _ = Suite(&BackupCSISnapshotDataSuite{})
+
+const backupCSISnapshotDataNS = "test-backup-csi-snapshot-data"
+
+func (s *BackupCSISnapshotDataSuite) TestBackupCSISnapshotDataCleanup(c *C) {
+	fakeCli := fake.NewSimpleClientset()
+	fakeCli.Resources = []*metav1.APIResourceList{{GroupVersion: "snapshot.storage.k8s.io/v1"}}
+	dynCli := dynfake.NewSimpleDynamicClient(runtime.NewScheme())
+	snapshotter, err := snapshot.NewSnapshotter(fakeCli, dynCli)
+	c.Assert(err, IsNil)
+
+	args := backupCSISnapshotDataArgs{
+		Namespace:     backupCSISnapshotDataNS,
+		Volume:        originalPVCName,
+		SnapshotClass: snapshotClass,
+		TargetPath:    "s3://bucket/path",
+	}
+
+	// Kopia requires a RepositoryServer
+	kopiaArgs := args
+	kopiaArgs.TargetPath = ""
+	_, err = backupCSISnapshotData(context.Background(), fakeCli, dynCli, snapshotter, param.TemplateParams{}, kopiaArgs)
+	c.Assert(err, NotNil)
+
+	// The PVC does not exist
+	_, err = backupCSISnapshotData(context.Background(), fakeCli, dynCli, snapshotter, param.TemplateParams{}, args)
+	c.Assert(err, NotNil)
+
+	// The snapshot never becomes ready and is deleted after the timeout
+	createPVC(c, backupCSISnapshotDataNS, getOriginalPVCManifest(originalPVCName, storageClass), fakeCli)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = backupCSISnapshotData(ctx, fakeCli, dynCli, snapshotter, param.TemplateParams{}, args)
+	c.Assert(err, NotNil)
+	var created, deleted string
+	for _, a := range dynCli.Actions() {
+		switch a := a.(type) {
+		case k8stesting.CreateAction:
+			created = a.GetObject().(metav1.Object).GetName()
+		case k8stesting.DeleteAction:
+			deleted = a.GetName()
+		}
+	}
+	c.Assert(created, Not(Equals), "")
+	c.Assert(deleted, Equals, created)
+}
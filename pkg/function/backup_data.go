@@ -150,6 +150,28 @@ func (*backupDataFunc) Arguments() []string {
 	}
 }
 
+func (*backupDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		podNamespaceArgSchema(BackupDataNamespaceArg),
+		{Name: BackupDataPodArg, Type: kanister.ArgTypeString, Description: "Pod mounting the volume to back up"},
+		{Name: BackupDataContainerArg, Type: kanister.ArgTypeString, Description: "Container of the pod running the backup"},
+		{Name: BackupDataIncludePathArg, Type: kanister.ArgTypeString, Description: "Path of the data to back up in the container"},
+		artifactPrefixArgSchema(BackupDataBackupArtifactPrefixArg),
+		encryptionKeyArgSchema(BackupDataEncryptionKeyArg),
+	}
+}
+
+func (*backupDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataOutputBackupID, Type: kanister.OutputTypeString, Description: "ID of the restic snapshot"},
+		{Name: BackupDataOutputBackupTag, Type: kanister.OutputTypeString, Description: "Tag of the restic snapshot"},
+		{Name: BackupDataOutputBackupFileCount, Type: kanister.OutputTypeString, Description: "Number of files in the backup"},
+		{Name: BackupDataOutputBackupSize, Type: kanister.OutputTypeString, Description: "Size of the backup"},
+		{Name: BackupDataOutputBackupPhysicalSize, Type: kanister.OutputTypeString, Description: "Size added to the repository"},
+		versionOutput,
+	}
+}
+
 type backupDataParsedOutput struct {
 	backupID   string
 	backupTag  string
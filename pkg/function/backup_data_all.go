@@ -147,6 +147,24 @@ func (*backupDataAllFunc) Arguments() []string {
 	}
 }
 
+func (*backupDataAllFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: BackupDataAllNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pods"},
+		{Name: BackupDataAllContainerArg, Type: kanister.ArgTypeString, Description: "Container of the pods running the backup"},
+		{Name: BackupDataAllIncludePathArg, Type: kanister.ArgTypeString, Description: "Path of the data to back up in the containers"},
+		{Name: BackupDataAllBackupArtifactPrefixArg, Type: kanister.ArgTypeString, Description: "Path of the restic repositories in the Profile"},
+		{Name: BackupDataAllPodsArg, Type: kanister.ArgTypeString, Description: "Space separated pods to back up, defaults to the pods of the workload"},
+		encryptionKeyArgSchema(BackupDataAllEncryptionKeyArg),
+	}
+}
+
+func (*backupDataAllFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataAllOutput, Type: kanister.OutputTypeString, Description: "JSON of the backup IDs and tags by pod"},
+		versionOutput,
+	}
+}
+
 func backupDataAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, container string, backupArtifactPrefix, includePath, encryptionKey string, tp param.TemplateParams) (map[string]interface{}, error) {
 	errChan := make(chan error, len(ps))
 	outChan := make(chan BackupInfo, len(ps))
@@ -193,6 +193,25 @@ func (*BackupDataStatsFunc) Arguments() []string {
 	}
 }
 
+func (*BackupDataStatsFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: BackupDataStatsNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod running restic"},
+		artifactPrefixArgSchema(BackupDataStatsBackupArtifactPrefixArg),
+		{Name: BackupDataStatsBackupIdentifierArg, Type: kanister.ArgTypeString, Description: "ID of the restic snapshot"},
+		{Name: BackupDataStatsMode, Type: kanister.ArgTypeString, Default: "restore-size", Enum: []string{"restore-size", "raw-data", "blobs-per-file", "files-by-contents"}, Description: "Restic stats mode"},
+		encryptionKeyArgSchema(BackupDataStatsEncryptionKeyArg),
+	}
+}
+
+func (*BackupDataStatsFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataStatsOutputMode, Type: kanister.OutputTypeString, Description: "Mode of the stats"},
+		{Name: BackupDataStatsOutputFileCount, Type: kanister.OutputTypeString, Description: "Number of files in the backup"},
+		{Name: BackupDataStatsOutputSize, Type: kanister.OutputTypeString, Description: "Size of the backup"},
+		versionOutput,
+	}
+}
+
 func (b *BackupDataStatsFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
@@ -83,6 +83,25 @@ func (*backupDataUsingKopiaServerFunc) Arguments() []string {
 	}
 }
 
+func (*backupDataUsingKopiaServerFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: BackupDataContainerArg, Type: kanister.ArgTypeString, Description: "Container of the pod running the backup"},
+		{Name: BackupDataIncludePathArg, Type: kanister.ArgTypeString, Description: "Path of the data to back up in the container"},
+		podNamespaceArgSchema(BackupDataNamespaceArg),
+		{Name: BackupDataPodArg, Type: kanister.ArgTypeString, Description: "Pod mounting the volume to back up"},
+		{Name: BackupDataUsingKopiaServerSnapshotTagsArg, Type: kanister.ArgTypeString, Description: `Tags of the Kopia snapshot, e.g. "key1:value1,key2:value2"`},
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*backupDataUsingKopiaServerFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataOutputBackupID, Type: kanister.OutputTypeString, Description: "ID of the Kopia snapshot"},
+		{Name: BackupDataOutputBackupSize, Type: kanister.OutputTypeString, Description: "Logical size of the backup"},
+		{Name: BackupDataOutputBackupPhysicalSize, Type: kanister.OutputTypeString, Description: "Size uploaded to the repository"},
+	}
+}
+
 func (b *backupDataUsingKopiaServerFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]any) (map[string]any, error) {
 	// Set progress percent
 	b.progressPercent = progress.StartedPercent
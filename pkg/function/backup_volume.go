@@ -0,0 +1,370 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/field"
+	kankopia "github.com/kanisterio/kanister/pkg/kopia"
+	kopiacmd "github.com/kanisterio/kanister/pkg/kopia/command"
+	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
+	"github.com/kanisterio/kanister/pkg/kube"
+	kubesnapshot "github.com/kanisterio/kanister/pkg/kube/snapshot"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+const (
+	// BackupVolumeFuncName gives the function name
+	BackupVolumeFuncName = "BackupVolume"
+	// BackupVolumeNamespaceArg is the namespace of the volume
+	BackupVolumeNamespaceArg = "namespace"
+	// BackupVolumeVolumeArg is the name of the PVC to back up
+	BackupVolumeVolumeArg = "volume"
+	// BackupVolumeVolumeSnapshotArg is the name of a VolumeSnapshot to back up instead of the PVC
+	BackupVolumeVolumeSnapshotArg = "volumeSnapshot"
+	// BackupVolumeStorageClassArg is the storage class of the volume restored from the VolumeSnapshot
+	BackupVolumeStorageClassArg = "storageClass"
+	// BackupVolumePodOverrideArg contains pod specs to override default pod specs
+	BackupVolumePodOverrideArg = "podOverride"
+	// BackupVolumeOutputKopiaSnapshot is the Kopia snapshot info, which can be
+	// used as KopiaSnapshot artifact
+	BackupVolumeOutputKopiaSnapshot = "kopiaSnapshot"
+
+	backupVolumeJobPrefix = "backup-volume-"
+	// backupVolumeMountDir is the parent directory of the volume in the pod.
+	// The path is part of the Kopia snapshot source, so it must not change
+	// between backups of the same volume.
+	backupVolumeMountDir = "/mnt/vol_data"
+)
+
+func init() {
+	_ = kanister.Register(&backupVolumeFunc{})
+}
+
+var _ kanister.Func = (*backupVolumeFunc)(nil)
+
+type backupVolumeFunc struct {
+	progressPercent string
+}
+
+func (*backupVolumeFunc) Name() string {
+	return BackupVolumeFuncName
+}
+
+func (b *backupVolumeFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	b.progressPercent = progress.StartedPercent
+	defer func() { b.progressPercent = progress.CompletedPercent }()
+
+	var namespace, volume, volumeSnapshot, storageClass, tagsStr, userHostname string
+	var err error
+	if err = Arg(args, BackupVolumeNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupVolumeVolumeArg, &volume, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupVolumeVolumeSnapshotArg, &volumeSnapshot, ""); err != nil {
+		return nil, err
+	}
+	if (volume == "") == (volumeSnapshot == "") {
+		return nil, errors.Errorf("Exactly one of the %s or %s arguments is required", BackupVolumeVolumeArg, BackupVolumeVolumeSnapshotArg)
+	}
+	if err = OptArg(args, BackupVolumeStorageClassArg, &storageClass, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, BackupDataUsingKopiaServerSnapshotTagsArg, &tagsStr, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, KopiaRepositoryServerUserHostname, &userHostname, ""); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, BackupVolumePodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if tagsStr != "" {
+		tags = strings.Split(tagsStr, ",")
+	}
+
+	hostname, fingerprint, userAccessPassphrase, err := repositoryServerUserCredentials(tp, userHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+	pvc, readOnly := volume, true
+	if volumeSnapshot != "" {
+		dynCli, err := kube.NewDynamicClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+		}
+		snapshotter, err := kubesnapshot.NewSnapshotter(cli, dynCli)
+		if err != nil {
+			return nil, err
+		}
+		// The status of the VolumeSnapshot is only set once it is reconciled
+		if err := snapshotter.WaitOnReadyToUse(ctx, volumeSnapshot, namespace); err != nil {
+			return nil, errors.Wrapf(err, "Failed to wait for VolumeSnapshot %s to be ready", volumeSnapshot)
+		}
+		vs, err := snapshotter.Get(ctx, volumeSnapshot, namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get VolumeSnapshot %s", volumeSnapshot)
+		}
+		var restoreSize *resource.Quantity
+		if vs.Status != nil {
+			restoreSize = vs.Status.RestoreSize
+		}
+		restored, err := createPVCFromVolumeSnapshot(ctx, cli, vs.Name, namespace, vs.Spec.Source.PersistentVolumeClaimName, restoreSize, storageClass)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := cli.CoreV1().PersistentVolumeClaims(namespace).Delete(context.Background(), restored.Name, metav1.DeleteOptions{}); err != nil {
+				log.WithContext(ctx).WithError(err).Print("Failed to delete PVC restored from VolumeSnapshot", field.M{"PVC": restored.Name})
+			}
+		}()
+		pvc = restored.Name
+		// The volume is named after the source PVC, so that backups of the
+		// PVC and of its snapshots share the Kopia snapshot source
+		if vs.Spec.Source.PersistentVolumeClaimName != nil {
+			volume = *vs.Spec.Source.PersistentVolumeClaimName
+		} else {
+			volume = vs.Name
+		}
+		// The restored PVC is only used by this pod, so it is not mounted
+		// read-only in case the filesystem needs to replay its journal
+		readOnly = false
+	}
+	return backupVolume(ctx, cli, namespace, pvc, volume, readOnly, hostname, fingerprint, userAccessPassphrase, tags, tp, podOverride)
+}
+
+func (*backupVolumeFunc) RequiredArgs() []string {
+	return []string{BackupVolumeNamespaceArg}
+}
+
+func (*backupVolumeFunc) Arguments() []string {
+	return []string{
+		BackupVolumeNamespaceArg,
+		BackupVolumeVolumeArg,
+		BackupVolumeVolumeSnapshotArg,
+		BackupVolumeStorageClassArg,
+		BackupVolumePodOverrideArg,
+		BackupDataUsingKopiaServerSnapshotTagsArg,
+		KopiaRepositoryServerUserHostname,
+	}
+}
+
+func (*backupVolumeFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: BackupVolumeNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVC or VolumeSnapshot"},
+		{Name: BackupVolumeVolumeArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to back up"},
+		{Name: BackupVolumeVolumeSnapshotArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshot to back up instead of a PVC"},
+		{Name: BackupVolumeStorageClassArg, Type: kanister.ArgTypeString, Description: "Storage class of the PVC restored from the VolumeSnapshot"},
+		podOverrideArgSchema(BackupVolumePodOverrideArg),
+		{Name: BackupDataUsingKopiaServerSnapshotTagsArg, Type: kanister.ArgTypeString, Description: `Tags of the Kopia snapshot, e.g. "key1:value1,key2:value2"`},
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*backupVolumeFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: BackupDataOutputBackupID, Type: kanister.OutputTypeString, Description: "ID of the Kopia snapshot"},
+		{Name: BackupDataOutputBackupSize, Type: kanister.OutputTypeString, Description: "Logical size of the backup"},
+		{Name: BackupDataOutputBackupPhysicalSize, Type: kanister.OutputTypeString, Description: "Size uploaded to the repository"},
+		{Name: BackupVolumeOutputKopiaSnapshot, Type: kanister.OutputTypeString, Description: "Kopia snapshot info to restore the volume with RestoreVolume"},
+	}
+}
+
+func (b *backupVolumeFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    b.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
+
+// repositoryServerUserCredentials returns the hostname, server fingerprint and
+// passphrase of the RepositoryServer user
+func repositoryServerUserCredentials(tp param.TemplateParams, userHostname string) (string, string, string, error) {
+	if tp.RepositoryServer == nil {
+		return "", "", "", errors.New("Failed to find a RepositoryServer in Template Params")
+	}
+	userPassphrase, cert, err := userCredentialsAndServerTLS(&tp)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "Failed to fetch User Credentials/Certificate Data from Template Params")
+	}
+	fingerprint, err := kankopia.ExtractFingerprintFromCertificateJSON(cert)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "Failed to fetch Kopia API Server Certificate Secret Data from Certificate")
+	}
+	hostname, userAccessPassphrase, err := hostNameAndUserPassPhraseFromRepoServer(userPassphrase, userHostname)
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "Failed to fetch Hostname/User Passphrase from Secret")
+	}
+	return hostname, fingerprint, userAccessPassphrase, nil
+}
+
+// backupVolumeMountPath returns the path the volume is mounted at by
+// BackupVolume and RestoreVolume
+func backupVolumeMountPath(volume string) string {
+	return filepath.Join(backupVolumeMountDir, volume)
+}
+
+func backupVolume(
+	ctx context.Context,
+	cli kubernetes.Interface,
+	namespace,
+	pvc,
+	volume string,
+	readOnly bool,
+	hostname,
+	fingerprint,
+	userPassphrase string,
+	tags []string,
+	tp param.TemplateParams,
+	podOverride crv1alpha1.JSONMap,
+) (map[string]interface{}, error) {
+	mountPath := backupVolumeMountPath(volume)
+	options := &kube.PodOptions{
+		Namespace:    namespace,
+		GenerateName: backupVolumeJobPrefix,
+		Image:        consts.GetKanisterToolsImage(),
+		Command:      []string{"bash", "-c", "tail -f /dev/null"},
+		Volumes:      map[string]kube.VolumeMountOptions{pvc: {MountPath: mountPath, ReadOnly: readOnly}},
+		PodOverride:  podOverride,
+	}
+	pr := kube.NewPodRunner(cli, options)
+	podFunc := func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
+		if err := pc.WaitForPodReady(ctx); err != nil {
+			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pc.PodName())
+		}
+		pod := pc.Pod()
+		snapInfo, err := backupDataUsingKopiaServer(
+			cli,
+			pod.Spec.Containers[0].Name,
+			hostname,
+			mountPath,
+			namespace,
+			pod.Name,
+			tp.RepositoryServer.Address,
+			fingerprint,
+			tp.RepositoryServer.Username,
+			userPassphrase,
+			tags,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to backup volume using Kopia Repository Server")
+		}
+		return backupVolumeOutput(snapInfo.SnapshotID, snapInfo.Stats)
+	}
+	return pr.Run(ctx, podFunc)
+}
+
+func backupVolumeOutput(snapID string, stats *kopiacmd.SnapshotCreateStats) (map[string]interface{}, error) {
+	var logSize, phySize int64
+	if stats != nil {
+		logSize = stats.SizeHashedB + stats.SizeCachedB
+		phySize = stats.SizeUploadedB
+	}
+	kopiaSnapshot, err := snapshot.MarshalKopiaSnapshot(&snapshot.SnapshotInfo{
+		ID:           snapID,
+		LogicalSize:  logSize,
+		PhysicalSize: phySize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		BackupDataOutputBackupID:           snapID,
+		BackupDataOutputBackupSize:         humanize.Bytes(uint64(logSize)),
+		BackupDataOutputBackupPhysicalSize: humanize.Bytes(uint64(phySize)),
+		BackupVolumeOutputKopiaSnapshot:    kopiaSnapshot,
+	}, nil
+}
+
+// createPVCFromVolumeSnapshot creates a PVC with the contents of the
+// VolumeSnapshot. The storage class and access modes are taken from the
+// source PVC unless a storage class is given. Snapshots of block volumes are
+// rejected, since the PVC is mounted as a filesystem.
+func createPVCFromVolumeSnapshot(ctx context.Context, cli kubernetes.Interface, volumeSnapshot, namespace string, sourcePVC *string, restoreSize *resource.Quantity, storageClass string) (*v1.PersistentVolumeClaim, error) {
+	snapshotAPIGroup := SnapshotAPIGroup
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: backupVolumeJobPrefix,
+			Namespace:    namespace,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			DataSource: &v1.TypedLocalObjectReference{
+				APIGroup: &snapshotAPIGroup,
+				Kind:     "VolumeSnapshot",
+				Name:     volumeSnapshot,
+			},
+		},
+	}
+	if sourcePVC != nil {
+		source, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, *sourcePVC, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+		case err != nil:
+			return nil, errors.Wrapf(err, "Failed to get PVC %s", *sourcePVC)
+		default:
+			if source.Spec.VolumeMode != nil && *source.Spec.VolumeMode == v1.PersistentVolumeBlock {
+				return nil, errors.Errorf("VolumeSnapshot %s of block volume %s cannot be backed up, only filesystem volumes are supported", volumeSnapshot, *sourcePVC)
+			}
+			pvc.Spec.AccessModes = source.Spec.AccessModes
+			pvc.Spec.StorageClassName = source.Spec.StorageClassName
+			if restoreSize == nil {
+				size := source.Spec.Resources.Requests[v1.ResourceStorage]
+				restoreSize = &size
+			}
+		}
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	if restoreSize == nil || restoreSize.IsZero() {
+		return nil, errors.Errorf("Failed to determine the restore size of VolumeSnapshot %s", volumeSnapshot)
+	}
+	pvc.Spec.Resources.Requests = v1.ResourceList{v1.ResourceStorage: *restoreSize}
+	pvc, err := cli.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Failed to create PVC from VolumeSnapshot %s", volumeSnapshot))
+	}
+	return pvc, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	kopiacmd "github.com/kanisterio/kanister/pkg/kopia/command"
+)
+
+type BackupVolumeSuite struct{}
+
+var _ = Suite(&BackupVolumeSuite{})
+
+func (s *BackupVolumeSuite) TestBackupVolumeOutput(c *C) {
+	out, err := backupVolumeOutput("k1234", &kopiacmd.SnapshotCreateStats{SizeHashedB: 1000, SizeCachedB: 24, SizeUploadedB: 100})
+	c.Assert(err, IsNil)
+	c.Assert(out[BackupDataOutputBackupID], Equals, "k1234")
+	c.Assert(out[BackupDataOutputBackupSize], Equals, "1.0 kB")
+	c.Assert(out[BackupDataOutputBackupPhysicalSize], Equals, "100 B")
+
+	// The KopiaSnapshot output can be passed to RestoreVolume
+	snapID, err := restoreVolumeSnapshotID(map[string]interface{}{RestoreVolumeKopiaSnapshotArg: out[BackupVolumeOutputKopiaSnapshot]})
+	c.Assert(err, IsNil)
+	c.Assert(snapID, Equals, "k1234")
+
+	snapID, err = restoreVolumeSnapshotID(map[string]interface{}{RestoreVolumeBackupIDArg: "k1234"})
+	c.Assert(err, IsNil)
+	c.Assert(snapID, Equals, "k1234")
+
+	_, err = restoreVolumeSnapshotID(map[string]interface{}{})
+	c.Assert(err, NotNil)
+	_, err = restoreVolumeSnapshotID(map[string]interface{}{
+		RestoreVolumeBackupIDArg:      "k1234",
+		RestoreVolumeKopiaSnapshotArg: out[BackupVolumeOutputKopiaSnapshot],
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *BackupVolumeSuite) TestCreatePVCFromVolumeSnapshot(c *C) {
+	ctx := context.Background()
+	sc := "source-sc"
+	source := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "ns"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			StorageClassName: &sc,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+	}
+	// The fake clientset does not generate names, so every PVC is created with a new one
+	newCli := func() *fake.Clientset { return fake.NewSimpleClientset(source.DeepCopy()) }
+	sourceName := source.Name
+
+	// The source PVC is used for the defaults
+	pvc, err := createPVCFromVolumeSnapshot(ctx, newCli(), "snap", "ns", &sourceName, nil, "")
+	c.Assert(err, IsNil)
+	c.Assert(pvc.Spec.DataSource.Name, Equals, "snap")
+	c.Assert(pvc.Spec.AccessModes, DeepEquals, source.Spec.AccessModes)
+	c.Assert(*pvc.Spec.StorageClassName, Equals, sc)
+	c.Assert(pvc.Spec.VolumeMode, IsNil)
+	c.Assert(pvc.Spec.Resources.Requests[v1.ResourceStorage], DeepEquals, resource.MustParse("1Gi"))
+
+	// The restore size and storage class take precedence
+	size := resource.MustParse("2Gi")
+	pvc, err = createPVCFromVolumeSnapshot(ctx, newCli(), "snap", "ns", &sourceName, &size, "other-sc")
+	c.Assert(err, IsNil)
+	c.Assert(*pvc.Spec.StorageClassName, Equals, "other-sc")
+	c.Assert(pvc.Spec.Resources.Requests[v1.ResourceStorage], DeepEquals, size)
+
+	// The size is required if the source PVC was deleted
+	missing := "missing"
+	_, err = createPVCFromVolumeSnapshot(ctx, newCli(), "snap", "ns", &missing, nil, "")
+	c.Assert(err, NotNil)
+	pvc, err = createPVCFromVolumeSnapshot(ctx, newCli(), "snap", "ns", &missing, &size, "")
+	c.Assert(err, IsNil)
+	c.Assert(pvc.Spec.AccessModes, DeepEquals, []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce})
+	c.Assert(pvc.Spec.StorageClassName, IsNil)
+
+	// Snapshots of block volumes are rejected
+	block := v1.PersistentVolumeBlock
+	source.Spec.VolumeMode = &block
+	_, err = createPVCFromVolumeSnapshot(ctx, newCli(), "snap", "ns", &sourceName, nil, "")
+	c.Assert(err, ErrorMatches, "VolumeSnapshot snap of block volume data cannot be backed up, only filesystem volumes are supported")
+}
@@ -160,6 +160,21 @@ func (*CheckRepositoryFunc) Arguments() []string {
 		CheckRepositoryEncryptionKeyArg,
 	}
 }
+
+func (*CheckRepositoryFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		artifactPrefixArgSchema(CheckRepositoryArtifactPrefixArg),
+		encryptionKeyArgSchema(CheckRepositoryEncryptionKeyArg),
+	}
+}
+
+func (*CheckRepositoryFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CheckRepositoryPasswordIncorrect, Type: kanister.OutputTypeString, Description: "\"true\" if the encryption key is incorrect"},
+		{Name: CheckRepositoryRepoDoesNotExist, Type: kanister.OutputTypeString, Description: "\"true\" if the repository does not exist"},
+		versionOutput,
+	}
+}
 func (c *CheckRepositoryFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
@@ -75,6 +75,7 @@ func copyVolumeData(
 	tp param.TemplateParams,
 	namespace,
 	pvcName,
+	mountPoint,
 	targetPath,
 	encryptionKey string,
 	podOverride map[string]interface{},
@@ -86,7 +87,6 @@ func copyVolumeData(
 	}
 
 	// Create a pod with PVCs attached
-	mountPoint := fmt.Sprintf(CopyVolumeDataMountPoint, pvcName)
 	options := &kube.PodOptions{
 		Namespace:    namespace,
 		GenerateName: CopyVolumeDataJobPrefix,
@@ -211,7 +211,8 @@ func (c *copyVolumeDataFunc) Exec(ctx context.Context, tp param.TemplateParams,
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
 	}
-	return copyVolumeData(ctx, cli, tp, namespace, vol, targetPath, encryptionKey, podOverride)
+	mountPoint := fmt.Sprintf(CopyVolumeDataMountPoint, vol)
+	return copyVolumeData(ctx, cli, tp, namespace, vol, mountPoint, targetPath, encryptionKey, podOverride)
 }
 
 func (*copyVolumeDataFunc) RequiredArgs() []string {
@@ -231,6 +232,28 @@ func (*copyVolumeDataFunc) Arguments() []string {
 	}
 }
 
+func (*copyVolumeDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CopyVolumeDataNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVC"},
+		{Name: CopyVolumeDataVolumeArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to copy"},
+		artifactPrefixArgSchema(CopyVolumeDataArtifactPrefixArg),
+		encryptionKeyArgSchema(CopyVolumeDataEncryptionKeyArg),
+	}
+}
+
+func (*copyVolumeDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CopyVolumeDataOutputBackupID, Type: kanister.OutputTypeString, Description: "ID of the restic snapshot"},
+		{Name: CopyVolumeDataOutputBackupRoot, Type: kanister.OutputTypeString, Description: "Path of the volume in the snapshot"},
+		{Name: CopyVolumeDataOutputBackupArtifactLocation, Type: kanister.OutputTypeString, Description: "Location of the restic repository"},
+		{Name: CopyVolumeDataOutputBackupTag, Type: kanister.OutputTypeString, Description: "Tag of the restic snapshot"},
+		{Name: CopyVolumeDataOutputBackupFileCount, Type: kanister.OutputTypeString, Description: "Number of files in the backup"},
+		{Name: CopyVolumeDataOutputBackupSize, Type: kanister.OutputTypeString, Description: "Size of the backup"},
+		{Name: CopyVolumeDataOutputPhysicalSize, Type: kanister.OutputTypeString, Description: "Size added to the repository"},
+		versionOutput,
+	}
+}
+
 func (c *copyVolumeDataFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
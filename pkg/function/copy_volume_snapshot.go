@@ -0,0 +1,258 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	awsconfig "github.com/kanisterio/kanister/pkg/aws"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+func init() {
+	_ = kanister.Register(&copyVolumeSnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*copyVolumeSnapshotFunc)(nil)
+)
+
+const (
+	// CopyVolumeSnapshotFuncName gives the name of the function
+	CopyVolumeSnapshotFuncName           = "CopyVolumeSnapshot"
+	CopyVolumeSnapshotManifestArg        = "snapshots"
+	CopyVolumeSnapshotDestRegionArg      = "destRegion"
+	CopyVolumeSnapshotDestProfileArg     = "destProfile"
+	CopyVolumeSnapshotCredentialsArg     = "credentials"
+	CopyVolumeSnapshotDestCredentialsArg = "destCredentials"
+	CopyVolumeSnapshotOutputArg          = "volumeSnapshotInfo"
+)
+
+type copyVolumeSnapshotFunc struct {
+	progressPercent string
+}
+
+func (*copyVolumeSnapshotFunc) Name() string {
+	return CopyVolumeSnapshotFuncName
+}
+
+func copyVolumeSnapshot(ctx context.Context, snapshotinfo, destRegion string, profile, destProfile *param.Profile, creds, destCreds map[string]string, getter getter.Getter) (map[string]interface{}, error) {
+	PVCData := []VolumeSnapshotInfo{}
+	err := json.Unmarshal([]byte(snapshotinfo), &PVCData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not decode JSON data")
+	}
+	copies := make([]VolumeSnapshotInfo, 0, len(PVCData))
+	for _, pvcInfo := range PVCData {
+		if isCSIStorageType(pvcInfo.Type) {
+			return nil, errors.Errorf("Snapshot copy is not supported for storage type %s", pvcInfo.Type)
+		}
+		config, err := blockstorageConfig(profile, pvcInfo.Type, pvcInfo.Region, creds)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := getter.Get(pvcInfo.Type, config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not get storage provider %v", pvcInfo.Type)
+		}
+		snapshot, err := provider.SnapshotGet(ctx, pvcInfo.SnapshotID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get Snapshot from Provider")
+		}
+
+		region := destRegion
+		if region == "" {
+			region = pvcInfo.Region
+		}
+		// Destination credentials switch the copy to the destination account or project
+		copyArgs := make(map[string]string)
+		if destProfile != nil {
+			if err := validateDestProfileCredential(destProfile, pvcInfo.Type); err != nil {
+				return nil, err
+			}
+			for k, v := range getConfig(destProfile, pvcInfo.Type) {
+				copyArgs[k] = v
+			}
+		}
+		for k, v := range destCreds {
+			copyArgs[k] = v
+		}
+		to := blockstorage.Snapshot{Region: region}
+		var snap *blockstorage.Snapshot
+		if len(copyArgs) == 0 {
+			snap, err = provider.SnapshotCopy(ctx, *snapshot, to)
+		} else {
+			snap, err = provider.SnapshotCopyWithArgs(ctx, *snapshot, to, copyArgs)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to copy snapshot %s", pvcInfo.SnapshotID)
+		}
+
+		destConfig := make(map[string]string, len(config)+len(copyArgs))
+		for k, v := range config {
+			destConfig[k] = v
+		}
+		for k, v := range copyArgs {
+			destConfig[k] = v
+		}
+		if pvcInfo.Type == blockstorage.TypeEBS {
+			destConfig[awsconfig.ConfigRegion] = region
+		}
+		destProvider, err := getter.Get(pvcInfo.Type, destConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not get destination storage provider %v", pvcInfo.Type)
+		}
+		if err = destProvider.SnapshotCreateWaitForCompletion(ctx, snap); err != nil {
+			return nil, errors.Wrap(err, "Snapshot copy did not complete "+snap.ID)
+		}
+		log.WithContext(ctx).Print("Successfully copied snapshot", field.M{"SnapshotID": pvcInfo.SnapshotID, "CopyID": snap.ID, "Region": region})
+		copies = append(copies, VolumeSnapshotInfo{
+			SnapshotID: snap.ID,
+			Type:       pvcInfo.Type,
+			Region:     region,
+			PVCName:    pvcInfo.PVCName,
+			Az:         pvcInfo.Az,
+			Tags:       pvcInfo.Tags,
+			VolumeType: pvcInfo.VolumeType,
+		})
+	}
+	manifestData, err := json.Marshal(copies)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to encode JSON data")
+	}
+	return map[string]interface{}{CopyVolumeSnapshotOutputArg: string(manifestData)}, nil
+}
+
+// fetchDestProfile fetches the Profile referenced as `namespace/name`
+// validateDestProfileCredential checks that the credential of the destination
+// profile can be used by getConfig for storage type sType.
+func validateDestProfileCredential(profile *param.Profile, sType blockstorage.Type) error {
+	switch {
+	case profile.Credential.Type == param.CredentialTypeKeyPair:
+	case profile.Credential.Type == param.CredentialTypeSecret && sType == blockstorage.TypeEBS:
+	default:
+		return errors.Errorf("Credential type %s of the destination profile not supported for blockstorage type %s", profile.Credential.Type, sType)
+	}
+	return nil
+}
+
+func fetchDestProfile(ctx context.Context, ref string) (*param.Profile, error) {
+	parts := strings.Split(ref, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("Invalid destination profile %s, expected format is namespace/name", ref)
+	}
+	config, err := kube.LoadConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load Kubernetes config")
+	}
+	cli, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	crCli, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kanister client")
+	}
+	return param.FetchProfile(ctx, cli, crCli, &crv1alpha1.ObjectReference{Namespace: parts[0], Name: parts[1]})
+}
+
+func (c *copyVolumeSnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	c.progressPercent = progress.StartedPercent
+	defer func() { c.progressPercent = progress.CompletedPercent }()
+
+	var snapshotinfo, destRegion, destProfileRef string
+	var creds, destCreds map[string]string
+	if err := Arg(args, CopyVolumeSnapshotManifestArg, &snapshotinfo); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyVolumeSnapshotDestRegionArg, &destRegion, ""); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyVolumeSnapshotDestProfileArg, &destProfileRef, ""); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyVolumeSnapshotCredentialsArg, &creds, nil); err != nil {
+		return nil, err
+	}
+	if err := OptArg(args, CopyVolumeSnapshotDestCredentialsArg, &destCreds, nil); err != nil {
+		return nil, err
+	}
+	var destProfile *param.Profile
+	if destProfileRef != "" {
+		var err error
+		if destProfile, err = fetchDestProfile(ctx, destProfileRef); err != nil {
+			return nil, errors.Wrap(err, "Failed to fetch destination profile")
+		}
+		if err = ValidateProfile(destProfile); err != nil {
+			return nil, errors.Wrap(err, "Destination profile validation failed")
+		}
+	}
+	return copyVolumeSnapshot(ctx, snapshotinfo, destRegion, tp.Profile, destProfile, creds, destCreds, getter.New())
+}
+
+func (*copyVolumeSnapshotFunc) RequiredArgs() []string {
+	return []string{CopyVolumeSnapshotManifestArg}
+}
+
+func (*copyVolumeSnapshotFunc) Arguments() []string {
+	return []string{
+		CopyVolumeSnapshotManifestArg,
+		CopyVolumeSnapshotDestRegionArg,
+		CopyVolumeSnapshotDestProfileArg,
+		CopyVolumeSnapshotCredentialsArg,
+		CopyVolumeSnapshotDestCredentialsArg,
+	}
+}
+
+func (*copyVolumeSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CopyVolumeSnapshotManifestArg, Type: kanister.ArgTypeString, Description: "Output of CreateVolumeSnapshot"},
+		{Name: CopyVolumeSnapshotDestRegionArg, Type: kanister.ArgTypeString, Description: "Region to copy the snapshots to"},
+		{Name: CopyVolumeSnapshotDestProfileArg, Type: kanister.ArgTypeString, Description: `Profile of the destination, as "namespace/name"`},
+		{Name: CopyVolumeSnapshotCredentialsArg, Type: kanister.ArgTypeObject, Description: "Credentials of the provider, defaults to the credentials of the Profile"},
+		{Name: CopyVolumeSnapshotDestCredentialsArg, Type: kanister.ArgTypeObject, Description: "Credentials of the destination provider"},
+	}
+}
+
+func (*copyVolumeSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CopyVolumeSnapshotOutputArg, Type: kanister.OutputTypeString, Description: "JSON of the copied snapshots"},
+	}
+}
+
+func (c *copyVolumeSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    c.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	awsconfig "github.com/kanisterio/kanister/pkg/aws"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/testutil/mockblockstorage"
+)
+
+type CopyVolumeSnapshotTestSuite struct{}
+
+var _ = Suite(&CopyVolumeSnapshotTestSuite{})
+
+func (s *CopyVolumeSnapshotTestSuite) TestCopyVolumeSnapshot(c *C) {
+	ctx := context.Background()
+	mockGetter := mockblockstorage.NewGetter()
+	profile := &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Region: "us-west-2",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{
+				ID:     "foo",
+				Secret: "bar",
+			},
+		},
+	}
+	tags := []*blockstorage.KeyValue{
+		{Key: "testkey", Value: "testval"},
+	}
+	marshal := func(infos ...VolumeSnapshotInfo) string {
+		info, err := json.Marshal(infos)
+		c.Assert(err, IsNil)
+		return string(info)
+	}
+	ebsInfo := VolumeSnapshotInfo{SnapshotID: "snap-1", Type: blockstorage.TypeEBS, Region: "us-west-2", PVCName: "pvc-1", Az: "us-west-2a", Tags: tags, VolumeType: "ssd"}
+	csiInfo := VolumeSnapshotInfo{SnapshotID: "ns/snap-2", Type: blockstorage.TypeGeneric, PVCName: "pvc-2"}
+	adInfo := VolumeSnapshotInfo{SnapshotID: "snap-3", Type: blockstorage.TypeAD, PVCName: "pvc-3"}
+	for _, tc := range []struct {
+		snapshotinfo string
+		destRegion   string
+		destProfile  *param.Profile
+		destCreds    map[string]string
+		creds        map[string]string
+		region       string
+		check        Checker
+	}{
+		{
+			snapshotinfo: marshal(ebsInfo),
+			destRegion:   "us-east-1",
+			region:       "us-east-1",
+			check:        IsNil,
+		},
+		{
+			snapshotinfo: marshal(ebsInfo),
+			destCreds:    map[string]string{awsconfig.AccessKeyID: "id", awsconfig.SecretAccessKey: "secret"},
+			region:       "us-west-2",
+			check:        IsNil,
+		},
+		{
+			snapshotinfo: marshal(ebsInfo),
+			destProfile:  profile,
+			region:       "us-west-2",
+			check:        IsNil,
+		},
+		{
+			// getConfig only reads key pairs and secrets
+			snapshotinfo: marshal(ebsInfo),
+			destProfile:  &param.Profile{Credential: param.Credential{Type: param.CredentialTypeKopia}},
+			check:        NotNil,
+		},
+		{
+			snapshotinfo: marshal(adInfo),
+			creds:        map[string]string{blockstorage.AzureSubscriptionID: "sub"},
+			check:        IsNil,
+		},
+		{
+			snapshotinfo: marshal(adInfo),
+			check:        NotNil,
+		},
+		{
+			snapshotinfo: marshal(csiInfo),
+			check:        NotNil,
+		},
+		{
+			snapshotinfo: "invalid",
+			check:        NotNil,
+		},
+	} {
+		out, err := copyVolumeSnapshot(ctx, tc.snapshotinfo, tc.destRegion, profile, tc.destProfile, tc.creds, tc.destCreds, mockGetter)
+		c.Assert(err, tc.check)
+		if err != nil {
+			continue
+		}
+		var copies []VolumeSnapshotInfo
+		err = json.Unmarshal([]byte(out[CopyVolumeSnapshotOutputArg].(string)), &copies)
+		c.Assert(err, IsNil)
+		c.Assert(copies, HasLen, 1)
+		c.Assert(copies[0].SnapshotID, Not(Equals), "")
+		c.Assert(copies[0].Region, Equals, tc.region)
+	}
+}
@@ -142,6 +142,26 @@ func (*createCSISnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*createCSISnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateCSISnapshotPVCNameArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to snapshot"},
+		{Name: CreateCSISnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVC and the VolumeSnapshot"},
+		{Name: CreateCSISnapshotSnapshotClassArg, Type: kanister.ArgTypeString, Description: "VolumeSnapshotClass of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotNameArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshot, defaults to the PVC name with a random suffix"},
+		{Name: CreateCSISnapshotLabelsArg, Type: kanister.ArgTypeObject, Description: "Labels of the VolumeSnapshot"},
+	}
+}
+
+func (*createCSISnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CreateCSISnapshotNameArg, Type: kanister.OutputTypeString, Description: "Name of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotPVCNameArg, Type: kanister.OutputTypeString, Description: "Name of the snapshotted PVC"},
+		{Name: CreateCSISnapshotNamespaceArg, Type: kanister.OutputTypeString, Description: "Namespace of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotRestoreSizeArg, Type: kanister.OutputTypeString, Description: "Minimum size of a volume restored from the snapshot"},
+		{Name: CreateCSISnapshotSnapshotContentNameArg, Type: kanister.OutputTypeString, Description: "Name of the bound VolumeSnapshotContent"},
+	}
+}
+
 func createCSISnapshot(ctx context.Context, snapshotter snapshot.Snapshotter, name, namespace, pvc, snapshotClass string, wait bool, labels map[string]string) (*v1.VolumeSnapshot, error) {
 	if err := snapshotter.Create(ctx, name, namespace, pvc, &snapshotClass, wait, labels); err != nil {
 		return nil, err
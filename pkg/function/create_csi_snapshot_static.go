@@ -151,6 +151,25 @@ func (*createCSISnapshotStaticFunc) Arguments() []string {
 	}
 }
 
+func (*createCSISnapshotStaticFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateCSISnapshotStaticNameArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotStaticNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotStaticDriverArg, Type: kanister.ArgTypeString, Description: "CSI driver of the snapshot"},
+		{Name: CreateCSISnapshotStaticSnapshotHandleArg, Type: kanister.ArgTypeString, Description: "Handle of the existing snapshot in the storage provider"},
+		{Name: CreateCSISnapshotStaticSnapshotClassArg, Type: kanister.ArgTypeString, Description: "VolumeSnapshotClass of the VolumeSnapshot"},
+	}
+}
+
+func (*createCSISnapshotStaticFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CreateCSISnapshotStaticNameArg, Type: kanister.OutputTypeString, Description: "Name of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotStaticNamespaceArg, Type: kanister.OutputTypeString, Description: "Namespace of the VolumeSnapshot"},
+		{Name: CreateCSISnapshotStaticOutputRestoreSize, Type: kanister.OutputTypeString, Description: "Minimum size of a volume restored from the snapshot"},
+		{Name: CreateCSISnapshotStaticOutputSnapshotContentName, Type: kanister.OutputTypeString, Description: "Name of the bound VolumeSnapshotContent"},
+	}
+}
+
 func createCSISnapshotStatic(
 	ctx context.Context,
 	snapshotter snapshot.Snapshotter,
@@ -191,6 +191,23 @@ func (crs *createRDSSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*createRDSSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateRDSSnapshotInstanceIDArg, Type: kanister.ArgTypeString, Description: "ID of the RDS instance or Aurora cluster"},
+		{Name: CreateRDSSnapshotDBEngine, Type: kanister.ArgTypeString, Description: "Engine of the database, e.g. aurora-mysql for Aurora clusters"},
+	}
+}
+
+func (*createRDSSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CreateRDSSnapshotSnapshotID, Type: kanister.OutputTypeString, Description: "ID of the RDS snapshot"},
+		{Name: CreateRDSSnapshotInstanceIDArg, Type: kanister.OutputTypeString, Description: "ID of the RDS instance"},
+		{Name: CreateRDSSnapshotSecurityGroupID, Type: kanister.OutputTypeString, Description: "YAML list of the security group IDs of the instance"},
+		{Name: CreateRDSSnapshotAllocatedStorage, Type: kanister.OutputTypeString, Description: "Storage allocated to the instance"},
+		{Name: CreateRDSSnapshotDBSubnetGroup, Type: kanister.OutputTypeString, Description: "DB subnet group of the instance"},
+	}
+}
+
 func (crs *createRDSSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
@@ -27,6 +27,7 @@ import (
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	awsconfig "github.com/kanisterio/kanister/pkg/aws"
 	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/csi"
 	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
 	"github.com/kanisterio/kanister/pkg/field"
 	"github.com/kanisterio/kanister/pkg/kube"
@@ -69,6 +70,9 @@ func createVolumeFromSnapshot(ctx context.Context, cli kubernetes.Interface, nam
 	if len(pvcNames) > 0 && len(pvcNames) != len(PVCData) {
 		return nil, errors.New("Invalid number of PVC names provided")
 	}
+	if err = validateSnapshotGroup(PVCData); err != nil {
+		return nil, err
+	}
 	// providerList required for unit testing
 	providerList := make(map[string]blockstorage.Provider)
 	for i, pvcInfo := range PVCData {
@@ -88,6 +92,16 @@ func createVolumeFromSnapshot(ctx context.Context, cli kubernetes.Interface, nam
 		if err != nil {
 			return nil, errors.Wrapf(err, "Could not get storage provider %v", pvcInfo.Type)
 		}
+		if isCSIStorageType(pvcInfo.Type) {
+			// VolumeSnapshots can only be restored in their own namespace
+			snapNamespace, _, err := csi.SplitID(pvcInfo.SnapshotID)
+			if err != nil {
+				return nil, err
+			}
+			if snapNamespace != namespace {
+				return nil, errors.Errorf("Snapshot %s must be restored in namespace %s", pvcInfo.SnapshotID, snapNamespace)
+			}
+		}
 		_, err = cli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
 		if err == nil {
 			if err = kubevolume.DeletePVC(cli, namespace, pvcName); err != nil {
@@ -109,6 +123,12 @@ func createVolumeFromSnapshot(ctx context.Context, cli kubernetes.Interface, nam
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed to create volume from snapshot, snapID: %s", snapshot.ID)
 		}
+		if isCSIStorageType(pvcInfo.Type) {
+			// The CSI provider restores the snapshot into the PVC itself
+			log.WithContext(ctx).Print("Restore/Create volume from snapshot completed", field.M{"PVC": pvcName, "Volume": vol.ID})
+			providerList[pvcInfo.PVCName] = provider
+			continue
+		}
 
 		annotations := map[string]string{}
 		pvc, err := kubevolume.CreatePVC(ctx, cli, namespace, pvcName, vol.SizeInBytes, vol.ID, annotations, nil, nil)
@@ -164,6 +184,18 @@ func (*createVolumeFromSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*createVolumeFromSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateVolumeFromSnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVCs"},
+		{Name: CreateVolumeFromSnapshotManifestArg, Type: kanister.ArgTypeString, Description: "Output of CreateVolumeSnapshot"},
+		{Name: CreateVolumeFromSnapshotPVCNamesArg, Type: kanister.ArgTypeArray, Description: "Names of the PVCs to create, defaults to the names of the snapshotted PVCs"},
+	}
+}
+
+func (*createVolumeFromSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (crs *createVolumeFromSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
@@ -171,3 +203,27 @@ func (crs *createVolumeFromSnapshotFunc) ExecutionProgress() (crv1alpha1.PhasePr
 		LastTransitionTime: &metav1Time,
 	}, nil
 }
+
+// validateSnapshotGroup checks that snapshots of a group are restored together
+// and are not mixed with snapshots of another group
+func validateSnapshotGroup(PVCData []VolumeSnapshotInfo) error {
+	var groupID string
+	for _, pvcInfo := range PVCData {
+		if pvcInfo.GroupID == "" {
+			continue
+		}
+		if groupID != "" && pvcInfo.GroupID != groupID {
+			return errors.Errorf("Snapshots of groups %s and %s can not be restored together", groupID, pvcInfo.GroupID)
+		}
+		groupID = pvcInfo.GroupID
+	}
+	if groupID == "" {
+		return nil
+	}
+	for _, pvcInfo := range PVCData {
+		if pvcInfo.GroupID != groupID {
+			return errors.Errorf("Snapshot %s is not part of group %s", pvcInfo.SnapshotID, groupID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+func init() {
+	_ = kanister.Register(&createVolumeGroupSnapshotFunc{})
+}
+
+var (
+	_ kanister.Func = (*createVolumeGroupSnapshotFunc)(nil)
+)
+
+const (
+	// CreateVolumeGroupSnapshotFuncName gives the name of the function
+	CreateVolumeGroupSnapshotFuncName      = "CreateVolumeGroupSnapshot"
+	CreateVolumeGroupSnapshotNamespaceArg  = "namespace"
+	CreateVolumeGroupSnapshotPVCsArg       = "pvcs"
+	CreateVolumeGroupSnapshotPodArg        = "pod"
+	CreateVolumeGroupSnapshotContainerArg  = "container"
+	CreateVolumeGroupSnapshotFreezeCmdArg  = "freezeCommand"
+	CreateVolumeGroupSnapshotThawCmdArg    = "thawCommand"
+	CreateVolumeGroupSnapshotSkipWaitArg   = "skipWait"
+	CreateVolumeGroupSnapshotOutputArg     = "volumeSnapshotInfo"
+	CreateVolumeGroupSnapshotGroupIDOutput = "groupID"
+	// SnapshotGroupTag is set on every snapshot of a group to the group ID
+	SnapshotGroupTag = "kanister.io/snapshot-group"
+)
+
+type createVolumeGroupSnapshotFunc struct {
+	progressPercent string
+}
+
+func (*createVolumeGroupSnapshotFunc) Name() string {
+	return CreateVolumeGroupSnapshotFuncName
+}
+
+// volumeFreezer freezes the filesystems of a group of volumes while
+// they are snapshotted one by one
+type volumeFreezer interface {
+	Freeze(ctx context.Context) error
+	Thaw(ctx context.Context) error
+}
+
+func createVolumeGroupSnapshot(ctx context.Context, tp param.TemplateParams, cli kubernetes.Interface, namespace string, pvcs []string, freezer volumeFreezer, getter getter.Getter, skipWait bool) (map[string]interface{}, error) {
+	if len(pvcs) == 0 {
+		return nil, errors.New("No PVCs to snapshot")
+	}
+	vols := make([]volumeInfo, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		volInfo, err := getPVCInfo(ctx, cli, namespace, pvc, tp, getter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get PVC info")
+		}
+		if len(vols) > 0 && (volInfo.sType != vols[0].sType || volInfo.region != vols[0].region) {
+			return nil, errors.Errorf("PVCs %s and %s are not of the same storage type and region", vols[0].pvc, pvc)
+		}
+		vols = append(vols, *volInfo)
+	}
+	provider := vols[0].provider
+	volumes := make([]blockstorage.Volume, 0, len(vols))
+	for _, volInfo := range vols {
+		vol, err := provider.VolumeGet(ctx, volInfo.volumeID, volInfo.volZone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Volume unavailable, volumeID: %s", volInfo.volumeID)
+		}
+		if vol.Encrypted {
+			return nil, errors.New("Encrypted volumes are unsupported")
+		}
+		volumes = append(volumes, *vol)
+	}
+
+	groupID := "group-" + rand.String(8)
+	tags := map[string]string{
+		SnapshotGroupTag: groupID,
+	}
+	var snaps []*blockstorage.Snapshot
+	var err error
+	gs, ok := provider.(blockstorage.GroupSnapshotter)
+	if ok {
+		snaps, err = gs.SnapshotGroupCreate(ctx, volumes, tags)
+	}
+	if !ok || errors.Cause(err) == blockstorage.ErrGroupSnapshotNotSupported {
+		log.WithContext(ctx).Print("Group snapshots not available, freezing volumes", field.M{"Reason": err, "GroupID": groupID})
+		snaps, err = snapshotFrozenVolumes(ctx, provider, vols, volumes, tags, freezer)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to snapshot volume group")
+	}
+	if !skipWait {
+		for _, snap := range snaps {
+			if err := provider.SnapshotCreateWaitForCompletion(ctx, snap); err != nil {
+				deleteGroupSnapshots(provider, snaps)
+				return nil, errors.Wrap(err, "Snapshot creation did not complete")
+			}
+		}
+	}
+
+	PVCData := make([]VolumeSnapshotInfo, 0, len(snaps))
+	for i, snap := range snaps {
+		PVCData = append(PVCData, VolumeSnapshotInfo{
+			SnapshotID: snap.ID,
+			Type:       vols[i].sType,
+			Region:     vols[i].region,
+			PVCName:    vols[i].pvc,
+			Az:         snap.Volume.Az,
+			Tags:       snap.Volume.Tags,
+			VolumeType: snap.Volume.VolumeType,
+			GroupID:    groupID,
+		})
+	}
+	manifestData, err := json.Marshal(PVCData)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to encode JSON data")
+	}
+	return map[string]interface{}{
+		CreateVolumeGroupSnapshotOutputArg:     string(manifestData),
+		CreateVolumeGroupSnapshotGroupIDOutput: groupID,
+	}, nil
+}
+
+// snapshotFrozenVolumes snapshots the volumes while their filesystems are frozen
+func snapshotFrozenVolumes(ctx context.Context, provider blockstorage.Provider, vols []volumeInfo, volumes []blockstorage.Volume, tags map[string]string, freezer volumeFreezer) ([]*blockstorage.Snapshot, error) {
+	if freezer == nil {
+		return nil, errors.Errorf("Argument %s is required to freeze the volumes", CreateVolumeGroupSnapshotPodArg)
+	}
+	if err := freezer.Freeze(ctx); err != nil {
+		return nil, errors.Wrap(err, "Failed to freeze volumes")
+	}
+	snaps := make([]*blockstorage.Snapshot, 0, len(volumes))
+	var err error
+	for i, volume := range volumes {
+		var snap *blockstorage.Snapshot
+		snapTags := map[string]string{"pvcname": vols[i].pvc}
+		for k, v := range tags {
+			snapTags[k] = v
+		}
+		if snap, err = provider.SnapshotCreate(ctx, volume, snapTags); err != nil {
+			break
+		}
+		snaps = append(snaps, snap)
+	}
+	// The filesystems must stay frozen until every snapshot has been cut
+	if cw, ok := provider.(blockstorage.SnapshotCutWaiter); ok && err == nil {
+		for _, snap := range snaps {
+			if err = cw.SnapshotWaitForCut(ctx, snap); err != nil {
+				break
+			}
+		}
+	}
+	if thawErr := freezer.Thaw(ctx); thawErr != nil {
+		if err == nil {
+			err = errors.Wrap(thawErr, "Failed to thaw volumes")
+		} else {
+			log.WithContext(ctx).WithError(thawErr).Print("Failed to thaw volumes")
+		}
+	}
+	if err != nil {
+		deleteGroupSnapshots(provider, snaps)
+		return nil, err
+	}
+	return snaps, nil
+}
+
+// deleteGroupSnapshots deletes the snapshots of a group that failed. It uses
+// a new context, since the failure may be the expiration of the context of
+// the phase.
+func deleteGroupSnapshots(provider blockstorage.Provider, snaps []*blockstorage.Snapshot) {
+	for _, snap := range snaps {
+		if err := provider.SnapshotDelete(context.Background(), snap); err != nil {
+			log.WithError(err).Print("Failed to delete snapshot of failed group", field.M{"SnapshotID": snap.ID})
+		}
+	}
+}
+
+// execFreezer freezes volumes by executing commands in a container
+// of a pod that mounts them
+type execFreezer struct {
+	cli       kubernetes.Interface
+	namespace string
+	pod       string
+	container string
+	freeze    [][]string
+	thaw      [][]string
+}
+
+// newExecFreezer returns a freezer running freezeCmd and thawCmd in the container. If the
+// commands are not set, fsfreeze is run for the mount path of every PVC.
+func newExecFreezer(ctx context.Context, cli kubernetes.Interface, namespace, podName, container string, pvcs []string, freezeCmd, thawCmd []string) (*execFreezer, error) {
+	f := &execFreezer{
+		cli:       cli,
+		namespace: namespace,
+		pod:       podName,
+		container: container,
+	}
+	if len(freezeCmd) > 0 || len(thawCmd) > 0 {
+		if len(freezeCmd) == 0 || len(thawCmd) == 0 {
+			return nil, errors.Errorf("Both %s and %s must be set", CreateVolumeGroupSnapshotFreezeCmdArg, CreateVolumeGroupSnapshotThawCmdArg)
+		}
+		f.freeze = [][]string{freezeCmd}
+		f.thaw = [][]string{thawCmd}
+		return f, nil
+	}
+	pod, err := cli.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get pod %s", podName)
+	}
+	paths, err := pvcMountPaths(pod, container, pvcs)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		f.freeze = append(f.freeze, []string{"fsfreeze", "--freeze", path})
+		f.thaw = append(f.thaw, []string{"fsfreeze", "--unfreeze", path})
+	}
+	return f, nil
+}
+
+// pvcMountPaths returns the paths at which the PVCs are mounted in the container
+func pvcMountPaths(pod *v1.Pod, container string, pvcs []string) ([]string, error) {
+	var c *v1.Container
+	for i := range pod.Spec.Containers {
+		if container == "" || pod.Spec.Containers[i].Name == container {
+			c = &pod.Spec.Containers[i]
+			break
+		}
+	}
+	if c == nil {
+		return nil, errors.Errorf("Container %s not found in pod %s", container, pod.Name)
+	}
+	paths := make([]string, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		var path string
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim == nil || vol.PersistentVolumeClaim.ClaimName != pvc {
+				continue
+			}
+			for _, vm := range c.VolumeMounts {
+				if vm.Name == vol.Name {
+					path = vm.MountPath
+				}
+			}
+		}
+		if path == "" {
+			return nil, errors.Errorf("PVC %s is not mounted in container %s of pod %s", pvc, c.Name, pod.Name)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// Freeze runs the freeze commands and thaws the volumes again if one of them fails
+func (f *execFreezer) Freeze(ctx context.Context) error {
+	for i, cmd := range f.freeze {
+		if err := f.exec(ctx, cmd); err != nil {
+			for _, thawCmd := range f.thaw[:i] {
+				if thawErr := f.exec(ctx, thawCmd); thawErr != nil {
+					log.WithContext(ctx).WithError(thawErr).Print("Failed to thaw volume")
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Thaw runs all thaw commands
+func (f *execFreezer) Thaw(ctx context.Context) error {
+	var errs []string
+	for _, cmd := range f.thaw {
+		if err := f.exec(ctx, cmd); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (f *execFreezer) exec(ctx context.Context, cmd []string) error {
+	stdout, stderr, err := kube.Exec(f.cli, f.namespace, f.pod, f.container, cmd, nil)
+	format.LogWithCtx(ctx, f.pod, f.container, stdout)
+	format.LogWithCtx(ctx, f.pod, f.container, stderr)
+	return errors.Wrapf(err, "Failed to run %v", cmd)
+}
+
+func (c *createVolumeGroupSnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	c.progressPercent = progress.StartedPercent
+	defer func() { c.progressPercent = progress.CompletedPercent }()
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
+	}
+	var namespace, pod, container string
+	var pvcs, freezeCmd, thawCmd []string
+	var skipWait bool
+	if err = Arg(args, CreateVolumeGroupSnapshotNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotPVCsArg, &pvcs, nil); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotPodArg, &pod, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotContainerArg, &container, ""); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotFreezeCmdArg, &freezeCmd, nil); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotThawCmdArg, &thawCmd, nil); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, CreateVolumeGroupSnapshotSkipWaitArg, &skipWait, nil); err != nil {
+		return nil, err
+	}
+	if len(pvcs) == 0 {
+		pvcs, err = getPVCList(tp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var freezer volumeFreezer
+	if pod != "" {
+		if freezer, err = newExecFreezer(ctx, cli, namespace, pod, container, pvcs, freezeCmd, thawCmd); err != nil {
+			return nil, err
+		}
+	}
+	return createVolumeGroupSnapshot(ctx, tp, cli, namespace, pvcs, freezer, getter.New(), skipWait)
+}
+
+func (*createVolumeGroupSnapshotFunc) RequiredArgs() []string {
+	return []string{CreateVolumeGroupSnapshotNamespaceArg}
+}
+
+func (*createVolumeGroupSnapshotFunc) Arguments() []string {
+	return []string{
+		CreateVolumeGroupSnapshotNamespaceArg,
+		CreateVolumeGroupSnapshotPVCsArg,
+		CreateVolumeGroupSnapshotPodArg,
+		CreateVolumeGroupSnapshotContainerArg,
+		CreateVolumeGroupSnapshotFreezeCmdArg,
+		CreateVolumeGroupSnapshotThawCmdArg,
+		CreateVolumeGroupSnapshotSkipWaitArg,
+	}
+}
+
+func (*createVolumeGroupSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateVolumeGroupSnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVCs"},
+		{Name: CreateVolumeGroupSnapshotPVCsArg, Type: kanister.ArgTypeArray, Description: "PVCs to snapshot, defaults to the PVCs of the workload"},
+		{Name: CreateVolumeGroupSnapshotPodArg, Type: kanister.ArgTypeString, Description: "Pod whose volumes are frozen if group snapshots are not available"},
+		{Name: CreateVolumeGroupSnapshotContainerArg, Type: kanister.ArgTypeString, Description: "Container running the freeze and thaw commands"},
+		{Name: CreateVolumeGroupSnapshotFreezeCmdArg, Type: kanister.ArgTypeArray, Description: "Command freezing the volumes, defaults to fsfreeze"},
+		{Name: CreateVolumeGroupSnapshotThawCmdArg, Type: kanister.ArgTypeArray, Description: "Command thawing the volumes, defaults to fsfreeze"},
+		{Name: CreateVolumeGroupSnapshotSkipWaitArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Does not wait for the snapshots to complete"},
+	}
+}
+
+func (*createVolumeGroupSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CreateVolumeGroupSnapshotOutputArg, Type: kanister.OutputTypeString, Description: "JSON of the snapshots"},
+		{Name: CreateVolumeGroupSnapshotGroupIDOutput, Type: kanister.OutputTypeString, Description: "ID of the snapshot group"},
+	}
+}
+
+func (c *createVolumeGroupSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    c.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
@@ -0,0 +1,341 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/testutil/mockblockstorage"
+)
+
+type CreateVolumeGroupSnapshotTestSuite struct{}
+
+var _ = Suite(&CreateVolumeGroupSnapshotTestSuite{})
+
+type fakeFreezer struct {
+	calls   []string
+	thawErr error
+}
+
+func (f *fakeFreezer) Freeze(context.Context) error {
+	f.calls = append(f.calls, "freeze")
+	return nil
+}
+
+func (f *fakeFreezer) Thaw(context.Context) error {
+	f.calls = append(f.calls, "thaw")
+	return f.thawErr
+}
+
+// groupProvider adds native group snapshots to the mock provider
+type groupProvider struct {
+	*mockblockstorage.Provider
+	err     error
+	volumes []blockstorage.Volume
+}
+
+func (p *groupProvider) SnapshotGroupCreate(ctx context.Context, volumes []blockstorage.Volume, tags map[string]string) ([]*blockstorage.Snapshot, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.volumes = volumes
+	snaps := make([]*blockstorage.Snapshot, 0, len(volumes))
+	for range volumes {
+		snaps = append(snaps, p.MockSnapshot())
+	}
+	return snaps, nil
+}
+
+// cutProvider records when the snapshots of the mock provider are cut in the
+// calls of the freezer
+type cutProvider struct {
+	*mockblockstorage.Provider
+	freezer *fakeFreezer
+	err     error
+}
+
+func (p *cutProvider) SnapshotWaitForCut(ctx context.Context, snap *blockstorage.Snapshot) error {
+	p.freezer.calls = append(p.freezer.calls, "cut")
+	return p.err
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) objects(ns string, n int) []runtime.Object {
+	var objs []runtime.Object
+	for i := 1; i <= n; i++ {
+		objs = append(objs,
+			&v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("pvc-%d", i),
+					Namespace: ns,
+				},
+				Spec: v1.PersistentVolumeClaimSpec{
+					VolumeName: fmt.Sprintf("pv-%d", i),
+				},
+			},
+			&v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("pv-%d", i),
+					Labels: map[string]string{
+						kube.FDZoneLabelName:   "us-west-2a",
+						kube.FDRegionLabelName: "us-west-2",
+					},
+				},
+				Spec: v1.PersistentVolumeSpec{
+					Capacity: v1.ResourceList{
+						v1.ResourceName(v1.ResourceStorage): k8sresource.MustParse("1Gi"),
+					},
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						AWSElasticBlockStore: &v1.AWSElasticBlockStoreVolumeSource{
+							VolumeID: fmt.Sprintf("vol-%d", i),
+						},
+					},
+				},
+			},
+		)
+	}
+	return objs
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) templateParams() param.TemplateParams {
+	return param.TemplateParams{
+		Profile: &param.Profile{
+			Location: crv1alpha1.Location{
+				Type:   crv1alpha1.LocationTypeS3Compliant,
+				Region: "us-west-2",
+			},
+			Credential: param.Credential{
+				Type: param.CredentialTypeKeyPair,
+				KeyPair: &param.KeyPair{
+					ID:     "foo",
+					Secret: "bar",
+				},
+			},
+		},
+	}
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) TestCreateVolumeGroupSnapshot(c *C) {
+	ctx := context.Background()
+	ns := "ns"
+	pvcs := []string{"pvc-1", "pvc-2"}
+	for _, tc := range []struct {
+		groupErr    error
+		native      bool
+		freezer     *fakeFreezer
+		wantFreeze  []string
+		errChecker  Checker
+		groupCalled bool
+	}{
+		{
+			// Native group snapshots don't freeze the volumes
+			native:      true,
+			freezer:     &fakeFreezer{},
+			errChecker:  IsNil,
+			groupCalled: true,
+		},
+		{
+			native:     true,
+			groupErr:   errors.Wrap(blockstorage.ErrGroupSnapshotNotSupported, "test"),
+			freezer:    &fakeFreezer{},
+			wantFreeze: []string{"freeze", "thaw"},
+			errChecker: IsNil,
+		},
+		{
+			freezer:    &fakeFreezer{},
+			wantFreeze: []string{"freeze", "thaw"},
+			errChecker: IsNil,
+		},
+		{
+			native:     true,
+			groupErr:   errors.New("test"),
+			freezer:    &fakeFreezer{},
+			errChecker: NotNil,
+		},
+		{
+			// A freezer is required without group snapshots
+			errChecker: NotNil,
+		},
+	} {
+		mock, err := mockblockstorage.Get(blockstorage.TypeEBS)
+		c.Assert(err, IsNil)
+		var provider blockstorage.Provider = mock
+		gp := &groupProvider{Provider: mock, err: tc.groupErr}
+		if tc.native {
+			provider = gp
+		}
+		var freezer volumeFreezer
+		if tc.freezer != nil {
+			freezer = tc.freezer
+		}
+		cli := fake.NewSimpleClientset(s.objects(ns, len(pvcs))...)
+		out, err := createVolumeGroupSnapshot(ctx, s.templateParams(), cli, ns, pvcs, freezer, providerGetter{provider: provider}, false)
+		c.Assert(err, tc.errChecker)
+		if err != nil {
+			continue
+		}
+		c.Assert(tc.freezer.calls, DeepEquals, tc.wantFreeze)
+		c.Assert(gp.volumes != nil, Equals, tc.groupCalled)
+
+		var infos []VolumeSnapshotInfo
+		c.Assert(json.Unmarshal([]byte(out[CreateVolumeGroupSnapshotOutputArg].(string)), &infos), IsNil)
+		c.Assert(infos, HasLen, len(pvcs))
+		for i, info := range infos {
+			c.Assert(info.PVCName, Equals, pvcs[i])
+			c.Assert(info.GroupID, Equals, out[CreateVolumeGroupSnapshotGroupIDOutput])
+		}
+		// The manifest must be accepted by CreateVolumeFromSnapshot
+		c.Assert(validateSnapshotGroup(infos), IsNil)
+	}
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) TestCreateVolumeGroupSnapshotThawFailure(c *C) {
+	mock, err := mockblockstorage.Get(blockstorage.TypeEBS)
+	c.Assert(err, IsNil)
+	freezer := &fakeFreezer{thawErr: errors.New("thaw failed")}
+	cli := fake.NewSimpleClientset(s.objects("ns", 2)...)
+	_, err = createVolumeGroupSnapshot(context.Background(), s.templateParams(), cli, "ns", []string{"pvc-1", "pvc-2"}, freezer, providerGetter{provider: mock}, false)
+	c.Assert(err, ErrorMatches, ".*Failed to thaw volumes: thaw failed")
+	// The snapshots taken while the volumes were frozen are deleted
+	c.Assert(mock.DeletedSnapIDList, Not(HasLen), 0)
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) TestCreateVolumeGroupSnapshotWaitForCut(c *C) {
+	pvcs := []string{"pvc-1", "pvc-2"}
+	mock, err := mockblockstorage.Get(blockstorage.TypeEBS)
+	c.Assert(err, IsNil)
+	freezer := &fakeFreezer{}
+	provider := &cutProvider{Provider: mock, freezer: freezer}
+	cli := fake.NewSimpleClientset(s.objects("ns", len(pvcs))...)
+	_, err = createVolumeGroupSnapshot(context.Background(), s.templateParams(), cli, "ns", pvcs, freezer, providerGetter{provider: provider}, false)
+	c.Assert(err, IsNil)
+	// The volumes are thawed once every snapshot has been cut
+	c.Assert(freezer.calls, DeepEquals, []string{"freeze", "cut", "cut", "thaw"})
+
+	mock, err = mockblockstorage.Get(blockstorage.TypeEBS)
+	c.Assert(err, IsNil)
+	freezer = &fakeFreezer{}
+	provider = &cutProvider{Provider: mock, freezer: freezer, err: errors.New("snapshot failed")}
+	_, err = createVolumeGroupSnapshot(context.Background(), s.templateParams(), cli, "ns", pvcs, freezer, providerGetter{provider: provider}, false)
+	c.Assert(err, ErrorMatches, ".*snapshot failed")
+	c.Assert(freezer.calls, DeepEquals, []string{"freeze", "cut", "thaw"})
+	c.Assert(mock.DeletedSnapIDList, Not(HasLen), 0)
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) TestPVCMountPaths(c *C) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod"},
+		Spec: v1.PodSpec{
+			Volumes: []v1.Volume{
+				{
+					Name: "data",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-data"},
+					},
+				},
+				{
+					Name: "log",
+					VolumeSource: v1.VolumeSource{
+						PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-log"},
+					},
+				},
+			},
+			Containers: []v1.Container{
+				{
+					Name:         "sidecar",
+					VolumeMounts: []v1.VolumeMount{{Name: "log", MountPath: "/sidecar/log"}},
+				},
+				{
+					Name: "db",
+					VolumeMounts: []v1.VolumeMount{
+						{Name: "data", MountPath: "/var/lib/db"},
+						{Name: "log", MountPath: "/var/log/db"},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		container  string
+		pvcs       []string
+		paths      []string
+		errChecker Checker
+	}{
+		{
+			container:  "db",
+			pvcs:       []string{"pvc-log", "pvc-data"},
+			paths:      []string{"/var/log/db", "/var/lib/db"},
+			errChecker: IsNil,
+		},
+		{
+			// The first container is used by default
+			pvcs:       []string{"pvc-log"},
+			paths:      []string{"/sidecar/log"},
+			errChecker: IsNil,
+		},
+		{
+			pvcs:       []string{"pvc-data"},
+			errChecker: NotNil,
+		},
+		{
+			container:  "missing",
+			pvcs:       []string{"pvc-data"},
+			errChecker: NotNil,
+		},
+	} {
+		paths, err := pvcMountPaths(pod, tc.container, tc.pvcs)
+		c.Assert(err, tc.errChecker)
+		c.Assert(paths, DeepEquals, tc.paths)
+	}
+}
+
+func (s *CreateVolumeGroupSnapshotTestSuite) TestValidateSnapshotGroup(c *C) {
+	for _, tc := range []struct {
+		infos      []VolumeSnapshotInfo
+		errChecker Checker
+	}{
+		{
+			infos:      []VolumeSnapshotInfo{{SnapshotID: "snap-1"}, {SnapshotID: "snap-2"}},
+			errChecker: IsNil,
+		},
+		{
+			infos:      []VolumeSnapshotInfo{{SnapshotID: "snap-1", GroupID: "g1"}, {SnapshotID: "snap-2", GroupID: "g1"}},
+			errChecker: IsNil,
+		},
+		{
+			infos:      []VolumeSnapshotInfo{{SnapshotID: "snap-1", GroupID: "g1"}, {SnapshotID: "snap-2", GroupID: "g2"}},
+			errChecker: NotNil,
+		},
+		{
+			infos:      []VolumeSnapshotInfo{{SnapshotID: "snap-1", GroupID: "g1"}, {SnapshotID: "snap-2"}},
+			errChecker: NotNil,
+		},
+	} {
+		c.Assert(validateSnapshotGroup(tc.infos), tc.errChecker)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	awsconfig "github.com/kanisterio/kanister/pkg/aws"
 	"github.com/kanisterio/kanister/pkg/blockstorage"
 	"github.com/kanisterio/kanister/pkg/blockstorage/awsebs"
+	"github.com/kanisterio/kanister/pkg/blockstorage/csi"
 	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
@@ -54,6 +55,7 @@ const (
 	CreateVolumeSnapshotNamespaceArg = "namespace"
 	CreateVolumeSnapshotPVCsArg      = "pvcs"
 	CreateVolumeSnapshotSkipWaitArg  = "skipWait"
+	CreateVolumeSnapshotOutputArg    = "volumeSnapshotInfo"
 )
 
 type createVolumeSnapshotFunc struct {
@@ -72,6 +74,8 @@ type VolumeSnapshotInfo struct {
 	Az         string
 	Tags       blockstorage.VolumeTags
 	VolumeType string
+	// GroupID is set for snapshots taken together by CreateVolumeGroupSnapshot
+	GroupID string `json:",omitempty"`
 }
 
 type volumeInfo struct {
@@ -85,6 +89,10 @@ type volumeInfo struct {
 }
 
 func ValidateLocationForBlockstorage(profile *param.Profile, sType blockstorage.Type) error {
+	if isCSIStorageType(sType) {
+		// CSI snapshots are kept in the cluster and do not use the profile
+		return nil
+	}
 	if err := ValidateProfile(profile); err != nil {
 		return errors.Wrapf(err, "Profile Validation failed")
 	}
@@ -143,7 +151,7 @@ func createVolumeSnapshot(ctx context.Context, tp param.TemplateParams, cli kube
 		return nil, errors.Wrapf(err, "Failed to encode JSON data")
 	}
 
-	return map[string]interface{}{"volumeSnapshotInfo": string(manifestData)}, nil
+	return map[string]interface{}{CreateVolumeSnapshotOutputArg: string(manifestData)}, nil
 }
 
 func snapshotVolume(ctx context.Context, volume volumeInfo, skipWait bool) (*VolumeSnapshotInfo, error) {
@@ -239,10 +247,24 @@ func getPVCInfo(ctx context.Context, kubeCli kubernetes.Interface, namespace str
 			return &volumeInfo{provider: provider, volumeID: filepath.Base(gpd.PDName), sType: blockstorage.TypeGPD, volZone: pvZone, pvc: name, size: size, region: region}, nil
 		}
 		return nil, errors.Errorf("PV zone label is empty, pvName: %s, namespace: %s", pvName, namespace)
+
+	case pv.Spec.CSI != nil:
+		sType := csi.StorageType(pv.Spec.CSI.Driver)
+		provider, err = getter.Get(sType, getConfig(tp.Profile, sType))
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not get storage provider")
+		}
+		return &volumeInfo{provider: provider, volumeID: csi.ID(namespace, name), sType: sType, volZone: kube.GetZoneFromLabels(pvLabels), pvc: name, size: size, region: region}, nil
 	}
 	return nil, errors.New("Storage type not supported!")
 }
 
+// isCSIStorageType returns true if volumes of the storage type are handled
+// by the generic CSI provider
+func isCSIStorageType(sType blockstorage.Type) bool {
+	return sType == blockstorage.TypeGeneric || sType == blockstorage.TypeCeph
+}
+
 func getPVCList(tp param.TemplateParams) ([]string, error) {
 	var pvcList []string
 	var podsToPvcs map[string]map[string]string
@@ -314,6 +336,29 @@ func getConfig(profile *param.Profile, sType blockstorage.Type) map[string]strin
 	return config
 }
 
+// blockstorageConfig returns the provider config for storage type sType. Azure
+// credentials can not be stored in a Profile and have to be passed in creds.
+func blockstorageConfig(profile *param.Profile, sType blockstorage.Type, region string, creds map[string]string) (map[string]string, error) {
+	config := make(map[string]string)
+	if sType == blockstorage.TypeAD {
+		if len(creds) == 0 {
+			return nil, errors.Errorf("Credentials are required for storage type %s", sType)
+		}
+	} else {
+		if err := ValidateLocationForBlockstorage(profile, sType); err != nil {
+			return nil, errors.Wrap(err, "Profile validation failed")
+		}
+		config = getConfig(profile, sType)
+		if sType == blockstorage.TypeEBS {
+			config[awsconfig.ConfigRegion] = region
+		}
+	}
+	for k, v := range creds {
+		config[k] = v
+	}
+	return config, nil
+}
+
 func (*createVolumeSnapshotFunc) RequiredArgs() []string {
 	return []string{CreateVolumeSnapshotNamespaceArg}
 }
@@ -326,6 +371,20 @@ func (*createVolumeSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*createVolumeSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: CreateVolumeSnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVCs"},
+		{Name: CreateVolumeSnapshotPVCsArg, Type: kanister.ArgTypeArray, Description: "PVCs to snapshot, defaults to the PVCs of the workload"},
+		{Name: CreateVolumeSnapshotSkipWaitArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Does not wait for the snapshots to complete"},
+	}
+}
+
+func (*createVolumeSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: CreateVolumeSnapshotOutputArg, Type: kanister.OutputTypeString, Description: "JSON of the snapshots"},
+	}
+}
+
 func (c *createVolumeSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
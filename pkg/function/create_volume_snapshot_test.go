@@ -128,6 +128,31 @@ func (s *CreateVolumeSnapshotTestSuite) TestGetPVCInfo(c *C) {
 				},
 			},
 		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pvc-test-4",
+				Namespace: ns,
+			},
+			Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-test-4",
+			},
+		},
+		&v1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pv-test-4",
+			},
+			Spec: v1.PersistentVolumeSpec{
+				Capacity: v1.ResourceList{
+					v1.ResourceName(v1.ResourceStorage): k8sresource.MustParse("1Gi"),
+				},
+				PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{
+						Driver:       "rbd.csi.ceph.com",
+						VolumeHandle: "0001-0009-rook-ceph",
+					},
+				},
+			},
+		},
 	)
 	_, err := cli.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "pvc-test-1", metav1.GetOptions{})
 	c.Assert(err, IsNil)
@@ -162,6 +187,14 @@ func (s *CreateVolumeSnapshotTestSuite) TestGetPVCInfo(c *C) {
 			pvc:   "pvc-test-3",
 			check: NotNil,
 		},
+		{
+			pvc:          "pvc-test-4",
+			wantVolumeID: "ns/pvc-test-4",
+			wantType:     blockstorage.TypeCeph,
+			wantPVC:      "pvc-test-4",
+			wantSize:     int64(1073741824),
+			check:        IsNil,
+		},
 	} {
 		volInfo, err := getPVCInfo(ctx, cli, ns, tc.pvc, tp, mockGetter)
 		c.Assert(err, tc.check)
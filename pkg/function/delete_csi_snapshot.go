@@ -103,6 +103,17 @@ func (*deleteCSISnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*deleteCSISnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteCSISnapshotNameArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshot"},
+		{Name: DeleteCSISnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the VolumeSnapshot"},
+	}
+}
+
+func (*deleteCSISnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (c *deleteCSISnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
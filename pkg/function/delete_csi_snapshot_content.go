@@ -91,6 +91,16 @@ func (*deleteCSISnapshotContentFunc) Arguments() []string {
 	}
 }
 
+func (*deleteCSISnapshotContentFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteCSISnapshotContentNameArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshotContent"},
+	}
+}
+
+func (*deleteCSISnapshotContentFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (c *deleteCSISnapshotContentFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
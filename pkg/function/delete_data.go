@@ -31,6 +31,7 @@ import (
 	"github.com/kanisterio/kanister/pkg/consts"
 	"github.com/kanisterio/kanister/pkg/format"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/progress"
 	"github.com/kanisterio/kanister/pkg/restic"
@@ -163,6 +164,9 @@ func deleteDataPodFunc(
 			format.LogWithCtx(ctx, pod.Name, pod.Spec.Containers[0].Name, stdout.String())
 			format.LogWithCtx(ctx, pod.Name, pod.Spec.Containers[0].Name, stderr.String())
 			if err != nil {
+				if objectstore.IsObjectLockedMessage(stderr.String()) {
+					return nil, errors.Wrapf(err, "Failed to forget data, backup objects are protected by object lock until their retention period expires")
+				}
 				return nil, errors.Wrapf(err, "Failed to forget data")
 			}
 			if reclaimSpace {
@@ -198,6 +202,9 @@ func pruneData(
 	format.Log(pod.Name, pod.Spec.Containers[0].Name, stderr.String())
 
 	spaceFreed := restic.SpaceFreedFromPruneLog(stdout.String())
+	if err != nil && objectstore.IsObjectLockedMessage(stderr.String()) {
+		return spaceFreed, errors.Wrapf(err, "Failed to prune data after forget, backup objects are protected by object lock until their retention period expires")
+	}
 	return spaceFreed, errors.Wrapf(err, "Failed to prune data after forget")
 }
 
@@ -263,6 +270,23 @@ func (*deleteDataFunc) Arguments() []string {
 	}
 }
 
+func (*deleteDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteDataNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod running restic"},
+		artifactPrefixArgSchema(DeleteDataBackupArtifactPrefixArg),
+		{Name: DeleteDataBackupIdentifierArg, Type: kanister.ArgTypeString, Description: "ID of the restic snapshot to delete"},
+		{Name: DeleteDataBackupTagArg, Type: kanister.ArgTypeString, Description: "Tag of the restic snapshot to delete"},
+		encryptionKeyArgSchema(DeleteDataEncryptionKeyArg),
+		{Name: DeleteDataReclaimSpace, Type: kanister.ArgTypeBoolean, Default: false, Description: "Prunes the repository to reclaim the space"},
+	}
+}
+
+func (*deleteDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: DeleteDataOutputSpaceFreed, Type: kanister.OutputTypeString, Description: "Space freed in the repository, if reclaimSpace is set"},
+	}
+}
+
 func (d *deleteDataFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
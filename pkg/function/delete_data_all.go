@@ -131,6 +131,22 @@ func (*deleteDataAllFunc) Arguments() []string {
 	}
 }
 
+func (*deleteDataAllFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteDataAllNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod running restic"},
+		{Name: DeleteDataAllBackupArtifactPrefixArg, Type: kanister.ArgTypeString, Description: "Path of the restic repositories in the Profile"},
+		{Name: DeleteDataAllBackupInfo, Type: kanister.ArgTypeString, Description: "Output of BackupDataAll"},
+		encryptionKeyArgSchema(DeleteDataAllEncryptionKeyArg),
+		{Name: DeleteDataAllReclaimSpace, Type: kanister.ArgTypeBoolean, Default: false, Description: "Prunes the repositories to reclaim the space"},
+	}
+}
+
+func (*deleteDataAllFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: DeleteDataOutputSpaceFreed, Type: kanister.OutputTypeString, Description: "Space freed in the repository, if reclaimSpace is set"},
+	}
+}
+
 func (d *deleteDataAllFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
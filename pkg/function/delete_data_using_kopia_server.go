@@ -71,6 +71,19 @@ func (*deleteDataUsingKopiaServerFunc) Arguments() []string {
 	}
 }
 
+func (*deleteDataUsingKopiaServerFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteDataBackupIdentifierArg, Type: kanister.ArgTypeString, Description: "ID of the Kopia snapshot to delete"},
+		{Name: DeleteDataNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod running Kopia"},
+		{Name: RestoreDataImageArg, Type: kanister.ArgTypeString, Description: "Image of the pod running Kopia"},
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*deleteDataUsingKopiaServerFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (d *deleteDataUsingKopiaServerFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]any) (map[string]any, error) {
 	// Set progress percent
 	d.progressPercent = progress.StartedPercent
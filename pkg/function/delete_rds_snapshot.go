@@ -143,6 +143,17 @@ func (*deleteRDSSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*deleteRDSSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteRDSSnapshotSnapshotIDArg, Type: kanister.ArgTypeString, Description: "ID of the RDS snapshot"},
+		{Name: CreateRDSSnapshotDBEngine, Type: kanister.ArgTypeString, Description: "Engine of the database, e.g. aurora-mysql for Aurora clusters"},
+	}
+}
+
+func (*deleteRDSSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (d *deleteRDSSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
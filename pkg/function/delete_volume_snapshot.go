@@ -133,6 +133,17 @@ func (*deleteVolumeSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*deleteVolumeSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: DeleteVolumeSnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the snapshotted PVCs"},
+		{Name: DeleteVolumeSnapshotManifestArg, Type: kanister.ArgTypeString, Description: "Output of CreateVolumeSnapshot"},
+	}
+}
+
+func (*deleteVolumeSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (d *deleteVolumeSnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
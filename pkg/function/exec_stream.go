@@ -0,0 +1,172 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/kanisterio/kanister/pkg/datamover"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+const (
+	// StdinFromArg references the data streamed into the stdin of a command
+	StdinFromArg = "stdinFrom"
+	// StdoutToArg references the location the stdout of a command is uploaded to
+	StdoutToArg = "stdoutTo"
+	// StdoutPathOutput is the path the stdout was uploaded to
+	StdoutPathOutput = "stdoutPath"
+	// StdoutKopiaSnapshotOutput is the Kopia snapshot info of the uploaded stdout
+	StdoutKopiaSnapshotOutput = "kopiaSnapshot"
+	// StdoutKopiaSnapshotsOutput maps <pod>/<container> to the Kopia snapshot
+	// info of the uploaded stdout of KubeExecAll
+	StdoutKopiaSnapshotsOutput = "kopiaSnapshots"
+)
+
+// StdinFrom references data in the location of the Profile. KopiaSnapshot
+// is the snapshot info printed by `kando location push` and is required
+// with Kopia profiles.
+type StdinFrom struct {
+	Path          string
+	KopiaSnapshot string
+}
+
+// StdoutTo references a path in the location of the Profile
+type StdoutTo struct {
+	Path string
+}
+
+// GetStdinFrom returns the stdinFrom argument, or nil if it is not set
+func GetStdinFrom(tp param.TemplateParams, args map[string]interface{}) (*StdinFrom, error) {
+	if !ArgExists(args, StdinFromArg) {
+		return nil, nil
+	}
+	sf := &StdinFrom{}
+	if err := Arg(args, StdinFromArg, sf); err != nil {
+		return nil, err
+	}
+	if sf.Path == "" {
+		return nil, errors.Errorf("Argument `%s` requires a path", StdinFromArg)
+	}
+	if tp.Profile == nil {
+		return nil, errors.Errorf("Argument `%s` requires a Profile", StdinFromArg)
+	}
+	return sf, nil
+}
+
+// stdinDownload streams the referenced data from the object store into the
+// stdin of a command. The data is downloaded while it is read.
+type stdinDownload struct {
+	pr   *io.PipeReader
+	done chan struct{}
+	err  error
+}
+
+func startStdinDownload(ctx context.Context, profile *param.Profile, sf *StdinFrom) *stdinDownload {
+	return newStdinDownload(sf.Path, func(w io.Writer) error {
+		return datamover.NewProfileDataMover(profile, "", sf.KopiaSnapshot).Read(ctx, w, sf.Path)
+	})
+}
+
+func newStdinDownload(path string, read func(io.Writer) error) *stdinDownload {
+	pr, pw := io.Pipe()
+	d := &stdinDownload{pr: pr, done: make(chan struct{})}
+	go func() {
+		defer close(d.done)
+		d.err = errors.Wrapf(read(pw), "Failed to read %s", path)
+		_ = pw.CloseWithError(d.err)
+	}()
+	return d
+}
+
+func (d *stdinDownload) Read(p []byte) (int, error) {
+	return d.pr.Read(p)
+}
+
+// finish stops the download after the command exited with cmdErr. The exec
+// only logs the errors of reading stdin, so the command sees the end of a
+// truncated input and may succeed. The download error is returned instead.
+// Stopping the download of data the command did not read is not an error.
+func (d *stdinDownload) finish(cmdErr error) error {
+	_ = d.pr.Close()
+	<-d.done
+	if cmdErr != nil {
+		return cmdErr
+	}
+	if d.err != nil && !errors.Is(d.err, io.ErrClosedPipe) {
+		return errors.Wrap(d.err, "Failed to stream stdin")
+	}
+	return nil
+}
+
+// GetStdoutTo returns the stdoutTo argument, or nil if it is not set
+func GetStdoutTo(tp param.TemplateParams, args map[string]interface{}) (*StdoutTo, error) {
+	if !ArgExists(args, StdoutToArg) {
+		return nil, nil
+	}
+	st := &StdoutTo{}
+	if err := Arg(args, StdoutToArg, st); err != nil {
+		return nil, err
+	}
+	if st.Path == "" {
+		return nil, errors.Errorf("Argument `%s` requires a path", StdoutToArg)
+	}
+	if tp.Profile == nil {
+		return nil, errors.Errorf("Argument `%s` requires a Profile", StdoutToArg)
+	}
+	return st, nil
+}
+
+// stdoutUpload uploads everything written to it to the object store
+type stdoutUpload struct {
+	pw       *io.PipeWriter
+	done     chan struct{}
+	snapshot string
+	err      error
+}
+
+func startStdoutUpload(ctx context.Context, profile *param.Profile, path string) *stdoutUpload {
+	pr, pw := io.Pipe()
+	u := &stdoutUpload{pw: pw, done: make(chan struct{})}
+	go func() {
+		defer close(u.done)
+		u.snapshot, u.err = datamover.NewProfileDataMover(profile, "", "").Write(ctx, pr, path)
+		// Unblock the command if the upload failed early
+		pr.CloseWithError(errors.Wrapf(u.err, "Failed to upload to %s", path))
+	}()
+	return u
+}
+
+func (u *stdoutUpload) Write(p []byte) (int, error) {
+	return u.pw.Write(p)
+}
+
+// finish ends the upload after the command exited with cmdErr and returns the
+// Kopia snapshot info, if any. The upload is aborted if the command failed.
+func (u *stdoutUpload) finish(cmdErr error) (string, error) {
+	if cmdErr != nil {
+		_ = u.pw.CloseWithError(cmdErr)
+	} else {
+		_ = u.pw.Close()
+	}
+	<-u.done
+	if cmdErr != nil {
+		return "", cmdErr
+	}
+	return u.snapshot, errors.Wrapf(u.err, "Failed to upload stdout")
+}
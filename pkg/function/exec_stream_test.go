@@ -0,0 +1,70 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+)
+
+var _ = Suite(&ExecStreamSuite{})
+
+type ExecStreamSuite struct{}
+
+func (s *ExecStreamSuite) TestStdinDownload(c *C) {
+	d := newStdinDownload("dump.sql", func(w io.Writer) error {
+		_, err := w.Write([]byte("data"))
+		return err
+	})
+	b, err := io.ReadAll(d)
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, "data")
+	c.Assert(d.finish(nil), IsNil)
+}
+
+func (s *ExecStreamSuite) TestStdinDownloadFailure(c *C) {
+	// The command reads a truncated input and exits successfully
+	d := newStdinDownload("dump.sql", func(w io.Writer) error {
+		if _, err := w.Write([]byte("partial")); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	})
+	b, _ := io.ReadAll(d)
+	c.Assert(string(b), Equals, "partial")
+	err := d.finish(nil)
+	c.Assert(err, NotNil)
+	c.Assert(err, ErrorMatches, ".*connection reset.*")
+
+	// The error of the command takes precedence
+	d = newStdinDownload("dump.sql", func(w io.Writer) error {
+		return errors.New("connection reset")
+	})
+	c.Assert(d.finish(errors.New("exit code 1")), ErrorMatches, "exit code 1")
+}
+
+func (s *ExecStreamSuite) TestStdinDownloadNotRead(c *C) {
+	// The command exits without reading all the data
+	d := newStdinDownload("dump.sql", func(w io.Writer) error {
+		_, err := io.Copy(w, bytes.NewReader(make([]byte, 1<<20)))
+		return err
+	})
+	_, err := d.Read(make([]byte, 10))
+	c.Assert(err, IsNil)
+	c.Assert(d.finish(nil), IsNil)
+}
@@ -226,6 +226,30 @@ func (*exportRDSSnapshotToLocationFunc) Arguments() []string {
 	}
 }
 
+func (*exportRDSSnapshotToLocationFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: ExportRDSSnapshotToLocNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod exporting the databases"},
+		{Name: ExportRDSSnapshotToLocInstanceIDArg, Type: kanister.ArgTypeString, Description: "ID of the RDS instance"},
+		{Name: ExportRDSSnapshotToLocSnapshotIDArg, Type: kanister.ArgTypeString, Description: "ID of the RDS snapshot"},
+		{Name: ExportRDSSnapshotToLocDBEngineArg, Type: kanister.ArgTypeString, Description: "Engine of the database"},
+		{Name: ExportRDSSnapshotToLocDBUsernameArg, Type: kanister.ArgTypeString, Description: "Username of the database"},
+		{Name: ExportRDSSnapshotToLocDBPasswordArg, Type: kanister.ArgTypeString, Description: "Password of the database"},
+		{Name: ExportRDSSnapshotToLocBackupArtPrefixArg, Type: kanister.ArgTypeString, Description: "Path of the dump in the Profile, defaults to the instance ID"},
+		{Name: ExportRDSSnapshotToLocDatabasesArg, Type: kanister.ArgTypeArray, Description: "Databases to export, defaults to all the databases"},
+		{Name: ExportRDSSnapshotToLocSecGrpIDArg, Type: kanister.ArgTypeArray, Description: "Security group IDs of the temporary instance"},
+		{Name: ExportRDSSnapshotToLocDBSubnetGroupArg, Type: kanister.ArgTypeString, Default: "default", Description: "DB subnet group of the temporary instance"},
+	}
+}
+
+func (*exportRDSSnapshotToLocationFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: ExportRDSSnapshotToLocBackupID, Type: kanister.OutputTypeString, Description: "ID of the dump, if databases are exported"},
+		{Name: ExportRDSSnapshotToLocSnapshotIDArg, Type: kanister.OutputTypeString, Description: "ID of the RDS snapshot"},
+		{Name: ExportRDSSnapshotToLocInstanceIDArg, Type: kanister.OutputTypeString, Description: "ID of the RDS instance"},
+		{Name: ExportRDSSnapshotToLocSecGrpIDArg, Type: kanister.OutputTypeString, Description: "YAML list of the security group IDs of the instance"},
+	}
+}
+
 func (d *exportRDSSnapshotToLocationFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
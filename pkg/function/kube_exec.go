@@ -17,11 +17,16 @@ package function
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"regexp"
 	"time"
 
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/kube"
@@ -46,6 +51,9 @@ const (
 	KubeExecPodNameArg       = "pod"
 	KubeExecContainerNameArg = "container"
 	KubeExecCommandArg       = "command"
+	// KubeExecResultFileArg collects the result file written with
+	// `kando output --file` as output, in addition to the outputs in the logs
+	KubeExecResultFileArg = "resultFile"
 )
 
 type kubeExecFunc struct {
@@ -78,6 +86,16 @@ func parseLogAndCreateOutput(out string) (map[string]interface{}, error) {
 	return op, nil
 }
 
+// readExecResult reads and removes the result file written by a command
+func readExecResult(cli kubernetes.Interface, namespace, pod, container, path string) (map[string]interface{}, error) {
+	cmd := fmt.Sprintf("cat %s 2>/dev/null; rm -f %s", path, path)
+	stdout, stderr, err := kube.Exec(cli, namespace, pod, container, []string{"sh", "-c", cmd}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read result file, stderr: %s", stderr)
+	}
+	return output.ParseResult([]byte(stdout))
+}
+
 func (kef *kubeExecFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
 	// Set progress percent
 	kef.progressPercent = progress.StartedPercent
@@ -89,6 +107,7 @@ func (kef *kubeExecFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 	}
 	var namespace, pod, container string
 	var cmd []string
+	var resultFile bool
 	if err = Arg(args, KubeExecNamespaceArg, &namespace); err != nil {
 		return nil, err
 	}
@@ -101,16 +120,80 @@ func (kef *kubeExecFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 	if err = Arg(args, KubeExecCommandArg, &cmd); err != nil {
 		return nil, err
 	}
+	if err = OptArg(args, KubeExecResultFileArg, &resultFile, false); err != nil {
+		return nil, err
+	}
+	stdinFrom, err := GetStdinFrom(tp, args)
+	if err != nil {
+		return nil, err
+	}
+	stdoutTo, err := GetStdoutTo(tp, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var resultPath string
+	if resultFile {
+		// The container may be shared by other phases, so every command gets its own result file
+		resultPath = fmt.Sprintf("/tmp/kanister-result-%s.json", rand.String(10))
+		cmd = append([]string{"env", output.ResultFileEnv + "=" + resultPath}, cmd...)
+	}
 
 	var (
 		bufStdout  = &bytes.Buffer{}
 		outWriters = io.MultiWriter(os.Stdout, bufStdout)
+		stdin      io.Reader
+		download   *stdinDownload
 	)
-	if err := kube.ExecOutput(cli, namespace, pod, container, cmd, nil, outWriters, os.Stderr); err != nil {
+	if stdinFrom != nil {
+		download = startStdinDownload(ctx, tp.Profile, stdinFrom)
+		stdin = download
+	}
+	var upload *stdoutUpload
+	if stdoutTo != nil {
+		// Stdout is uploaded instead of parsed, outputs can be written to the result file
+		upload = startStdoutUpload(ctx, tp.Profile, stdoutTo.Path)
+		outWriters = upload
+	}
+	err = kube.ExecOutput(cli, namespace, pod, container, cmd, stdin, outWriters, os.Stderr)
+	if download != nil {
+		err = download.finish(err)
+	}
+	var kopiaSnapshot string
+	if upload != nil {
+		kopiaSnapshot, err = upload.finish(err)
+	}
+	if err != nil {
+		if resultFile {
+			_, _ = readExecResult(cli, namespace, pod, container, resultPath)
+		}
 		return nil, err
 	}
 
-	return parseLogAndCreateOutput(bufStdout.String())
+	out, err := parseLogAndCreateOutput(bufStdout.String())
+	if err != nil {
+		return nil, err
+	}
+	if stdoutTo != nil {
+		out = map[string]interface{}{StdoutPathOutput: stdoutTo.Path}
+		if kopiaSnapshot != "" {
+			out[StdoutKopiaSnapshotOutput] = kopiaSnapshot
+		}
+	}
+	if !resultFile {
+		return out, nil
+	}
+	result, err := readExecResult(cli, namespace, pod, container, resultPath)
+	if err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = make(map[string]interface{}, len(result))
+	}
+	for k, v := range result {
+		out[k] = v
+	}
+	return out, nil
 }
 
 func (*kubeExecFunc) RequiredArgs() []string {
@@ -127,6 +210,29 @@ func (*kubeExecFunc) Arguments() []string {
 		KubeExecPodNameArg,
 		KubeExecCommandArg,
 		KubeExecContainerNameArg,
+		KubeExecResultFileArg,
+		StdinFromArg,
+		StdoutToArg,
+	}
+}
+
+func (*kubeExecFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		podNamespaceArgSchema(KubeExecNamespaceArg),
+		{Name: KubeExecPodNameArg, Type: kanister.ArgTypeString, Description: "Name of the pod"},
+		{Name: KubeExecCommandArg, Type: kanister.ArgTypeArray, Description: "Command to run"},
+		{Name: KubeExecContainerNameArg, Type: kanister.ArgTypeString, Description: "Container running the command, defaults to the first container"},
+		{Name: KubeExecResultFileArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Reads the outputs from the result file written by the command"},
+		{Name: StdinFromArg, Type: kanister.ArgTypeObject, Description: "Streams the stdin of the command from the Profile"},
+		{Name: StdoutToArg, Type: kanister.ArgTypeObject, Description: "Streams the stdout of the command to the Profile"},
+	}
+}
+
+func (*kubeExecFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
+		{Name: StdoutPathOutput, Type: kanister.OutputTypeString, Description: "Path the stdout of the command was streamed to"},
+		{Name: StdoutKopiaSnapshotOutput, Type: kanister.OutputTypeString, Description: "Kopia snapshot info of the streamed stdout"},
 	}
 }
 
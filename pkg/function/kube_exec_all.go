@@ -15,8 +15,12 @@
 package function
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -79,9 +83,20 @@ func (kef *kubeExecAllFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err = Arg(args, KubeExecAllCommandArg, &cmd); err != nil {
 		return nil, err
 	}
+	stdinFrom, err := GetStdinFrom(tp, args)
+	if err != nil {
+		return nil, err
+	}
+	stdoutTo, err := GetStdoutTo(tp, args)
+	if err != nil {
+		return nil, err
+	}
 	ps := strings.Fields(pods)
 	cs := strings.Fields(containers)
-	return execAll(ctx, cli, namespace, ps, cs, cmd)
+	if stdoutTo != nil {
+		return execAllStdoutTo(ctx, cli, namespace, ps, cs, cmd, tp.Profile, stdinFrom, stdoutTo)
+	}
+	return execAll(ctx, cli, namespace, ps, cs, cmd, tp.Profile, stdinFrom)
 }
 
 func (*kubeExecAllFunc) RequiredArgs() []string {
@@ -99,6 +114,27 @@ func (*kubeExecAllFunc) Arguments() []string {
 		KubeExecAllPodsNameArg,
 		KubeExecAllContainersNameArg,
 		KubeExecAllCommandArg,
+		StdinFromArg,
+		StdoutToArg,
+	}
+}
+
+func (*kubeExecAllFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: KubeExecAllNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pods"},
+		{Name: KubeExecAllPodsNameArg, Type: kanister.ArgTypeString, Description: "Space separated names of the pods"},
+		{Name: KubeExecAllContainersNameArg, Type: kanister.ArgTypeString, Description: "Space separated names of the containers running the command"},
+		{Name: KubeExecAllCommandArg, Type: kanister.ArgTypeArray, Description: "Command to run"},
+		{Name: StdinFromArg, Type: kanister.ArgTypeObject, Description: "Streams the stdin of the commands from the Profile"},
+		{Name: StdoutToArg, Type: kanister.ArgTypeObject, Description: "Streams the stdout of the commands to the Profile"},
+	}
+}
+
+func (*kubeExecAllFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
+		{Name: StdoutPathOutput, Type: kanister.OutputTypeString, Description: "Path the stdout of the commands was streamed to"},
+		{Name: StdoutKopiaSnapshotsOutput, Type: kanister.OutputTypeObject, Description: "Kopia snapshot info of the streamed stdout by pod and container"},
 	}
 }
 
@@ -110,7 +146,7 @@ func (k *kubeExecAllFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error)
 	}, nil
 }
 
-func execAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, cs []string, cmd []string) (map[string]interface{}, error) {
+func execAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, cs []string, cmd []string, profile *param.Profile, stdinFrom *StdinFrom) (map[string]interface{}, error) {
 	numContainers := len(ps) * len(cs)
 	errChan := make(chan error, numContainers)
 	output := ""
@@ -118,9 +154,19 @@ func execAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps
 	for _, p := range ps {
 		for _, c := range cs {
 			go func(p string, c string) {
-				stdout, stderr, err := kube.Exec(cli, namespace, p, c, cmd, nil)
+				// Every container reads its own copy of the data
+				var stdin io.Reader
+				var download *stdinDownload
+				if stdinFrom != nil {
+					download = startStdinDownload(ctx, profile, stdinFrom)
+					stdin = download
+				}
+				stdout, stderr, err := kube.Exec(cli, namespace, p, c, cmd, stdin)
 				format.LogWithCtx(ctx, p, c, stdout)
 				format.LogWithCtx(ctx, p, c, stderr)
+				if download != nil {
+					err = download.finish(err)
+				}
 				errChan <- err
 				output = output + "\n" + stdout
 			}(p, c)
@@ -142,3 +188,53 @@ func execAll(ctx context.Context, cli kubernetes.Interface, namespace string, ps
 	}
 	return out, nil
 }
+
+// execAllStdoutTo runs the command like execAll, but uploads the stdout of
+// every container to <path>/<pod>/<container> instead of parsing it
+func execAllStdoutTo(ctx context.Context, cli kubernetes.Interface, namespace string, ps []string, cs []string, cmd []string, profile *param.Profile, stdinFrom *StdinFrom, stdoutTo *StdoutTo) (map[string]interface{}, error) {
+	numContainers := len(ps) * len(cs)
+	errChan := make(chan error, numContainers)
+	var mu sync.Mutex
+	snapshots := make(map[string]interface{}, numContainers)
+	for _, p := range ps {
+		for _, c := range cs {
+			go func(p string, c string) {
+				var stdin io.Reader
+				var download *stdinDownload
+				if stdinFrom != nil {
+					download = startStdinDownload(ctx, profile, stdinFrom)
+					stdin = download
+				}
+				upload := startStdoutUpload(ctx, profile, path.Join(stdoutTo.Path, p, c))
+				stderr := &bytes.Buffer{}
+				err := kube.ExecOutput(cli, namespace, p, c, cmd, stdin, upload, stderr)
+				format.LogWithCtx(ctx, p, c, stderr.String())
+				if download != nil {
+					err = download.finish(err)
+				}
+				snapshot, err := upload.finish(err)
+				if snapshot != "" {
+					mu.Lock()
+					snapshots[path.Join(p, c)] = snapshot
+					mu.Unlock()
+				}
+				errChan <- errors.Wrapf(err, "Failed to execute command in container %s of pod %s", c, p)
+			}(p, c)
+		}
+	}
+	errs := make([]string, 0, numContainers)
+	for i := 0; i < numContainers; i++ {
+		err := <-errChan
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) != 0 {
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	out := map[string]interface{}{StdoutPathOutput: stdoutTo.Path}
+	if len(snapshots) != 0 {
+		out[StdoutKopiaSnapshotsOutput] = snapshots
+	}
+	return out, nil
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -40,13 +41,27 @@ const (
 	KubeTaskImageArg       = "image"
 	KubeTaskCommandArg     = "command"
 	KubeTaskPodOverrideArg = "podOverride"
+	// KubeTaskContainersArg, KubeTaskInitContainersArg and KubeTaskVolumesArg
+	// take container and volume specs of the pod, in the format of the Pod API
+	KubeTaskContainersArg     = "containers"
+	KubeTaskInitContainersArg = "initContainers"
+	KubeTaskVolumesArg        = "volumes"
+	// KubeTaskOutputContainerArg names the container whose logs are parsed for
+	// output. It defaults to the first of the containers.
+	KubeTaskOutputContainerArg = "outputContainer"
+	// KubeTaskResultFileArg collects the result file written with
+	// `kando output --file` as output, in addition to the outputs in the logs
+	KubeTaskResultFileArg = "resultFile"
 )
 
 func init() {
 	_ = kanister.Register(&kubeTaskFunc{})
 }
 
-var _ kanister.Func = (*kubeTaskFunc)(nil)
+var (
+	_ kanister.Func          = (*kubeTaskFunc)(nil)
+	_ kanister.ArgsValidator = (*kubeTaskFunc)(nil)
+)
 
 type kubeTaskFunc struct {
 	progressPercent string
@@ -64,13 +79,16 @@ func kubeTask(ctx context.Context, cli kubernetes.Interface, namespace, image st
 		Command:      command,
 		PodOverride:  podOverride,
 	}
+	return kubeTaskWithOptions(ctx, cli, options)
+}
 
+func kubeTaskWithOptions(ctx context.Context, cli kubernetes.Interface, options *kube.PodOptions) (map[string]interface{}, error) {
 	pr := kube.NewPodRunner(cli, options)
-	podFunc := kubeTaskPodFunc()
+	podFunc := kubeTaskPodFunc(options.ResultFile)
 	return pr.Run(ctx, podFunc)
 }
 
-func kubeTaskPodFunc() func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
+func kubeTaskPodFunc(resultFile bool) func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
 	return func(ctx context.Context, pc kube.PodController) (map[string]interface{}, error) {
 		if err := pc.WaitForPodReady(ctx); err != nil {
 			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to be ready", pc.PodName())
@@ -85,6 +103,15 @@ func kubeTaskPodFunc() func(ctx context.Context, pc kube.PodController) (map[str
 		if err != nil {
 			return nil, err
 		}
+		if resultFile {
+			result, err := pc.GetResult(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to collect result of Pod %s", pc.PodName())
+			}
+			for k, v := range result {
+				out[k] = v
+			}
+		}
 		// Wait for pod completion
 		if err := pc.WaitForPodCompletion(ctx); err != nil {
 			return nil, errors.Wrapf(err, "Failed while waiting for Pod %s to complete", pc.PodName())
@@ -98,35 +125,126 @@ func (ktf *kubeTaskFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 	ktf.progressPercent = progress.StartedPercent
 	defer func() { ktf.progressPercent = progress.CompletedPercent }()
 
-	var namespace, image string
+	var namespace, image, outputContainer string
 	var command []string
+	var containers, initContainers []v1.Container
+	var volumes []v1.Volume
 	var err error
-	if err = Arg(args, KubeTaskImageArg, &image); err != nil {
+	if err = OptArg(args, KubeTaskImageArg, &image, ""); err != nil {
 		return nil, err
 	}
-	if err = Arg(args, KubeTaskCommandArg, &command); err != nil {
+	if err = OptArg(args, KubeTaskCommandArg, &command, nil); err != nil {
 		return nil, err
 	}
 	if err = OptArg(args, KubeTaskNamespaceArg, &namespace, ""); err != nil {
 		return nil, err
 	}
+	if err = GetSpecArg(args, KubeTaskContainersArg, &containers); err != nil {
+		return nil, err
+	}
+	if err = GetSpecArg(args, KubeTaskInitContainersArg, &initContainers); err != nil {
+		return nil, err
+	}
+	if err = GetSpecArg(args, KubeTaskVolumesArg, &volumes); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, KubeTaskOutputContainerArg, &outputContainer, ""); err != nil {
+		return nil, err
+	}
 	podOverride, err := GetPodSpecOverride(tp, args, KubeTaskPodOverrideArg)
 	if err != nil {
 		return nil, err
 	}
+	options, err := kubeTaskPodOptions(namespace, image, command, containers, initContainers, volumes, outputContainer)
+	if err != nil {
+		return nil, err
+	}
+	options.PodOverride = podOverride
+	if options.Job, err = GetJobOptions(args); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, KubeTaskResultFileArg, &options.ResultFile, false); err != nil {
+		return nil, err
+	}
 
 	cli, err := kube.NewClient()
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
 	}
-	return kubeTask(ctx, cli, namespace, image, command, podOverride)
+	return kubeTaskWithOptions(ctx, cli, options)
+}
+
+// kubeTaskPodOptions returns the options of a pod running either a single image
+// and command or the given containers
+func kubeTaskPodOptions(namespace, image string, command []string, containers, initContainers []v1.Container, volumes []v1.Volume, outputContainer string) (*kube.PodOptions, error) {
+	options := &kube.PodOptions{
+		Namespace:      namespace,
+		GenerateName:   jobPrefix,
+		InitContainers: initContainers,
+		PodVolumes:     volumes,
+	}
+	if len(containers) == 0 {
+		if image == "" || len(command) == 0 {
+			return nil, errors.Errorf("Arguments %s and %s or %s are required", KubeTaskImageArg, KubeTaskCommandArg, KubeTaskContainersArg)
+		}
+		if outputContainer != "" {
+			return nil, errors.Errorf("Argument %s requires %s", KubeTaskOutputContainerArg, KubeTaskContainersArg)
+		}
+		options.Image = image
+		options.Command = command
+		return options, nil
+	}
+	if image != "" || len(command) > 0 {
+		return nil, errors.Errorf("Arguments %s and %s can not be used with %s", KubeTaskImageArg, KubeTaskCommandArg, KubeTaskContainersArg)
+	}
+	if outputContainer == "" {
+		outputContainer = containers[0].Name
+	}
+	found := false
+	for _, c := range containers {
+		if c.Name == "" || c.Image == "" {
+			return nil, errors.Errorf("Containers in %s require a name and an image", KubeTaskContainersArg)
+		}
+		found = found || c.Name == outputContainer
+	}
+	if !found {
+		return nil, errors.Errorf("Output container %s not found in %s", outputContainer, KubeTaskContainersArg)
+	}
+	// The output container becomes the main container of the pod
+	options.ContainerName = outputContainer
+	options.Containers = containers
+	return options, nil
 }
 
 func (*kubeTaskFunc) RequiredArgs() []string {
-	return []string{
-		KubeTaskImageArg,
-		KubeTaskCommandArg,
+	// Either image and command or containers are required, see ValidateArgs
+	return []string{}
+}
+
+// ValidateArgs checks that either image and command or containers are set
+func (*kubeTaskFunc) ValidateArgs(args map[string]interface{}) error {
+	if ArgExists(args, KubeTaskContainersArg) {
+		switch c := args[KubeTaskContainersArg].(type) {
+		case nil:
+		case string:
+			if c != "" {
+				return nil
+			}
+		case []interface{}:
+			if len(c) != 0 {
+				return nil
+			}
+		default:
+			return nil
+		}
+		return errors.Errorf("Argument %s can not be empty", KubeTaskContainersArg)
+	}
+	for _, a := range []string{KubeTaskImageArg, KubeTaskCommandArg} {
+		if !ArgExists(args, a) {
+			return errors.Errorf("Required arg missing: %s", a)
+		}
 	}
+	return nil
 }
 
 func (*kubeTaskFunc) Arguments() []string {
@@ -135,6 +253,33 @@ func (*kubeTaskFunc) Arguments() []string {
 		KubeTaskCommandArg,
 		KubeTaskNamespaceArg,
 		KubeTaskPodOverrideArg,
+		KubeTaskContainersArg,
+		KubeTaskInitContainersArg,
+		KubeTaskVolumesArg,
+		KubeTaskOutputContainerArg,
+		JobBackoffLimitArg,
+		JobActiveDeadlineSecondsArg,
+		KubeTaskResultFileArg,
+	}
+}
+
+func (*kubeTaskFunc) ArgSchemas() []kanister.ArgSchema {
+	return append([]kanister.ArgSchema{
+		{Name: KubeTaskImageArg, Type: kanister.ArgTypeString, Description: "Image of the container"},
+		{Name: KubeTaskCommandArg, Type: kanister.ArgTypeArray, Description: "Command of the container"},
+		{Name: KubeTaskNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pod, defaults to the namespace of the controller"},
+		podOverrideArgSchema(KubeTaskPodOverrideArg),
+		{Name: KubeTaskContainersArg, Type: kanister.ArgTypeArray, Description: "Containers of the pod, instead of image and command"},
+		{Name: KubeTaskInitContainersArg, Type: kanister.ArgTypeArray, Description: "Init containers of the pod"},
+		{Name: KubeTaskVolumesArg, Type: kanister.ArgTypeArray, Description: "Volumes of the pod"},
+		{Name: KubeTaskOutputContainerArg, Type: kanister.ArgTypeString, Description: "Container whose logs are parsed for the outputs, defaults to the first container"},
+		{Name: KubeTaskResultFileArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Reads the outputs from the result file written by the command"},
+	}, jobArgSchemas...)
+}
+
+func (*kubeTaskFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
 	}
 }
 
@@ -161,3 +161,141 @@ func (s *KubeTaskSuite) TestKubeTask(c *C) {
 		}
 	}
 }
+
+func multiContainerPhase(namespace string) crv1alpha1.BlueprintPhase {
+	return crv1alpha1.BlueprintPhase{
+		Name: "testMultiContainer",
+		Func: KubeTaskFuncName,
+		Args: map[string]interface{}{
+			KubeTaskNamespaceArg: namespace,
+			KubeTaskVolumesArg: []interface{}{
+				map[string]interface{}{"name": "shared", "emptyDir": map[string]interface{}{}},
+			},
+			KubeTaskInitContainersArg: []interface{}{
+				map[string]interface{}{
+					"name":         "dump",
+					"image":        consts.LatestKanisterToolsImage,
+					"command":      []interface{}{"sh", "-c", "echo 0.99.0 > /shared/version"},
+					"volumeMounts": []interface{}{map[string]interface{}{"name": "shared", "mountPath": "/shared"}},
+				},
+			},
+			KubeTaskContainersArg: []interface{}{
+				map[string]interface{}{
+					"name":         "upload",
+					"image":        consts.LatestKanisterToolsImage,
+					"command":      []interface{}{"sh", "-c", "kando output version $(cat /shared/version)"},
+					"volumeMounts": []interface{}{map[string]interface{}{"name": "shared", "mountPath": "/shared"}},
+				},
+			},
+		},
+	}
+}
+
+func (s *KubeTaskSuite) TestKubeTaskMultipleContainers(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	tp := param.TemplateParams{
+		StatefulSet: &param.StatefulSetParams{
+			Namespace: s.namespace,
+		},
+	}
+	action := "test"
+	bp := newTaskBlueprint(multiContainerPhase(s.namespace))
+	phases, err := kanister.GetPhases(*bp, action, kanister.DefaultVersion, tp)
+	c.Assert(err, IsNil)
+	c.Assert(phases, HasLen, 1)
+	out, err := phases[0].Exec(ctx, *bp, action, tp)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, map[string]interface{}{"version": "0.99.0"})
+}
+
+type KubeTaskPodOptionsSuite struct{}
+
+var _ = Suite(&KubeTaskPodOptionsSuite{})
+
+func (s *KubeTaskPodOptionsSuite) TestKubeTaskPodOptions(c *C) {
+	containers := []v1.Container{
+		{Name: "dump", Image: "dump-image"},
+		{Name: "upload", Image: "upload-image"},
+	}
+	for _, tc := range []struct {
+		image           string
+		command         []string
+		containers      []v1.Container
+		outputContainer string
+		containerName   string
+		errChecker      Checker
+	}{
+		{
+			image:      "image",
+			command:    []string{"date"},
+			errChecker: IsNil,
+		},
+		{
+			containers:    containers,
+			containerName: "dump",
+			errChecker:    IsNil,
+		},
+		{
+			containers:      containers,
+			outputContainer: "upload",
+			containerName:   "upload",
+			errChecker:      IsNil,
+		},
+		{
+			containers:      containers,
+			outputContainer: "missing",
+			errChecker:      NotNil,
+		},
+		{
+			image:      "image",
+			command:    []string{"date"},
+			containers: containers,
+			errChecker: NotNil,
+		},
+		{
+			image:      "image",
+			errChecker: NotNil,
+		},
+		{
+			image:           "image",
+			command:         []string{"date"},
+			outputContainer: "upload",
+			errChecker:      NotNil,
+		},
+		{
+			containers: []v1.Container{{Name: "dump"}},
+			errChecker: NotNil,
+		},
+	} {
+		opts, err := kubeTaskPodOptions("ns", tc.image, tc.command, tc.containers, nil, nil, tc.outputContainer)
+		c.Assert(err, tc.errChecker)
+		if err != nil {
+			continue
+		}
+		c.Assert(opts.Image, Equals, tc.image)
+		c.Assert(opts.ContainerName, Equals, tc.containerName)
+		c.Assert(opts.Containers, DeepEquals, tc.containers)
+	}
+}
+
+func (s *KubeTaskSuite) TestKubeTaskJob(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	tp := param.TemplateParams{
+		StatefulSet: &param.StatefulSetParams{
+			Namespace: s.namespace,
+		},
+	}
+	action := "test"
+	phase := outputPhase(s.namespace)
+	phase.Args[JobBackoffLimitArg] = 2
+	phase.Args[JobActiveDeadlineSecondsArg] = 300
+	bp := newTaskBlueprint(phase)
+	phases, err := kanister.GetPhases(*bp, action, kanister.DefaultVersion, tp)
+	c.Assert(err, IsNil)
+	c.Assert(phases, HasLen, 1)
+	out, err := phases[0].Exec(ctx, *bp, action, tp)
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, map[string]interface{}{"version": "0.99.0"})
+}
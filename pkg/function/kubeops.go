@@ -137,6 +137,26 @@ func (*kubeops) Arguments() []string {
 	}
 }
 
+func (*kubeops) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: KubeOpsSpecArg, Type: kanister.ArgTypeString, Description: "Manifest of the object to create"},
+		{Name: KubeOpsOperationArg, Type: kanister.ArgTypeString, Enum: []string{string(kube.CreateOperation), string(kube.DeleteOperation)}, Description: "Operation to perform"},
+		{Name: KubeOpsNamespaceArg, Type: kanister.ArgTypeString, Default: "default", Description: "Namespace of the object"},
+		{Name: KubeOpsObjectReferenceArg, Type: kanister.ArgTypeObject, Description: "Reference of the object to delete"},
+	}
+}
+
+func (*kubeops) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: "apiVersion", Type: kanister.OutputTypeString, Description: "API version of the object"},
+		{Name: "group", Type: kanister.OutputTypeString, Description: "API group of the object"},
+		{Name: "resource", Type: kanister.OutputTypeString, Description: "Resource of the object"},
+		{Name: "kind", Type: kanister.OutputTypeString, Description: "Kind of the object"},
+		{Name: "name", Type: kanister.OutputTypeString, Description: "Name of the object"},
+		{Name: "namespace", Type: kanister.OutputTypeString, Description: "Namespace of the object"},
+	}
+}
+
 func (k *kubeops) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
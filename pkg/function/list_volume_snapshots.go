@@ -0,0 +1,248 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/csi"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/config"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+func init() {
+	_ = kanister.Register(&listVolumeSnapshotsFunc{})
+}
+
+var (
+	_ kanister.Func = (*listVolumeSnapshotsFunc)(nil)
+)
+
+const (
+	// ListVolumeSnapshotsFuncName gives the name of the function
+	ListVolumeSnapshotsFuncName       = "ListVolumeSnapshots"
+	ListVolumeSnapshotsTypeArg        = "type"
+	ListVolumeSnapshotsRegionArg      = "region"
+	ListVolumeSnapshotsNamespaceArg   = "namespace"
+	ListVolumeSnapshotsTagsArg        = "tags"
+	ListVolumeSnapshotsOlderThanArg   = "olderThan"
+	ListVolumeSnapshotsCredentialsArg = "credentials"
+	ListVolumeSnapshotsOutputArg      = "volumeSnapshotInfo"
+)
+
+type listVolumeSnapshotsFunc struct {
+	progressPercent string
+}
+
+// listedVolumeSnapshot extends VolumeSnapshotInfo with the creation time
+// so that the output can also be consumed by the other snapshot functions.
+type listedVolumeSnapshot struct {
+	VolumeSnapshotInfo
+	CreationTime time.Time
+}
+
+// volumeSnapshotFilter selects the snapshots of a storage type by tags and age
+type volumeSnapshotFilter struct {
+	sType     blockstorage.Type
+	region    string
+	namespace string
+	tags      map[string]string
+	olderThan time.Duration
+	creds     map[string]string
+	// clusterName is the value of the Kanister cluster tag of the snapshots
+	clusterName string
+}
+
+func (*listVolumeSnapshotsFunc) Name() string {
+	return ListVolumeSnapshotsFuncName
+}
+
+// snapshotFilterTags returns the cluster tag extended with tags. The other
+// standard tags, e.g. the Kanister version, are not used so that snapshots
+// taken by previous Kanister versions still match. The cluster tag is
+// required so that snapshots not created by Kanister never match.
+func snapshotFilterTags(clusterName string, tags map[string]string) (map[string]string, error) {
+	filterTags := ktags.Union(map[string]string{ktags.ClusterTagKey: clusterName}, tags)
+	if filterTags[ktags.ClusterTagKey] == "" {
+		return nil, errors.Errorf("Tag %s is required to filter the snapshots created by Kanister", ktags.ClusterTagKey)
+	}
+	return filterTags, nil
+}
+
+func listVolumeSnapshots(ctx context.Context, profile *param.Profile, filter volumeSnapshotFilter, getter getter.Getter) (blockstorage.Provider, []listedVolumeSnapshot, error) {
+	tags, err := snapshotFilterTags(filter.clusterName, filter.tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	config, err := blockstorageConfig(profile, filter.sType, filter.region, filter.creds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isCSIStorageType(filter.sType) {
+		config[csi.NamespaceKey] = filter.namespace
+	}
+	provider, err := getter.Get(filter.sType, config)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Could not get storage provider %v", filter.sType)
+	}
+	snaps, err := provider.SnapshotsList(ctx, tags)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to list snapshots")
+	}
+	now := time.Now()
+	listed := make([]listedVolumeSnapshot, 0, len(snaps))
+	for _, snap := range snaps {
+		created := time.Time(snap.CreationTime)
+		if filter.olderThan > 0 && now.Sub(created) < filter.olderThan {
+			continue
+		}
+		info := VolumeSnapshotInfo{
+			SnapshotID: snap.ID,
+			Type:       filter.sType,
+			Region:     snap.Region,
+			PVCName:    blockstorage.KeyValueToMap(snap.Tags)["pvcname"],
+		}
+		if info.Region == "" {
+			info.Region = filter.region
+		}
+		if snap.Volume != nil {
+			info.Az = snap.Volume.Az
+			info.VolumeType = snap.Volume.VolumeType
+			info.Tags = snap.Volume.Tags
+		}
+		listed = append(listed, listedVolumeSnapshot{VolumeSnapshotInfo: info, CreationTime: created})
+	}
+	return provider, listed, nil
+}
+
+// volumeSnapshotFilterArgs parses the arguments shared by ListVolumeSnapshots and PruneVolumeSnapshots
+func volumeSnapshotFilterArgs(tp param.TemplateParams, args map[string]interface{}) (volumeSnapshotFilter, error) {
+	var filter volumeSnapshotFilter
+	var sType, olderThan string
+	if err := Arg(args, ListVolumeSnapshotsTypeArg, &sType); err != nil {
+		return filter, err
+	}
+	filter.sType = blockstorage.Type(sType)
+	var defaultRegion string
+	if tp.Profile != nil {
+		defaultRegion = tp.Profile.Location.Region
+	}
+	if err := OptArg(args, ListVolumeSnapshotsRegionArg, &filter.region, defaultRegion); err != nil {
+		return filter, err
+	}
+	if err := OptArg(args, ListVolumeSnapshotsNamespaceArg, &filter.namespace, ""); err != nil {
+		return filter, err
+	}
+	if err := OptArg(args, ListVolumeSnapshotsTagsArg, &filter.tags, nil); err != nil {
+		return filter, err
+	}
+	if err := OptArg(args, ListVolumeSnapshotsOlderThanArg, &olderThan, ""); err != nil {
+		return filter, err
+	}
+	if olderThan != "" {
+		d, err := time.ParseDuration(olderThan)
+		if err != nil {
+			return filter, errors.Wrapf(err, "Failed to parse %s", ListVolumeSnapshotsOlderThanArg)
+		}
+		filter.olderThan = d
+	}
+	if err := OptArg(args, ListVolumeSnapshotsCredentialsArg, &filter.creds, nil); err != nil {
+		return filter, err
+	}
+	clusterName, err := config.GetClusterName(nil)
+	if err != nil {
+		return filter, errors.Wrap(err, "Failed to get the cluster name")
+	}
+	if clusterName == "" {
+		return filter, errors.New("Failed to get the cluster name")
+	}
+	filter.clusterName = clusterName
+	return filter, nil
+}
+
+func volumeSnapshotsOutput(listed []listedVolumeSnapshot) (map[string]interface{}, error) {
+	manifestData, err := json.Marshal(listed)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to encode JSON data")
+	}
+	return map[string]interface{}{ListVolumeSnapshotsOutputArg: string(manifestData)}, nil
+}
+
+func (l *listVolumeSnapshotsFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	l.progressPercent = progress.StartedPercent
+	defer func() { l.progressPercent = progress.CompletedPercent }()
+
+	filter, err := volumeSnapshotFilterArgs(tp, args)
+	if err != nil {
+		return nil, err
+	}
+	_, listed, err := listVolumeSnapshots(ctx, tp.Profile, filter, getter.New())
+	if err != nil {
+		return nil, err
+	}
+	return volumeSnapshotsOutput(listed)
+}
+
+func (*listVolumeSnapshotsFunc) RequiredArgs() []string {
+	return []string{ListVolumeSnapshotsTypeArg}
+}
+
+func (*listVolumeSnapshotsFunc) Arguments() []string {
+	return []string{
+		ListVolumeSnapshotsTypeArg,
+		ListVolumeSnapshotsRegionArg,
+		ListVolumeSnapshotsNamespaceArg,
+		ListVolumeSnapshotsTagsArg,
+		ListVolumeSnapshotsOlderThanArg,
+		ListVolumeSnapshotsCredentialsArg,
+	}
+}
+
+func (*listVolumeSnapshotsFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: ListVolumeSnapshotsTypeArg, Type: kanister.ArgTypeString, Description: "Type of the block storage provider, e.g. AWSEBS"},
+		{Name: ListVolumeSnapshotsRegionArg, Type: kanister.ArgTypeString, Description: "Region of the snapshots, defaults to the region of the Profile"},
+		{Name: ListVolumeSnapshotsNamespaceArg, Type: kanister.ArgTypeString, Description: "Only lists the snapshots of the volumes of this namespace"},
+		{Name: ListVolumeSnapshotsTagsArg, Type: kanister.ArgTypeObject, Description: "Only lists the snapshots with these tags"},
+		{Name: ListVolumeSnapshotsOlderThanArg, Type: kanister.ArgTypeString, Description: "Only lists the snapshots older than this duration, e.g. 24h"},
+		{Name: ListVolumeSnapshotsCredentialsArg, Type: kanister.ArgTypeObject, Description: "Credentials of the provider, defaults to the credentials of the Profile"},
+	}
+}
+
+func (*listVolumeSnapshotsFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: ListVolumeSnapshotsOutputArg, Type: kanister.OutputTypeString, Description: "JSON of the listed snapshots"},
+	}
+}
+
+func (l *listVolumeSnapshotsFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    l.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	ktags "github.com/kanisterio/kanister/pkg/blockstorage/tags"
+	"github.com/kanisterio/kanister/pkg/config"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/testutil/mockblockstorage"
+)
+
+type ListVolumeSnapshotsTestSuite struct{}
+
+var _ = Suite(&ListVolumeSnapshotsTestSuite{})
+
+// providerGetter always returns the same provider
+type providerGetter struct {
+	provider blockstorage.Provider
+}
+
+func (g providerGetter) Get(blockstorage.Type, map[string]string) (blockstorage.Provider, error) {
+	return g.provider, nil
+}
+
+var _ getter.Getter = providerGetter{}
+
+func (s *ListVolumeSnapshotsTestSuite) profile() *param.Profile {
+	return &param.Profile{
+		Location: crv1alpha1.Location{
+			Type:   crv1alpha1.LocationTypeS3Compliant,
+			Region: "us-west-2",
+		},
+		Credential: param.Credential{
+			Type: param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{
+				ID:     "foo",
+				Secret: "bar",
+			},
+		},
+	}
+}
+
+func (s *ListVolumeSnapshotsTestSuite) TestListVolumeSnapshots(c *C) {
+	ctx := context.Background()
+	filter := volumeSnapshotFilter{sType: blockstorage.TypeEBS, region: "us-west-2", olderThan: time.Hour, clusterName: "cluster"}
+	_, listed, err := listVolumeSnapshots(ctx, s.profile(), filter, mockblockstorage.NewGetter())
+	c.Assert(err, IsNil)
+	c.Assert(listed, HasLen, 2)
+	c.Assert(listed[0].Type, Equals, blockstorage.TypeEBS)
+	c.Assert(listed[0].Region, Equals, "us-west-2")
+	c.Assert(listed[0].VolumeType, Equals, "ssd")
+
+	out, err := volumeSnapshotsOutput(listed)
+	c.Assert(err, IsNil)
+	// The output can be consumed by functions expecting VolumeSnapshotInfo
+	var infos []VolumeSnapshotInfo
+	err = json.Unmarshal([]byte(out[ListVolumeSnapshotsOutputArg].(string)), &infos)
+	c.Assert(err, IsNil)
+	c.Assert(infos, HasLen, 2)
+	c.Assert(infos[0].SnapshotID, Equals, listed[0].SnapshotID)
+
+	_, _, err = listVolumeSnapshots(ctx, s.profile(), volumeSnapshotFilter{sType: blockstorage.TypeAD, clusterName: "cluster"}, mockblockstorage.NewGetter())
+	c.Assert(err, NotNil)
+}
+
+func (s *ListVolumeSnapshotsTestSuite) TestPruneVolumeSnapshots(c *C) {
+	ctx := context.Background()
+	_, err := pruneVolumeSnapshots(ctx, s.profile(), volumeSnapshotFilter{sType: blockstorage.TypeEBS, region: "us-west-2", clusterName: "cluster"}, false, mockblockstorage.NewGetter())
+	c.Assert(err, NotNil)
+
+	filter := volumeSnapshotFilter{sType: blockstorage.TypeEBS, region: "us-west-2", olderThan: time.Hour, clusterName: "cluster"}
+	for _, dryRun := range []bool{true, false} {
+		provider, err := mockblockstorage.Get(blockstorage.TypeEBS)
+		c.Assert(err, IsNil)
+		pruned, err := pruneVolumeSnapshots(ctx, s.profile(), filter, dryRun, providerGetter{provider: provider})
+		c.Assert(err, IsNil)
+		c.Assert(pruned, HasLen, 2)
+		if dryRun {
+			c.Assert(provider.DeletedSnapIDList, HasLen, 0)
+		} else {
+			c.Assert(mockblockstorage.CheckID(pruned[0].SnapshotID, provider.DeletedSnapIDList), Equals, true)
+		}
+	}
+}
+
+func (s *ListVolumeSnapshotsTestSuite) TestSnapshotFilterTags(c *C) {
+	tags, err := snapshotFilterTags("cluster", map[string]string{"pvcname": "pvc-1"})
+	c.Assert(err, IsNil)
+	c.Assert(tags, DeepEquals, map[string]string{ktags.ClusterTagKey: "cluster", "pvcname": "pvc-1"})
+
+	// The cluster tag is required
+	_, err = snapshotFilterTags("", map[string]string{"pvcname": "pvc-1"})
+	c.Assert(err, NotNil)
+	_, err = snapshotFilterTags("cluster", map[string]string{ktags.ClusterTagKey: ""})
+	c.Assert(err, NotNil)
+}
+
+func (s *ListVolumeSnapshotsTestSuite) TestPruneVolumeSnapshotsWithoutClusterName(c *C) {
+	// The cluster name is unavailable, e.g. without access to kube-system
+	restore := setClusterNameEnv("")
+	defer restore()
+	_, err := volumeSnapshotFilterArgs(param.TemplateParams{Profile: s.profile()}, map[string]interface{}{
+		ListVolumeSnapshotsTypeArg:      "EBS",
+		ListVolumeSnapshotsOlderThanArg: "24h",
+	})
+	c.Assert(err, NotNil)
+
+	provider, err := mockblockstorage.Get(blockstorage.TypeEBS)
+	c.Assert(err, IsNil)
+	filter := volumeSnapshotFilter{sType: blockstorage.TypeEBS, region: "us-west-2", olderThan: time.Hour}
+	_, err = pruneVolumeSnapshots(context.Background(), s.profile(), filter, false, providerGetter{provider: provider})
+	c.Assert(err, NotNil)
+	c.Assert(provider.DeletedSnapIDList, HasLen, 0)
+}
+
+// setClusterNameEnv sets the cluster name returned by config.GetClusterName
+// and returns a function restoring the previous value
+func setClusterNameEnv(name string) func() {
+	prev, ok := os.LookupEnv(config.ClusterNameEnvName)
+	_ = os.Setenv(config.ClusterNameEnvName, name)
+	return func() {
+		if ok {
+			_ = os.Setenv(config.ClusterNameEnvName, prev)
+		} else {
+			_ = os.Unsetenv(config.ClusterNameEnvName)
+		}
+	}
+}
+
+func (s *ListVolumeSnapshotsTestSuite) TestVolumeSnapshotFilterArgs(c *C) {
+	restore := setClusterNameEnv("cluster")
+	defer restore()
+	tp := param.TemplateParams{Profile: s.profile()}
+	filter, err := volumeSnapshotFilterArgs(tp, map[string]interface{}{
+		ListVolumeSnapshotsTypeArg:      "EBS",
+		ListVolumeSnapshotsOlderThanArg: "24h",
+		ListVolumeSnapshotsTagsArg:      map[string]interface{}{"pvcname": "pvc-1"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(filter.sType, Equals, blockstorage.TypeEBS)
+	c.Assert(filter.region, Equals, "us-west-2")
+	c.Assert(filter.olderThan, Equals, 24*time.Hour)
+	c.Assert(filter.tags, DeepEquals, map[string]string{"pvcname": "pvc-1"})
+	c.Assert(filter.clusterName, Equals, "cluster")
+
+	_, err = volumeSnapshotFilterArgs(tp, map[string]interface{}{
+		ListVolumeSnapshotsTypeArg:      "EBS",
+		ListVolumeSnapshotsOlderThanArg: "a day",
+	})
+	c.Assert(err, NotNil)
+	_, err = volumeSnapshotFilterArgs(tp, map[string]interface{}{})
+	c.Assert(err, NotNil)
+}
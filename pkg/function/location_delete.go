@@ -74,6 +74,16 @@ func (*locationDeleteFunc) Arguments() []string {
 	return []string{LocationDeleteArtifactArg}
 }
 
+func (*locationDeleteFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: LocationDeleteArtifactArg, Type: kanister.ArgTypeString, Description: "Path of the artifact to delete in the Profile"},
+	}
+}
+
+func (*locationDeleteFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (l *locationDeleteFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
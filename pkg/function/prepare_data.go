@@ -83,7 +83,7 @@ func getVolumes(tp param.TemplateParams) (map[string]string, error) {
 	return vols, nil
 }
 
-func prepareData(ctx context.Context, cli kubernetes.Interface, namespace, serviceAccount, image string, vols map[string]string, podOverride crv1alpha1.JSONMap, command ...string) (map[string]interface{}, error) {
+func prepareData(ctx context.Context, cli kubernetes.Interface, namespace, serviceAccount, image string, vols map[string]string, podOverride crv1alpha1.JSONMap, jobOptions *kube.JobOptions, command ...string) (map[string]interface{}, error) {
 	// Validate volumes
 	validatedVols := make(map[string]kube.VolumeMountOptions)
 	for pvcName, mountPoint := range vols {
@@ -106,6 +106,7 @@ func prepareData(ctx context.Context, cli kubernetes.Interface, namespace, servi
 		Volumes:            validatedVols,
 		ServiceAccountName: serviceAccount,
 		PodOverride:        podOverride,
+		Job:                jobOptions,
 	}
 	pr := kube.NewPodRunner(cli, options)
 	podFunc := prepareDataPodFunc(cli)
@@ -169,6 +170,10 @@ func (p *prepareDataFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 	if err != nil {
 		return nil, err
 	}
+	jobOptions, err := GetJobOptions(args)
+	if err != nil {
+		return nil, err
+	}
 
 	cli, err := kube.NewClient()
 	if err != nil {
@@ -179,7 +184,7 @@ func (p *prepareDataFunc) Exec(ctx context.Context, tp param.TemplateParams, arg
 			return nil, err
 		}
 	}
-	return prepareData(ctx, cli, namespace, serviceAccount, image, vols, podOverride, command...)
+	return prepareData(ctx, cli, namespace, serviceAccount, image, vols, podOverride, jobOptions, command...)
 }
 
 func (*prepareDataFunc) RequiredArgs() []string {
@@ -198,6 +203,25 @@ func (*prepareDataFunc) Arguments() []string {
 		PrepareDataVolumes,
 		PrepareDataServiceAccount,
 		PrepareDataPodOverrideArg,
+		JobBackoffLimitArg,
+		JobActiveDeadlineSecondsArg,
+	}
+}
+
+func (*prepareDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return append([]kanister.ArgSchema{
+		podNamespaceArgSchema(PrepareDataNamespaceArg),
+		{Name: PrepareDataImageArg, Type: kanister.ArgTypeString, Description: "Image of the pod"},
+		{Name: PrepareDataCommandArg, Type: kanister.ArgTypeArray, Description: "Command of the pod"},
+		{Name: PrepareDataVolumes, Type: kanister.ArgTypeObject, Description: "Mount paths of the PVCs to mount, by PVC name, defaults to the PVCs of the workload"},
+		{Name: PrepareDataServiceAccount, Type: kanister.ArgTypeString, Description: "Service account of the pod"},
+		podOverrideArgSchema(PrepareDataPodOverrideArg),
+	}, jobArgSchemas...)
+}
+
+func (*prepareDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
 	}
 }
 
@@ -0,0 +1,141 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blockstorage/getter"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+func init() {
+	_ = kanister.Register(&pruneVolumeSnapshotsFunc{})
+}
+
+var (
+	_ kanister.Func = (*pruneVolumeSnapshotsFunc)(nil)
+)
+
+const (
+	// PruneVolumeSnapshotsFuncName gives the name of the function
+	PruneVolumeSnapshotsFuncName  = "PruneVolumeSnapshots"
+	PruneVolumeSnapshotsDryRunArg = "dryRun"
+)
+
+type pruneVolumeSnapshotsFunc struct {
+	progressPercent string
+}
+
+func (*pruneVolumeSnapshotsFunc) Name() string {
+	return PruneVolumeSnapshotsFuncName
+}
+
+func pruneVolumeSnapshots(ctx context.Context, profile *param.Profile, filter volumeSnapshotFilter, dryRun bool, getter getter.Getter) ([]listedVolumeSnapshot, error) {
+	// Without tags or olderThan every snapshot of the cluster would match
+	if len(filter.tags) == 0 && filter.olderThan == 0 {
+		return nil, errors.Errorf("At least one of the %s or %s arguments is required", ListVolumeSnapshotsTagsArg, ListVolumeSnapshotsOlderThanArg)
+	}
+	provider, listed, err := listVolumeSnapshots(ctx, profile, filter, getter)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		log.WithContext(ctx).Print("Dry run, skipping deletion of snapshots", field.M{"Count": len(listed)})
+		return listed, nil
+	}
+	for _, l := range listed {
+		snapshot, err := provider.SnapshotGet(ctx, l.SnapshotID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get Snapshot from Provider")
+		}
+		if err = provider.SnapshotDelete(ctx, snapshot); err != nil {
+			return nil, errors.Wrapf(err, "Failed to delete snapshot %s", l.SnapshotID)
+		}
+		log.WithContext(ctx).Print("Successfully deleted snapshot", field.M{"SnapshotID": l.SnapshotID})
+	}
+	return listed, nil
+}
+
+func (p *pruneVolumeSnapshotsFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	p.progressPercent = progress.StartedPercent
+	defer func() { p.progressPercent = progress.CompletedPercent }()
+
+	filter, err := volumeSnapshotFilterArgs(tp, args)
+	if err != nil {
+		return nil, err
+	}
+	var dryRun bool
+	if err = OptArg(args, PruneVolumeSnapshotsDryRunArg, &dryRun, false); err != nil {
+		return nil, err
+	}
+	pruned, err := pruneVolumeSnapshots(ctx, tp.Profile, filter, dryRun, getter.New())
+	if err != nil {
+		return nil, err
+	}
+	return volumeSnapshotsOutput(pruned)
+}
+
+func (*pruneVolumeSnapshotsFunc) RequiredArgs() []string {
+	return []string{ListVolumeSnapshotsTypeArg}
+}
+
+func (*pruneVolumeSnapshotsFunc) Arguments() []string {
+	return []string{
+		ListVolumeSnapshotsTypeArg,
+		ListVolumeSnapshotsRegionArg,
+		ListVolumeSnapshotsNamespaceArg,
+		ListVolumeSnapshotsTagsArg,
+		ListVolumeSnapshotsOlderThanArg,
+		ListVolumeSnapshotsCredentialsArg,
+		PruneVolumeSnapshotsDryRunArg,
+	}
+}
+
+func (*pruneVolumeSnapshotsFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: ListVolumeSnapshotsTypeArg, Type: kanister.ArgTypeString, Description: "Type of the block storage provider, e.g. AWSEBS"},
+		{Name: ListVolumeSnapshotsRegionArg, Type: kanister.ArgTypeString, Description: "Region of the snapshots, defaults to the region of the Profile"},
+		{Name: ListVolumeSnapshotsNamespaceArg, Type: kanister.ArgTypeString, Description: "Only lists the snapshots of the volumes of this namespace"},
+		{Name: ListVolumeSnapshotsTagsArg, Type: kanister.ArgTypeObject, Description: "Only deletes the snapshots with these tags, required if olderThan is not set"},
+		{Name: ListVolumeSnapshotsOlderThanArg, Type: kanister.ArgTypeString, Description: "Only deletes the snapshots older than this duration, e.g. 24h, required if tags is not set"},
+		{Name: ListVolumeSnapshotsCredentialsArg, Type: kanister.ArgTypeObject, Description: "Credentials of the provider, defaults to the credentials of the Profile"},
+		{Name: PruneVolumeSnapshotsDryRunArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Only outputs the snapshots that would be deleted"},
+	}
+}
+
+func (*pruneVolumeSnapshotsFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: ListVolumeSnapshotsOutputArg, Type: kanister.OutputTypeString, Description: "JSON of the pruned snapshots"},
+	}
+}
+
+func (p *pruneVolumeSnapshotsFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    p.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
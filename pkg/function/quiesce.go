@@ -0,0 +1,202 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+	"github.com/kanisterio/kanister/pkg/quiesce"
+)
+
+const (
+	// QuiesceFuncName gives the function name
+	QuiesceFuncName = "Quiesce"
+	// QuiesceNamespaceArg is the namespace of the application
+	QuiesceNamespaceArg = "namespace"
+	// QuiesceHandlerArg is one of fsfreeze, scaleToZero or exec
+	QuiesceHandlerArg = "handler"
+	// QuiescePodArg is the pod in which the fsfreeze and exec handlers run commands
+	QuiescePodArg = "pod"
+	// QuiesceContainerArg is the container in which the fsfreeze and exec handlers run commands
+	QuiesceContainerArg = "container"
+	// QuiescePathsArg are the mount points frozen by the fsfreeze handler
+	QuiescePathsArg = "paths"
+	// QuiesceCommandArg is the command run by the exec handler to quiesce the application
+	QuiesceCommandArg = "quiesceCommand"
+	// QuiesceUnquiesceCommandArg is the command run by the exec handler to unquiesce the application
+	QuiesceUnquiesceCommandArg = "unquiesceCommand"
+	// QuiesceKindArg is the kind of the workload scaled by the scaleToZero handler
+	QuiesceKindArg = "kind"
+	// QuiesceNameArg is the name of the workload scaled by the scaleToZero handler
+	QuiesceNameArg = "name"
+	// QuiesceLeaseArg is the duration after which the controller unquiesces the application
+	QuiesceLeaseArg = "lease"
+
+	defaultQuiesceLease = 10 * time.Minute
+)
+
+func init() {
+	_ = kanister.Register(&quiesceFunc{})
+}
+
+var _ kanister.Func = (*quiesceFunc)(nil)
+
+type quiesceFunc struct {
+	progressPercent string
+}
+
+func (*quiesceFunc) Name() string {
+	return QuiesceFuncName
+}
+
+func (q *quiesceFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	q.progressPercent = progress.StartedPercent
+	defer func() { q.progressPercent = progress.CompletedPercent }()
+
+	record, quiesceCommand, lease, err := parseQuiesceArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+	if err = quiesce.Quiesce(ctx, cli, record, quiesceCommand); err != nil {
+		return nil, errors.Wrapf(err, "Failed to quiesce the application with handler %s", record.Handler)
+	}
+	// The lease starts once the application is quiesced
+	record.ExpiresAt = metav1.NewTime(time.Now().Add(lease))
+	out, err := quiesce.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{quiesce.OutputQuiesce: out}, nil
+}
+
+func (*quiesceFunc) RequiredArgs() []string {
+	return []string{
+		QuiesceNamespaceArg,
+		QuiesceHandlerArg,
+	}
+}
+
+func (*quiesceFunc) Arguments() []string {
+	return []string{
+		QuiesceNamespaceArg,
+		QuiesceHandlerArg,
+		QuiescePodArg,
+		QuiesceContainerArg,
+		QuiescePathsArg,
+		QuiesceCommandArg,
+		QuiesceUnquiesceCommandArg,
+		QuiesceKindArg,
+		QuiesceNameArg,
+		QuiesceLeaseArg,
+	}
+}
+
+func (*quiesceFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: QuiesceNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the application"},
+		{Name: QuiesceHandlerArg, Type: kanister.ArgTypeString, Enum: []string{quiesce.HandlerFSFreeze, quiesce.HandlerScaleToZero, quiesce.HandlerExec}, Description: "How to quiesce the application"},
+		{Name: QuiescePodArg, Type: kanister.ArgTypeString, Description: "Pod to freeze or to run the commands in"},
+		{Name: QuiesceContainerArg, Type: kanister.ArgTypeString, Description: "Container to freeze or to run the commands in"},
+		{Name: QuiescePathsArg, Type: kanister.ArgTypeArray, Description: "Mount paths to freeze"},
+		{Name: QuiesceCommandArg, Type: kanister.ArgTypeArray, Description: "Command quiescing the application"},
+		{Name: QuiesceUnquiesceCommandArg, Type: kanister.ArgTypeArray, Description: "Command unquiescing the application"},
+		{Name: QuiesceKindArg, Type: kanister.ArgTypeString, Description: "Kind of the workload to scale to zero"},
+		{Name: QuiesceNameArg, Type: kanister.ArgTypeString, Description: "Name of the workload to scale to zero"},
+		{Name: QuiesceLeaseArg, Type: kanister.ArgTypeString, Default: "10m", Description: "Duration after which the controller unquiesces the application"},
+	}
+}
+
+func (*quiesceFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: quiesce.OutputQuiesce, Type: kanister.OutputTypeString, Description: "Quiesce record to pass to Unquiesce"},
+	}
+}
+
+func (q *quiesceFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    q.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
+
+// parseQuiesceArgs returns the quiesce record described by the arguments,
+// the quiesce command of the exec handler and the lease.
+func parseQuiesceArgs(args map[string]interface{}) (*crv1alpha1.QuiesceStatus, []string, time.Duration, error) {
+	record := &crv1alpha1.QuiesceStatus{ID: rand.String(10)}
+	var quiesceCommand []string
+	var lease string
+	var err error
+	if err = Arg(args, QuiesceNamespaceArg, &record.Namespace); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = Arg(args, QuiesceHandlerArg, &record.Handler); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiescePodArg, &record.Pod, ""); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceContainerArg, &record.Container, ""); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiescePathsArg, &record.Paths, nil); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceCommandArg, &quiesceCommand, nil); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceUnquiesceCommandArg, &record.UnquiesceCommand, nil); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceKindArg, &record.Kind, ""); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceNameArg, &record.Name, ""); err != nil {
+		return nil, nil, 0, err
+	}
+	if err = OptArg(args, QuiesceLeaseArg, &lease, ""); err != nil {
+		return nil, nil, 0, err
+	}
+	record.Kind = strings.ToLower(record.Kind)
+	if err = quiesce.Validate(record); err != nil {
+		return nil, nil, 0, err
+	}
+	if record.Handler == quiesce.HandlerExec && len(quiesceCommand) == 0 {
+		return nil, nil, 0, errors.Errorf("Argument %s is required by handler %s", QuiesceCommandArg, record.Handler)
+	}
+	leaseDur := defaultQuiesceLease
+	if lease != "" {
+		if leaseDur, err = time.ParseDuration(lease); err != nil {
+			return nil, nil, 0, errors.Wrapf(err, "Failed to parse %s", QuiesceLeaseArg)
+		}
+	}
+	return record, quiesceCommand, leaseDur, nil
+}
@@ -161,6 +161,23 @@ func (*restoreCSISnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*restoreCSISnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: RestoreCSISnapshotNameArg, Type: kanister.ArgTypeString, Description: "Name of the VolumeSnapshot"},
+		{Name: RestoreCSISnapshotPVCNameArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to create"},
+		{Name: RestoreCSISnapshotNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the VolumeSnapshot and the PVC"},
+		{Name: RestoreCSISnapshotStorageClassArg, Type: kanister.ArgTypeString, Description: "Storage class of the PVC"},
+		{Name: RestoreCSISnapshotRestoreSizeArg, Type: kanister.ArgTypeString, Description: "Size of the PVC, e.g. 1Gi"},
+		{Name: RestoreCSISnapshotAccessModesArg, Type: kanister.ArgTypeArray, Default: []string{"ReadWriteOnce"}, Description: "Access modes of the PVC"},
+		{Name: RestoreCSISnapshotVolumeModeArg, Type: kanister.ArgTypeString, Default: "Filesystem", Enum: []string{"Filesystem", "Block"}, Description: "Volume mode of the PVC"},
+		{Name: RestoreCSISnapshotLabelsArg, Type: kanister.ArgTypeObject, Description: "Labels of the PVC"},
+	}
+}
+
+func (*restoreCSISnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (d *restoreCSISnapshotFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
@@ -271,6 +271,27 @@ func (*restoreDataFunc) Arguments() []string {
 	}
 }
 
+func (*restoreDataFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		podNamespaceArgSchema(RestoreDataNamespaceArg),
+		{Name: RestoreDataImageArg, Type: kanister.ArgTypeString, Description: "Image of the pod running restic"},
+		artifactPrefixArgSchema(RestoreDataBackupArtifactPrefixArg),
+		{Name: RestoreDataRestorePathArg, Type: kanister.ArgTypeString, Default: "/", Description: "Path to restore the data to"},
+		encryptionKeyArgSchema(RestoreDataEncryptionKeyArg),
+		{Name: RestoreDataPodArg, Type: kanister.ArgTypeString, Description: "Pod whose volumes are restored"},
+		{Name: RestoreDataVolsArg, Type: kanister.ArgTypeObject, Description: "Mount paths of the PVCs to restore, by PVC name, instead of a pod"},
+		{Name: RestoreDataBackupTagArg, Type: kanister.ArgTypeString, Description: "Tag of the restic snapshot to restore"},
+		{Name: RestoreDataBackupIdentifierArg, Type: kanister.ArgTypeString, Description: "ID of the restic snapshot to restore"},
+		podOverrideArgSchema(RestoreDataPodOverrideArg),
+	}
+}
+
+func (*restoreDataFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
+	}
+}
+
 func (d *restoreDataFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
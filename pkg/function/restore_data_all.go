@@ -200,6 +200,25 @@ func (*restoreDataAllFunc) Arguments() []string {
 	}
 }
 
+func (*restoreDataAllFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: RestoreDataAllNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the pods"},
+		{Name: RestoreDataAllImageArg, Type: kanister.ArgTypeString, Description: "Image of the pods running restic"},
+		{Name: RestoreDataAllBackupArtifactPrefixArg, Type: kanister.ArgTypeString, Description: "Path of the restic repositories in the Profile"},
+		{Name: RestoreDataAllBackupInfo, Type: kanister.ArgTypeString, Description: "Output of BackupDataAll"},
+		{Name: RestoreDataAllRestorePathArg, Type: kanister.ArgTypeString, Default: "/", Description: "Path to restore the data to"},
+		encryptionKeyArgSchema(RestoreDataAllEncryptionKeyArg),
+		{Name: RestoreDataAllPodsArg, Type: kanister.ArgTypeString, Description: "Space separated pods to restore, defaults to the pods of the workload"},
+		podOverrideArgSchema(RestoreDataAllPodOverrideArg),
+	}
+}
+
+func (*restoreDataAllFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		commandOutput,
+	}
+}
+
 func (r *restoreDataAllFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
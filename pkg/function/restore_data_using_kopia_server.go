@@ -76,6 +76,23 @@ func (*restoreDataUsingKopiaServerFunc) Arguments() []string {
 	}
 }
 
+func (*restoreDataUsingKopiaServerFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: RestoreDataBackupIdentifierArg, Type: kanister.ArgTypeString, Description: "ID of the Kopia snapshot to restore"},
+		podNamespaceArgSchema(RestoreDataNamespaceArg),
+		{Name: RestoreDataRestorePathArg, Type: kanister.ArgTypeString, Description: "Path to restore the data to"},
+		{Name: RestoreDataPodArg, Type: kanister.ArgTypeString, Description: "Pod whose volumes are restored"},
+		{Name: RestoreDataVolsArg, Type: kanister.ArgTypeObject, Description: "Mount paths of the PVCs to restore, by PVC name, instead of a pod"},
+		podOverrideArgSchema(RestoreDataPodOverrideArg),
+		{Name: RestoreDataImageArg, Type: kanister.ArgTypeString, Description: "Image of the pod running Kopia"},
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*restoreDataUsingKopiaServerFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (r *restoreDataUsingKopiaServerFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]any) (map[string]any, error) {
 	// Set progress percent
 	r.progressPercent = progress.StartedPercent
@@ -105,6 +105,27 @@ func (*restoreRDSSnapshotFunc) Arguments() []string {
 	}
 }
 
+func (*restoreRDSSnapshotFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: RestoreRDSSnapshotInstanceID, Type: kanister.ArgTypeString, Description: "ID of the RDS instance to restore"},
+		{Name: RestoreRDSSnapshotSnapshotID, Type: kanister.ArgTypeString, Description: "ID of the RDS snapshot to restore from"},
+		{Name: RestoreRDSSnapshotDBEngine, Type: kanister.ArgTypeString, Description: "Engine of the database"},
+		{Name: RestoreRDSSnapshotBackupArtifactPrefix, Type: kanister.ArgTypeString, Description: "Path of the dump in the Profile, to restore from a dump"},
+		{Name: RestoreRDSSnapshotBackupID, Type: kanister.ArgTypeString, Description: "ID of the dump, to restore from a dump"},
+		{Name: RestoreRDSSnapshotUsername, Type: kanister.ArgTypeString, Description: "Username of the database, to restore from a dump"},
+		{Name: RestoreRDSSnapshotPassword, Type: kanister.ArgTypeString, Description: "Password of the database, to restore from a dump"},
+		{Name: RestoreRDSSnapshotNamespace, Type: kanister.ArgTypeString, Description: "Namespace of the pod restoring the dump"},
+		{Name: RestoreRDSSnapshotSecGrpID, Type: kanister.ArgTypeArray, Description: "Security group IDs of the restored instance"},
+		{Name: RestoreRDSSnapshotDBSubnetGroup, Type: kanister.ArgTypeString, Default: "default", Description: "DB subnet group of the restored instance"},
+	}
+}
+
+func (*restoreRDSSnapshotFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: RestoreRDSSnapshotEndpoint, Type: kanister.OutputTypeString, Description: "Endpoint of the restored instance, if restored from a dump"},
+	}
+}
+
 func (r *restoreRDSSnapshotFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
 	// Set progress percent
 	r.progressPercent = progress.StartedPercent
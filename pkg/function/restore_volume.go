@@ -0,0 +1,182 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/kopia/snapshot"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+)
+
+const (
+	// RestoreVolumeFuncName gives the function name
+	RestoreVolumeFuncName = "RestoreVolume"
+	// RestoreVolumeNamespaceArg is the namespace of the volume
+	RestoreVolumeNamespaceArg = "namespace"
+	// RestoreVolumeVolumeArg is the name of the PVC to restore into
+	RestoreVolumeVolumeArg = "volume"
+	// RestoreVolumeBackupIDArg is the Kopia snapshot ID of the backup
+	RestoreVolumeBackupIDArg = "backupID"
+	// RestoreVolumeKopiaSnapshotArg is the Kopia snapshot info of the backup,
+	// e.g. from the KopiaSnapshot artifact
+	RestoreVolumeKopiaSnapshotArg = "kopiaSnapshot"
+	// RestoreVolumePodOverrideArg contains pod specs to override default pod specs
+	RestoreVolumePodOverrideArg = "podOverride"
+
+	restoreVolumeJobPrefix = "restore-volume-"
+)
+
+func init() {
+	_ = kanister.Register(&restoreVolumeFunc{})
+}
+
+var _ kanister.Func = (*restoreVolumeFunc)(nil)
+
+type restoreVolumeFunc struct {
+	progressPercent string
+}
+
+func (*restoreVolumeFunc) Name() string {
+	return RestoreVolumeFuncName
+}
+
+func (r *restoreVolumeFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	r.progressPercent = progress.StartedPercent
+	defer func() { r.progressPercent = progress.CompletedPercent }()
+
+	var namespace, volume, snapID, userHostname string
+	var err error
+	if err = Arg(args, RestoreVolumeNamespaceArg, &namespace); err != nil {
+		return nil, err
+	}
+	if err = Arg(args, RestoreVolumeVolumeArg, &volume); err != nil {
+		return nil, err
+	}
+	if snapID, err = restoreVolumeSnapshotID(args); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, KopiaRepositoryServerUserHostname, &userHostname, ""); err != nil {
+		return nil, err
+	}
+	podOverride, err := GetPodSpecOverride(tp, args, RestoreVolumePodOverrideArg)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, fingerprint, userAccessPassphrase, err := repositoryServerUserCredentials(tp, userHostname)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+
+	_, sparseRestore := tp.Options[SparseRestoreOption]
+	mountPath := backupVolumeMountPath(volume)
+	return restoreDataFromServer(
+		ctx,
+		cli,
+		hostname,
+		consts.GetKanisterToolsImage(),
+		restoreVolumeJobPrefix,
+		namespace,
+		mountPath,
+		tp.RepositoryServer.Address,
+		fingerprint,
+		snapID,
+		tp.RepositoryServer.Username,
+		userAccessPassphrase,
+		sparseRestore,
+		map[string]string{volume: mountPath},
+		podOverride,
+	)
+}
+
+func (*restoreVolumeFunc) RequiredArgs() []string {
+	return []string{
+		RestoreVolumeNamespaceArg,
+		RestoreVolumeVolumeArg,
+	}
+}
+
+func (*restoreVolumeFunc) Arguments() []string {
+	return []string{
+		RestoreVolumeNamespaceArg,
+		RestoreVolumeVolumeArg,
+		RestoreVolumeBackupIDArg,
+		RestoreVolumeKopiaSnapshotArg,
+		RestoreVolumePodOverrideArg,
+		KopiaRepositoryServerUserHostname,
+	}
+}
+
+func (*restoreVolumeFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: RestoreVolumeNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the PVC"},
+		{Name: RestoreVolumeVolumeArg, Type: kanister.ArgTypeString, Description: "Name of the PVC to restore"},
+		{Name: RestoreVolumeBackupIDArg, Type: kanister.ArgTypeString, Description: "ID of the Kopia snapshot to restore"},
+		{Name: RestoreVolumeKopiaSnapshotArg, Type: kanister.ArgTypeString, Description: "Kopia snapshot info output by BackupVolume"},
+		podOverrideArgSchema(RestoreVolumePodOverrideArg),
+		kopiaUserHostnameArgSchema,
+	}
+}
+
+func (*restoreVolumeFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
+func (r *restoreVolumeFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    r.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
+
+// restoreVolumeSnapshotID returns the snapshot ID from either the backupID
+// or the kopiaSnapshot argument
+func restoreVolumeSnapshotID(args map[string]interface{}) (string, error) {
+	var snapID, kopiaSnapshot string
+	if err := OptArg(args, RestoreVolumeBackupIDArg, &snapID, ""); err != nil {
+		return "", err
+	}
+	if err := OptArg(args, RestoreVolumeKopiaSnapshotArg, &kopiaSnapshot, ""); err != nil {
+		return "", err
+	}
+	if (snapID == "") == (kopiaSnapshot == "") {
+		return "", errors.Errorf("Exactly one of the %s or %s arguments is required", RestoreVolumeBackupIDArg, RestoreVolumeKopiaSnapshotArg)
+	}
+	if snapID != "" {
+		return snapID, nil
+	}
+	info, err := snapshot.UnmarshalKopiaSnapshot(kopiaSnapshot)
+	if err != nil {
+		return "", err
+	}
+	return info.ID, nil
+}
@@ -19,10 +19,12 @@ import (
 	"fmt"
 
 	. "gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	k8sscheme "k8s.io/client-go/kubernetes/scheme"
 
 	kanister "github.com/kanisterio/kanister/pkg"
@@ -314,6 +316,51 @@ func (s *ScaleSuite) TestGetArgs(c *C) {
 			wantWaitForReady: true,
 			check:            IsNil,
 		},
+		{
+			tp: param.TemplateParams{},
+			args: map[string]interface{}{
+				ScaleWorkloadReplicas:      1,
+				ScaleWorkloadNamespaceArg:  "foo",
+				ScaleWorkloadNameArg:       "app",
+				ScaleWorkloadGroupArg:      "argoproj.io",
+				ScaleWorkloadAPIVersionArg: "v1alpha1",
+				ScaleWorkloadResourceArg:   "rollouts",
+			},
+			wantName:         "app",
+			wantNamespace:    "foo",
+			wantReplicas:     int32(1),
+			wantWaitForReady: true,
+			check:            IsNil,
+		},
+		{
+			tp: param.TemplateParams{
+				Deployment: &param.DeploymentParams{
+					Name:      "app",
+					Namespace: "foo",
+				},
+			},
+			args: map[string]interface{}{
+				ScaleWorkloadRestoreOriginalArg: true,
+			},
+			wantKind:         param.DeploymentKind,
+			wantName:         "app",
+			wantNamespace:    "foo",
+			wantWaitForReady: true,
+			check:            IsNil,
+		},
+		{
+			tp: param.TemplateParams{
+				Deployment: &param.DeploymentParams{
+					Name:      "app",
+					Namespace: "foo",
+				},
+			},
+			args: map[string]interface{}{
+				ScaleWorkloadReplicas:           1,
+				ScaleWorkloadRestoreOriginalArg: true,
+			},
+			check: NotNil,
+		},
 	} {
 		namespace, kind, name, replicas, waitForReady, err := getArgs(tc.tp, tc.args)
 		c.Assert(err, tc.check)
@@ -327,3 +374,90 @@ func (s *ScaleSuite) TestGetArgs(c *C) {
 		c.Assert(waitForReady, Equals, tc.wantWaitForReady)
 	}
 }
+
+func (s *ScaleSuite) TestOriginalReplicas(c *C) {
+	gvr, err := scaleWorkloadGVR(param.DeploymentKind, map[string]interface{}{})
+	c.Assert(err, IsNil)
+	app := scaleWorkloadID(gvr, "foo", "app")
+	c.Assert(app, Equals, "deployments.apps/foo/app")
+
+	gvr, err = scaleWorkloadGVR("", map[string]interface{}{
+		ScaleWorkloadGroupArg:      "argoproj.io",
+		ScaleWorkloadAPIVersionArg: "v1alpha1",
+		ScaleWorkloadResourceArg:   "rollouts",
+	})
+	c.Assert(err, IsNil)
+	c.Assert(scaleWorkloadID(gvr, "foo", "app"), Equals, "rollouts.argoproj.io/foo/app")
+
+	_, err = scaleWorkloadGVR("daemonset", map[string]interface{}{})
+	c.Assert(err, NotNil)
+
+	tp := param.TemplateParams{
+		Phases: map[string]*param.Phase{
+			"scaleDown": {Output: map[string]interface{}{
+				ScaleWorkloadOriginalReplicasOutput: int32(3),
+				ScaleWorkloadWorkloadOutput:         app,
+			}},
+			"other": {Output: map[string]interface{}{
+				ScaleWorkloadOriginalReplicasOutput: int32(1),
+				ScaleWorkloadWorkloadOutput:         "deployments.apps/foo/other",
+			}},
+			"noOutput": {},
+		},
+	}
+	replicas, err := originalReplicas(tp, app)
+	c.Assert(err, IsNil)
+	c.Assert(replicas, Equals, int32(3))
+
+	// Outputs read back from the ActionSet status are float64
+	tp.Phases["scaleDownAgain"] = &param.Phase{Output: map[string]interface{}{
+		ScaleWorkloadOriginalReplicasOutput: float64(3),
+		ScaleWorkloadWorkloadOutput:         app,
+	}}
+	replicas, err = originalReplicas(tp, app)
+	c.Assert(err, IsNil)
+	c.Assert(replicas, Equals, int32(3))
+
+	tp.Phases["scaleDownAgain"].Output[ScaleWorkloadOriginalReplicasOutput] = 0
+	_, err = originalReplicas(tp, app)
+	c.Assert(err, NotNil)
+
+	_, err = originalReplicas(tp, "deployments.apps/foo/missing")
+	c.Assert(err, NotNil)
+}
+
+func (s *ScaleSuite) TestScaleWorkloadPVCs(c *C) {
+	ctx := context.Background()
+	ss := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "foo", UID: "ss-uid"},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}},
+		},
+	}
+	pod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "foo",
+			OwnerReferences: []metav1.OwnerReference{{UID: "ss-uid"}},
+		}}
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "foo"},
+		Spec: appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Volumes: []v1.Volume{
+			{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{}}},
+			{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "app-data"}}},
+		}}}},
+	}
+	cli := k8sfake.NewSimpleClientset(ss, pod("db-0"), pod("db-1"), d)
+
+	pvcs, err := scaleWorkloadPVCs(ctx, cli, nil, param.StatefulSetKind, "foo", "db")
+	c.Assert(err, IsNil)
+	c.Assert(pvcs, DeepEquals, []string{"data-db-0", "data-db-1"})
+
+	pvcs, err = scaleWorkloadPVCs(ctx, cli, nil, param.DeploymentKind, "foo", "app")
+	c.Assert(err, IsNil)
+	c.Assert(pvcs, DeepEquals, []string{"app-data"})
+
+	_, err = scaleWorkloadPVCs(ctx, cli, nil, param.DeploymentKind, "foo", "missing")
+	c.Assert(err, NotNil)
+}
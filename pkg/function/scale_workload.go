@@ -16,6 +16,8 @@ package function
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -23,11 +25,14 @@ import (
 	osversioned "github.com/openshift/client-go/apps/clientset/versioned"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/kube/volume"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/progress"
 )
@@ -40,6 +45,22 @@ const (
 	ScaleWorkloadKindArg      = "kind"
 	ScaleWorkloadReplicas     = "replicas"
 	ScaleWorkloadWaitArg      = "waitForReady"
+	// ScaleWorkloadWaitForDetachArg waits until the PVCs of the workload are
+	// detached from the nodes after scaling to zero
+	ScaleWorkloadWaitForDetachArg = "waitForDetach"
+	// ScaleWorkloadGroupArg, ScaleWorkloadAPIVersionArg and ScaleWorkloadResourceArg
+	// identify any other resource exposing the scale subresource
+	ScaleWorkloadGroupArg      = "group"
+	ScaleWorkloadAPIVersionArg = "apiVersion"
+	ScaleWorkloadResourceArg   = "resource"
+	// ScaleWorkloadRestoreOriginalArg scales the workload back to the replica
+	// count recorded by a previous ScaleWorkload phase
+	ScaleWorkloadRestoreOriginalArg = "restoreOriginal"
+
+	// ScaleWorkloadOriginalReplicasOutput is the replica count before scaling
+	ScaleWorkloadOriginalReplicasOutput = "originalReplicas"
+	// ScaleWorkloadWorkloadOutput identifies the scaled workload
+	ScaleWorkloadWorkloadOutput = "workload"
 )
 
 func init() {
@@ -69,6 +90,29 @@ func (s *scaleWorkloadFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err != nil {
 		return nil, err
 	}
+	var restoreOriginal, waitForDetach bool
+	if err = OptArg(args, ScaleWorkloadRestoreOriginalArg, &restoreOriginal, false); err != nil {
+		return nil, err
+	}
+	if err = OptArg(args, ScaleWorkloadWaitForDetachArg, &waitForDetach, false); err != nil {
+		return nil, err
+	}
+	if waitForDetach && (restoreOriginal || replicas != 0) {
+		return nil, errors.Errorf("Argument %s requires scaling to zero replicas", ScaleWorkloadWaitForDetachArg)
+	}
+	if waitForDetach && ArgExists(args, ScaleWorkloadResourceArg) {
+		return nil, errors.Errorf("Argument %s is not supported with %s", ScaleWorkloadWaitForDetachArg, ScaleWorkloadResourceArg)
+	}
+	gvr, err := scaleWorkloadGVR(kind, args)
+	if err != nil {
+		return nil, err
+	}
+	workload := scaleWorkloadID(gvr, namespace, name)
+	if restoreOriginal {
+		if replicas, err = originalReplicas(tp, workload); err != nil {
+			return nil, err
+		}
+	}
 
 	cfg, err := kube.LoadConfig()
 	if err != nil {
@@ -78,23 +122,58 @@ func (s *scaleWorkloadFunc) Exec(ctx context.Context, tp param.TemplateParams, a
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed to create Kubernetes client")
 	}
-	switch strings.ToLower(kind) {
-	case param.StatefulSetKind:
-		return nil, kube.ScaleStatefulSet(ctx, cli, namespace, name, replicas, waitForReady)
-	case param.DeploymentKind:
-		return nil, kube.ScaleDeployment(ctx, cli, namespace, name, replicas, waitForReady)
-	case param.DeploymentConfigKind:
-		osCli, err := osversioned.NewForConfig(cfg)
-		if err != nil {
+	dynCli, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create dynamic Kubernetes client")
+	}
+	var osCli osversioned.Interface
+	if kind == param.DeploymentConfigKind && !ArgExists(args, ScaleWorkloadResourceArg) {
+		if osCli, err = osversioned.NewForConfig(cfg); err != nil {
 			return nil, errors.Wrapf(err, "Failed to create OpenShift client")
 		}
-		return nil, kube.ScaleDeploymentConfig(ctx, cli, osCli, namespace, name, replicas, waitForReady)
 	}
-	return nil, errors.New("Workload type not supported " + kind)
+	original, err := kube.ScaleReplicas(ctx, dynCli, gvr, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	// The PVCs of a StatefulSet are known from its pods, which are gone afterwards
+	var pvcs []string
+	if waitForDetach {
+		if pvcs, err = scaleWorkloadPVCs(ctx, cli, osCli, kind, namespace, name); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case ArgExists(args, ScaleWorkloadResourceArg):
+		err = kube.ScaleResource(ctx, dynCli, gvr, namespace, name, replicas, waitForReady)
+	case kind == param.StatefulSetKind:
+		err = kube.ScaleStatefulSet(ctx, cli, namespace, name, replicas, waitForReady)
+	case kind == param.DeploymentKind:
+		err = kube.ScaleDeployment(ctx, cli, namespace, name, replicas, waitForReady)
+	default:
+		err = kube.ScaleDeploymentConfig(ctx, cli, osCli, namespace, name, replicas, waitForReady)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if waitForDetach {
+		if err = volume.WaitOnPVCsDetached(ctx, cli, namespace, pvcs); err != nil {
+			return nil, err
+		}
+	}
+	// The original replica count of a restore is not recorded, so that it
+	// does not shadow the one of the scale down
+	if restoreOriginal {
+		return nil, nil
+	}
+	return map[string]interface{}{
+		ScaleWorkloadOriginalReplicasOutput: original,
+		ScaleWorkloadWorkloadOutput:         workload,
+	}, nil
 }
 
 func (*scaleWorkloadFunc) RequiredArgs() []string {
-	return []string{ScaleWorkloadReplicas}
+	return []string{}
 }
 
 func (*scaleWorkloadFunc) Arguments() []string {
@@ -104,6 +183,33 @@ func (*scaleWorkloadFunc) Arguments() []string {
 		ScaleWorkloadNameArg,
 		ScaleWorkloadKindArg,
 		ScaleWorkloadWaitArg,
+		ScaleWorkloadWaitForDetachArg,
+		ScaleWorkloadGroupArg,
+		ScaleWorkloadAPIVersionArg,
+		ScaleWorkloadResourceArg,
+		ScaleWorkloadRestoreOriginalArg,
+	}
+}
+
+func (*scaleWorkloadFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: ScaleWorkloadReplicas, Type: kanister.ArgTypeInteger, Description: "Replicas to scale the workload to"},
+		{Name: ScaleWorkloadNamespaceArg, Type: kanister.ArgTypeString, Description: "Namespace of the workload, defaults to the namespace of the object of the ActionSet"},
+		{Name: ScaleWorkloadNameArg, Type: kanister.ArgTypeString, Description: "Name of the workload, defaults to the name of the object of the ActionSet"},
+		{Name: ScaleWorkloadKindArg, Type: kanister.ArgTypeString, Description: "Kind of the workload, e.g. deployment"},
+		{Name: ScaleWorkloadWaitArg, Type: kanister.ArgTypeBoolean, Default: true, Description: "Waits for the workload to reach the replicas"},
+		{Name: ScaleWorkloadWaitForDetachArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Waits for the PVCs to be detached after scaling to zero"},
+		{Name: ScaleWorkloadGroupArg, Type: kanister.ArgTypeString, Description: "API group of the resource to scale"},
+		{Name: ScaleWorkloadAPIVersionArg, Type: kanister.ArgTypeString, Description: "API version of the resource to scale"},
+		{Name: ScaleWorkloadResourceArg, Type: kanister.ArgTypeString, Description: "Resource exposing the scale subresource, instead of kind"},
+		{Name: ScaleWorkloadRestoreOriginalArg, Type: kanister.ArgTypeBoolean, Default: false, Description: "Scales the workload back to the replicas before it was scaled in a previous phase"},
+	}
+}
+
+func (*scaleWorkloadFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: ScaleWorkloadOriginalReplicasOutput, Type: kanister.OutputTypeInteger, Description: "Replicas of the workload before it was scaled"},
+		{Name: ScaleWorkloadWorkloadOutput, Type: kanister.OutputTypeString, Description: "Workload that was scaled"},
 	}
 }
 
@@ -117,29 +223,23 @@ func (s *scaleWorkloadFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error
 
 func getArgs(tp param.TemplateParams, args map[string]interface{}) (namespace, kind, name string, replicas int32, waitForReady bool, err error) {
 	var rep interface{}
+	var restoreOriginal bool
 	waitForReady = true
-	err = Arg(args, ScaleWorkloadReplicas, &rep)
-	if err != nil {
+	if err = OptArg(args, ScaleWorkloadRestoreOriginalArg, &restoreOriginal, false); err != nil {
 		return
 	}
-
-	switch val := rep.(type) {
-	case int:
-		replicas = int32(val)
-	case int32:
-		replicas = val
-	case int64:
-		replicas = int32(val)
-	case string:
-		var v int
-		if v, err = strconv.Atoi(val); err != nil {
-			err = errors.Wrapf(err, "Cannot convert %s to int ", val)
+	// The replica count is looked up later when restoring the original one
+	if !restoreOriginal || ArgExists(args, ScaleWorkloadReplicas) {
+		if restoreOriginal {
+			err = errors.Errorf("Arguments %s and %s are mutually exclusive", ScaleWorkloadReplicas, ScaleWorkloadRestoreOriginalArg)
+			return
+		}
+		if err = Arg(args, ScaleWorkloadReplicas, &rep); err != nil {
+			return
+		}
+		if replicas, err = toReplicas(rep); err != nil {
 			return
 		}
-		replicas = int32(v)
-	default:
-		err = errors.Errorf("Invalid arg type %T for Arg %s ", rep, ScaleWorkloadReplicas)
-		return
 	}
 	// Populate default values for optional arguments from template parameters
 	switch {
@@ -156,7 +256,8 @@ func getArgs(tp param.TemplateParams, args map[string]interface{}) (namespace, k
 		name = tp.DeploymentConfig.Name
 		namespace = tp.DeploymentConfig.Namespace
 	default:
-		if !ArgExists(args, ScaleWorkloadNamespaceArg) || !ArgExists(args, ScaleWorkloadNameArg) || !ArgExists(args, ScaleWorkloadKindArg) {
+		hasKind := ArgExists(args, ScaleWorkloadKindArg) || ArgExists(args, ScaleWorkloadResourceArg)
+		if !ArgExists(args, ScaleWorkloadNamespaceArg) || !ArgExists(args, ScaleWorkloadNameArg) || !hasKind {
 			return namespace, kind, name, replicas, waitForReady, errors.New("Workload information not available via defaults or namespace/name/kind parameters")
 		}
 	}
@@ -177,5 +278,132 @@ func getArgs(tp param.TemplateParams, args map[string]interface{}) (namespace, k
 	if err != nil {
 		return
 	}
+	kind = strings.ToLower(kind)
 	return
 }
+
+func toReplicas(rep interface{}) (int32, error) {
+	switch val := rep.(type) {
+	case int:
+		return int32(val), nil
+	case int32:
+		return val, nil
+	case int64:
+		return int32(val), nil
+	case float64:
+		// Numbers read back from the ActionSet status
+		return int32(val), nil
+	case string:
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return 0, errors.Wrapf(err, "Cannot convert %s to int ", val)
+		}
+		return int32(v), nil
+	default:
+		return 0, errors.Errorf("Invalid arg type %T for Arg %s ", rep, ScaleWorkloadReplicas)
+	}
+}
+
+// scaleWorkloadGVR returns the resource of the StatefulSet, Deployment or
+// DeploymentConfig kind, or of the resource given by the arguments.
+func scaleWorkloadGVR(kind string, args map[string]interface{}) (schema.GroupVersionResource, error) {
+	if ArgExists(args, ScaleWorkloadResourceArg) {
+		var gvr schema.GroupVersionResource
+		if err := Arg(args, ScaleWorkloadResourceArg, &gvr.Resource); err != nil {
+			return gvr, err
+		}
+		if err := Arg(args, ScaleWorkloadAPIVersionArg, &gvr.Version); err != nil {
+			return gvr, err
+		}
+		if err := OptArg(args, ScaleWorkloadGroupArg, &gvr.Group, ""); err != nil {
+			return gvr, err
+		}
+		return gvr, nil
+	}
+	switch kind {
+	case param.StatefulSetKind:
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}, nil
+	case param.DeploymentKind:
+		return schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, nil
+	case param.DeploymentConfigKind:
+		return schema.GroupVersionResource{Group: "apps.openshift.io", Version: "v1", Resource: "deploymentconfigs"}, nil
+	}
+	return schema.GroupVersionResource{}, errors.New("Workload type not supported " + kind)
+}
+
+// scaleWorkloadID identifies a workload in the phase outputs
+func scaleWorkloadID(gvr schema.GroupVersionResource, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", gvr.GroupResource(), namespace, name)
+}
+
+// originalReplicas returns the replica count recorded by the phases that
+// scaled the workload.
+func originalReplicas(tp param.TemplateParams, workload string) (int32, error) {
+	var found []int32
+	for _, p := range tp.Phases {
+		if p == nil || p.Output[ScaleWorkloadWorkloadOutput] != workload {
+			continue
+		}
+		replicas, err := toReplicas(p.Output[ScaleWorkloadOriginalReplicasOutput])
+		if err != nil {
+			return 0, err
+		}
+		found = append(found, replicas)
+	}
+	switch {
+	case len(found) == 0:
+		return 0, errors.Errorf("No phase recorded the original replica count of %s", workload)
+	case len(found) > 1:
+		// The phases run in order, but their outputs are not ordered
+		for _, r := range found[1:] {
+			if r != found[0] {
+				return 0, errors.Errorf("Phases recorded different original replica counts of %s: %v", workload, found)
+			}
+		}
+	}
+	return found[0], nil
+}
+
+// scaleWorkloadPVCs returns the PVCs used by the pods of the workload
+func scaleWorkloadPVCs(ctx context.Context, cli kubernetes.Interface, osCli osversioned.Interface, kind, namespace, name string) ([]string, error) {
+	var volNameToPvc []map[string]string
+	switch kind {
+	case param.StatefulSetKind:
+		ss, err := cli.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get StatefulSet %s", name)
+		}
+		runningPods, notRunningPods, err := kube.StatefulSetPods(ctx, cli, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range append(runningPods, notRunningPods...) {
+			pod := pod
+			volNameToPvc = append(volNameToPvc, kube.StatefulSetVolumes(cli, ss, &pod))
+		}
+	case param.DeploymentKind:
+		d, err := cli.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get Deployment %s", name)
+		}
+		volNameToPvc = append(volNameToPvc, kube.DeploymentVolumes(cli, d))
+	default:
+		dc, err := osCli.AppsV1().DeploymentConfigs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get DeploymentConfig %s", name)
+		}
+		volNameToPvc = append(volNameToPvc, kube.DeploymentConfigVolumes(osCli, dc, nil))
+	}
+	var pvcs []string
+	seen := make(map[string]bool)
+	for _, m := range volNameToPvc {
+		for _, pvc := range m {
+			if !seen[pvc] {
+				seen[pvc] = true
+				pvcs = append(pvcs, pvc)
+			}
+		}
+	}
+	sort.Strings(pvcs)
+	return pvcs, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/progress"
+	"github.com/kanisterio/kanister/pkg/quiesce"
+)
+
+const (
+	// UnquiesceFuncName gives the function name
+	UnquiesceFuncName = "Unquiesce"
+	// UnquiesceQuiesceArg is the quiesce output of the Quiesce phase
+	UnquiesceQuiesceArg = "quiesce"
+)
+
+func init() {
+	_ = kanister.Register(&unquiesceFunc{})
+}
+
+var _ kanister.Func = (*unquiesceFunc)(nil)
+
+type unquiesceFunc struct {
+	progressPercent string
+}
+
+func (*unquiesceFunc) Name() string {
+	return UnquiesceFuncName
+}
+
+func (u *unquiesceFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	// Set progress percent
+	u.progressPercent = progress.StartedPercent
+	defer func() { u.progressPercent = progress.CompletedPercent }()
+
+	var recordStr string
+	if err := Arg(args, UnquiesceQuiesceArg, &recordStr); err != nil {
+		return nil, err
+	}
+	record, err := quiesce.Unmarshal(recordStr)
+	if err != nil {
+		return nil, err
+	}
+	// The controller unquiesces the application once the lease expires
+	if record.ExpiresAt.Time.Before(time.Now()) {
+		log.WithContext(ctx).Print("Quiesce lease expired, application already unquiesced", field.M{"ID": record.ID})
+		return map[string]interface{}{quiesce.OutputUnquiesced: record.ID}, nil
+	}
+	// The controller serializes the unquiesces, so that a quiesce undone
+	// once the lease expired is not undone again
+	if t := quiesce.TrackerFromContext(ctx); t != nil {
+		if err = t.Unquiesce(ctx, record.ID); err != nil {
+			return nil, errors.Wrapf(err, "Failed to unquiesce the application with handler %s", record.Handler)
+		}
+		return map[string]interface{}{quiesce.OutputUnquiesced: record.ID}, nil
+	}
+	cli, err := kube.NewClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create Kubernetes client")
+	}
+	if err = quiesce.Unquiesce(ctx, cli, record); err != nil {
+		return nil, errors.Wrapf(err, "Failed to unquiesce the application with handler %s", record.Handler)
+	}
+	// The controller removes the record from the ActionSet status
+	return map[string]interface{}{quiesce.OutputUnquiesced: record.ID}, nil
+}
+
+func (*unquiesceFunc) RequiredArgs() []string {
+	return []string{UnquiesceQuiesceArg}
+}
+
+func (*unquiesceFunc) Arguments() []string {
+	return []string{UnquiesceQuiesceArg}
+}
+
+func (*unquiesceFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: UnquiesceQuiesceArg, Type: kanister.ArgTypeString, Description: "Output of Quiesce"},
+	}
+}
+
+func (*unquiesceFunc) Outputs() []kanister.Output {
+	return []kanister.Output{
+		{Name: quiesce.OutputUnquiesced, Type: kanister.OutputTypeString, Description: "ID of the unquiesced quiesce record"},
+	}
+}
+
+func (u *unquiesceFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	metav1Time := metav1.NewTime(time.Now())
+	return crv1alpha1.PhaseProgress{
+		ProgressPercent:    u.progressPercent,
+		LastTransitionTime: &metav1Time,
+	}, nil
+}
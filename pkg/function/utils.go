@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/aws"
 	"github.com/kanisterio/kanister/pkg/aws/rds"
@@ -27,8 +28,83 @@ import (
 const (
 	// FunctionOutputVersion returns version
 	FunctionOutputVersion = "version"
+	// JobBackoffLimitArg and JobActiveDeadlineSecondsArg run the pod of a
+	// function through a Job, which replaces the pod if it fails or its node
+	// is drained
+	JobBackoffLimitArg          = "backoffLimit"
+	JobActiveDeadlineSecondsArg = "activeDeadlineSeconds"
 )
 
+var (
+	// versionOutput is the FunctionOutputVersion output of the functions
+	versionOutput = kanister.Output{Name: FunctionOutputVersion, Type: kanister.OutputTypeString, Description: "Version of the output format"}
+	// commandOutput is declared by the functions whose outputs are set by
+	// their commands with `kando output`
+	commandOutput = kanister.Output{Name: kanister.AnyOutput, Description: "Outputs set by the commands with kando output"}
+	// jobArgSchemas are the schemas of the args read by GetJobOptions
+	jobArgSchemas = []kanister.ArgSchema{
+		{Name: JobBackoffLimitArg, Type: kanister.ArgTypeInteger, Description: "Runs the pod through a Job retrying it this many times"},
+		{Name: JobActiveDeadlineSecondsArg, Type: kanister.ArgTypeInteger, Description: "Runs the pod through a Job failing after this many seconds"},
+	}
+	// kopiaUserHostnameArgSchema is the schema of the
+	// KopiaRepositoryServerUserHostname arg of the Kopia server functions
+	kopiaUserHostnameArgSchema = kanister.ArgSchema{Name: KopiaRepositoryServerUserHostname, Type: kanister.ArgTypeString, Description: "Hostname of the Kopia repository server user"}
+)
+
+// encryptionKeyArgSchema returns the schema of the restic encryption key arg
+// called name
+func encryptionKeyArgSchema(name string) kanister.ArgSchema {
+	return kanister.ArgSchema{Name: name, Type: kanister.ArgTypeString, Description: "Restic encryption key, defaults to a fixed password generated by Kanister"}
+}
+
+// artifactPrefixArgSchema returns the schema of the restic repository path
+// arg called name
+func artifactPrefixArgSchema(name string) kanister.ArgSchema {
+	return kanister.ArgSchema{Name: name, Type: kanister.ArgTypeString, Description: "Path of the restic repository in the Profile"}
+}
+
+// podOverrideArgSchema returns the schema of the pod spec overrides arg
+// called name
+func podOverrideArgSchema(name string) kanister.ArgSchema {
+	return kanister.ArgSchema{Name: name, Type: kanister.ArgTypeObject, Description: "Overrides of the pod spec"}
+}
+
+// podNamespaceArgSchema returns the schema of the pod namespace arg called
+// name
+func podNamespaceArgSchema(name string) kanister.ArgSchema {
+	return kanister.ArgSchema{Name: name, Type: kanister.ArgTypeString, Description: "Namespace of the pod"}
+}
+
+// GetJobOptions returns the options to run a pod through a Job, or nil if
+// neither JobBackoffLimitArg nor JobActiveDeadlineSecondsArg are set
+func GetJobOptions(args map[string]interface{}) (*kube.JobOptions, error) {
+	if !ArgExists(args, JobBackoffLimitArg) && !ArgExists(args, JobActiveDeadlineSecondsArg) {
+		return nil, nil
+	}
+	opts := &kube.JobOptions{}
+	if ArgExists(args, JobBackoffLimitArg) {
+		var backoffLimit int32
+		if err := Arg(args, JobBackoffLimitArg, &backoffLimit); err != nil {
+			return nil, err
+		}
+		if backoffLimit < 0 {
+			return nil, errors.Errorf("Argument %s must not be negative", JobBackoffLimitArg)
+		}
+		opts.BackoffLimit = &backoffLimit
+	}
+	if ArgExists(args, JobActiveDeadlineSecondsArg) {
+		var deadline int64
+		if err := Arg(args, JobActiveDeadlineSecondsArg, &deadline); err != nil {
+			return nil, err
+		}
+		if deadline <= 0 {
+			return nil, errors.Errorf("Argument %s must be positive", JobActiveDeadlineSecondsArg)
+		}
+		opts.ActiveDeadlineSeconds = &deadline
+	}
+	return opts, nil
+}
+
 // ValidateCredentials verifies if the given credentials have appropriate values set
 func ValidateCredentials(creds *param.Credential) error {
 	if creds == nil {
@@ -15,10 +15,15 @@
 package function
 
 import (
+	"reflect"
+
 	. "gopkg.in/check.v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/utils/strings/slices"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/secrets"
 )
@@ -253,3 +258,105 @@ func newValidStatefulSetTP() param.TemplateParams {
 		},
 	}
 }
+
+func (s *UtilsTestSuite) TestGetJobOptions(c *C) {
+	backoffLimit := int32(3)
+	deadline := int64(600)
+	for _, tc := range []struct {
+		args       map[string]interface{}
+		opts       *kube.JobOptions
+		errChecker Checker
+	}{
+		{
+			args:       map[string]interface{}{},
+			errChecker: IsNil,
+		},
+		{
+			args:       map[string]interface{}{JobBackoffLimitArg: 3},
+			opts:       &kube.JobOptions{BackoffLimit: &backoffLimit},
+			errChecker: IsNil,
+		},
+		{
+			args:       map[string]interface{}{JobBackoffLimitArg: "3", JobActiveDeadlineSecondsArg: 600},
+			opts:       &kube.JobOptions{BackoffLimit: &backoffLimit, ActiveDeadlineSeconds: &deadline},
+			errChecker: IsNil,
+		},
+		{
+			args:       map[string]interface{}{JobBackoffLimitArg: -1},
+			errChecker: NotNil,
+		},
+		{
+			args:       map[string]interface{}{JobActiveDeadlineSecondsArg: 0},
+			errChecker: NotNil,
+		},
+		{
+			args:       map[string]interface{}{JobActiveDeadlineSecondsArg: "soon"},
+			errChecker: NotNil,
+		},
+	} {
+		opts, err := GetJobOptions(tc.args)
+		c.Assert(err, tc.errChecker)
+		c.Assert(opts, DeepEquals, tc.opts)
+	}
+}
+
+func (s *UtilsTestSuite) TestJobArguments(c *C) {
+	// Only the functions whose command is the entrypoint of the pod can run
+	// it through a Job, since the Job does not re-run the commands executed
+	// in a replaced pod
+	for name, job := range map[string]bool{
+		KubeTaskFuncName:              true,
+		PrepareDataFuncName:           true,
+		CopyVolumeDataFuncName:        false,
+		RestoreDataFuncName:           false,
+		RestoreDataAllFuncName:        false,
+		DeleteDataFuncName:            false,
+		DeleteDataAllFuncName:         false,
+		BackupDataStatsFuncName:       false,
+		CheckRepositoryFuncName:       false,
+		BackupCSISnapshotDataFuncName: false,
+		BackupVolumeFuncName:          false,
+	} {
+		f := kanister.KanisterFuncForName(name, kanister.DefaultVersion)
+		c.Assert(f, NotNil, Commentf("%s", name))
+		c.Assert(slices.Contains(f.Arguments(), JobBackoffLimitArg), Equals, job, Commentf("%s", name))
+		c.Assert(slices.Contains(f.Arguments(), JobActiveDeadlineSecondsArg), Equals, job, Commentf("%s", name))
+	}
+}
+
+func (s *UtilsTestSuite) TestOutputs(c *C) {
+	for name := range kanister.RegisteredFunctions() {
+		f := kanister.KanisterFuncForName(name, kanister.DefaultVersion)
+		if f == nil || reflect.TypeOf(f).Elem().PkgPath() != reflect.TypeOf(s).Elem().PkgPath() {
+			// Not a built-in function, e.g. registered by testutil
+			continue
+		}
+		d, ok := f.(kanister.OutputsDescriber)
+		c.Assert(ok, Equals, true, Commentf("%s does not declare its outputs", name))
+		for _, o := range d.Outputs() {
+			c.Assert(o.Name, Not(Equals), "", Commentf("%s declares an output without name", name))
+			c.Assert(o.Description, Not(Equals), "", Commentf("%s does not describe output %s", name, o.Name))
+		}
+	}
+}
+
+func (s *UtilsTestSuite) TestArgSchemas(c *C) {
+	for name := range kanister.RegisteredFunctions() {
+		f := kanister.KanisterFuncForName(name, kanister.DefaultVersion)
+		if f == nil || reflect.TypeOf(f).Elem().PkgPath() != reflect.TypeOf(s).Elem().PkgPath() {
+			continue
+		}
+		d, ok := f.(kanister.ArgsDescriber)
+		c.Assert(ok, Equals, true, Commentf("%s does not declare its argument schema", name))
+		var names []string
+		for _, a := range d.ArgSchemas() {
+			c.Assert(a.Type, Not(Equals), "", Commentf("%s does not declare the type of %s", name, a.Name))
+			c.Assert(a.Description, Not(Equals), "", Commentf("%s does not describe %s", name, a.Name))
+			names = append(names, a.Name)
+		}
+		c.Assert(names, HasLen, len(f.Arguments()), Commentf("%s", name))
+		for _, a := range f.Arguments() {
+			c.Assert(slices.Contains(names, a), Equals, true, Commentf("%s does not declare the schema of %s", name, a))
+		}
+	}
+}
@@ -61,6 +61,16 @@ func (*waitForSnapshotCompletionFunc) Arguments() []string {
 	return []string{WaitForSnapshotCompletionSnapshotsArg}
 }
 
+func (*waitForSnapshotCompletionFunc) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: WaitForSnapshotCompletionSnapshotsArg, Type: kanister.ArgTypeString, Description: "Output of CreateVolumeSnapshot"},
+	}
+}
+
+func (*waitForSnapshotCompletionFunc) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (w *waitForSnapshotCompletionFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
 	// Set progress percent
 	w.progressPercent = progress.StartedPercent
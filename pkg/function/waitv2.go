@@ -97,6 +97,17 @@ func (*waitV2Func) Arguments() []string {
 	}
 }
 
+func (*waitV2Func) ArgSchemas() []kanister.ArgSchema {
+	return []kanister.ArgSchema{
+		{Name: WaitV2TimeoutArg, Type: kanister.ArgTypeString, Description: "Duration to wait for, e.g. 10m"},
+		{Name: WaitV2ConditionsArg, Type: kanister.ArgTypeObject, Description: "Conditions to wait for, with anyOf or allOf"},
+	}
+}
+
+func (*waitV2Func) Outputs() []kanister.Output {
+	return []kanister.Output{}
+}
+
 func (w *waitV2Func) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
 	metav1Time := metav1.NewTime(time.Now())
 	return crv1alpha1.PhaseProgress{
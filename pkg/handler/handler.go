@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/validatingwebhook"
 	"github.com/kanisterio/kanister/pkg/version"
 	"github.com/pkg/errors"
@@ -35,6 +36,7 @@ const (
 	metricsPath     = "/metrics"
 	healthCheckAddr = ":8000"
 	whHandlePath    = "/validate/v1alpha1/blueprint"
+	revWHHandlePath = "/validate/v1alpha1/blueprintrevision"
 )
 
 // Info provides information about kanister controller
@@ -67,8 +69,14 @@ func RunWebhookServer(c *rest.Config) error {
 		return errors.Wrapf(err, "Failed to create new webhook manager")
 	}
 
+	crCli, err := versioned.NewForConfig(c)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create the CR client")
+	}
+
 	hookServer := mgr.GetWebhookServer()
-	hookServer.Register(whHandlePath, &webhook.Admission{Handler: &validatingwebhook.BlueprintValidator{}})
+	hookServer.Register(whHandlePath, &webhook.Admission{Handler: &validatingwebhook.BlueprintValidator{CrClient: crCli}})
+	hookServer.Register(revWHHandlePath, &webhook.Admission{Handler: &validatingwebhook.BlueprintRevisionValidator{}})
 	hookServer.Register(healthCheckPath, &healthCheckHandler{})
 	hookServer.Register(metricsPath, promhttp.Handler())
 
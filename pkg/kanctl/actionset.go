@@ -15,11 +15,13 @@
 package kanctl
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"regexp"
 	"strings"
 	"sync"
+	"text/tabwriter"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -31,7 +33,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/yaml"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blueprint"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
@@ -43,6 +47,7 @@ const (
 	actionFlagName                       = "action"
 	actionSetFlagName                    = "name"
 	blueprintFlagName                    = "blueprint"
+	blueprintRevisionFlagName            = "blueprint-revision"
 	configMapsFlagName                   = "config-maps"
 	deploymentFlagName                   = "deployment"
 	optionsFlagName                      = "options"
@@ -68,19 +73,21 @@ var (
 )
 
 type PerformParams struct {
-	Namespace        string
-	ActionName       string
-	ActionSetName    string
-	ParentName       string
-	Blueprint        string
-	DryRun           bool
-	Objects          []crv1alpha1.ObjectReference
-	Options          map[string]string
-	Profile          *crv1alpha1.ObjectReference
-	RepositoryServer *crv1alpha1.ObjectReference
-	Secrets          map[string]crv1alpha1.ObjectReference
-	ConfigMaps       map[string]crv1alpha1.ObjectReference
-	Labels           map[string]string
+	Namespace         string
+	ActionName        string
+	ActionSetName     string
+	ParentName        string
+	Blueprint         string
+	BlueprintRevision string
+	DryRun            bool
+	Plan              bool
+	Objects           []crv1alpha1.ObjectReference
+	Options           map[string]string
+	Profile           *crv1alpha1.ObjectReference
+	RepositoryServer  *crv1alpha1.ObjectReference
+	Secrets           map[string]crv1alpha1.ObjectReference
+	ConfigMaps        map[string]crv1alpha1.ObjectReference
+	Labels            map[string]string
 }
 
 func newActionSetCmd() *cobra.Command {
@@ -97,6 +104,7 @@ func newActionSetCmd() *cobra.Command {
 	cmd.Flags().StringP(actionFlagName, "a", "", "action for the action set (required if creating a new action set)")
 	cmd.Flags().StringP(actionSetFlagName, "A", "", "name of the new actionset (optional. if not specified, kanctl will generate one based on the action name")
 	cmd.Flags().StringP(blueprintFlagName, "b", "", "blueprint for the action set (required if creating a new action set)")
+	cmd.Flags().String(blueprintRevisionFlagName, "", "revision of the blueprint the actions are pinned to. Actions created with --from are pinned to the revision of their parent unless --blueprint is set")
 	cmd.Flags().StringSliceP(configMapsFlagName, "c", []string{}, "config maps for the action set, comma separated ref=namespace/name pairs (eg: --config-maps ref1=namespace1/name1,ref2=namespace2/name2)")
 	cmd.Flags().StringSliceP(deploymentFlagName, "d", []string{}, "deployment for the action set, comma separated namespace/name pairs (eg: --deployment namespace1/name1,namespace2/name2)")
 	cmd.Flags().StringSliceP(optionsFlagName, "o", []string{}, "specify options for the action set, comma separated key=value pairs (eg: --options key1=value1,key2=value2)")
@@ -153,6 +161,9 @@ func perform(ctx context.Context, crCli versioned.Interface, params *PerformPara
 			return err
 		}
 		as, err = ChildActionSet(pas, params)
+		if err == nil {
+			err = dropUndeclaredOptions(ctx, crCli, params.Namespace, as, params.Options)
+		}
 	case len(params.Objects) > 0:
 		as, err = newActionSet(params)
 	default:
@@ -161,6 +172,9 @@ func perform(ctx context.Context, crCli versioned.Interface, params *PerformPara
 	if err != nil {
 		return err
 	}
+	if params.Plan {
+		return printActionSetPlan(ctx, crCli, params.Namespace, as)
+	}
 	if params.DryRun {
 		return printActionSet(as)
 	}
@@ -177,14 +191,15 @@ func newActionSet(params *PerformParams) (*crv1alpha1.ActionSet, error) {
 	actions := make([]crv1alpha1.ActionSpec, 0, len(params.Objects))
 	for _, obj := range params.Objects {
 		actions = append(actions, crv1alpha1.ActionSpec{
-			Name:             params.ActionName,
-			Blueprint:        params.Blueprint,
-			Object:           obj,
-			Secrets:          params.Secrets,
-			ConfigMaps:       params.ConfigMaps,
-			Profile:          params.Profile,
-			RepositoryServer: params.RepositoryServer,
-			Options:          params.Options,
+			Name:              params.ActionName,
+			Blueprint:         params.Blueprint,
+			BlueprintRevision: params.BlueprintRevision,
+			Object:            obj,
+			Secrets:           params.Secrets,
+			ConfigMaps:        params.ConfigMaps,
+			Profile:           params.Profile,
+			RepositoryServer:  params.RepositoryServer,
+			Options:           params.Options,
 		})
 	}
 
@@ -216,15 +231,16 @@ func ChildActionSet(parent *crv1alpha1.ActionSet, params *PerformParams) (*crv1a
 	actions := make([]crv1alpha1.ActionSpec, 0, len(parent.Status.Actions)*max(1, len(params.Objects)))
 	for aidx, pa := range parent.Status.Actions {
 		as := crv1alpha1.ActionSpec{
-			Name:             parent.Spec.Actions[aidx].Name,
-			Blueprint:        pa.Blueprint,
-			Object:           pa.Object,
-			Artifacts:        pa.Artifacts,
-			Secrets:          parent.Spec.Actions[aidx].Secrets,
-			ConfigMaps:       parent.Spec.Actions[aidx].ConfigMaps,
-			Profile:          parent.Spec.Actions[aidx].Profile,
-			RepositoryServer: parent.Spec.Actions[aidx].RepositoryServer,
-			Options:          mergeOptions(params.Options, parent.Spec.Actions[aidx].Options),
+			Name:              parent.Spec.Actions[aidx].Name,
+			Blueprint:         pa.Blueprint,
+			BlueprintRevision: pa.BlueprintRevision,
+			Object:            pa.Object,
+			Artifacts:         pa.Artifacts,
+			Secrets:           parent.Spec.Actions[aidx].Secrets,
+			ConfigMaps:        parent.Spec.Actions[aidx].ConfigMaps,
+			Profile:           parent.Spec.Actions[aidx].Profile,
+			RepositoryServer:  parent.Spec.Actions[aidx].RepositoryServer,
+			Options:           mergeOptions(params.Options, parent.Spec.Actions[aidx].Options),
 		}
 		// Apply overrides
 		if params.ActionName != "" {
@@ -232,6 +248,10 @@ func ChildActionSet(parent *crv1alpha1.ActionSet, params *PerformParams) (*crv1a
 		}
 		if params.Blueprint != "" {
 			as.Blueprint = params.Blueprint
+			as.BlueprintRevision = ""
+		}
+		if params.BlueprintRevision != "" {
+			as.BlueprintRevision = params.BlueprintRevision
 		}
 		if len(params.Secrets) > 0 {
 			as.Secrets = params.Secrets
@@ -277,6 +297,33 @@ func ChildActionSet(parent *crv1alpha1.ActionSet, params *PerformParams) (*crv1a
 	return actionset, nil
 }
 
+// dropUndeclaredOptions removes the options a child ActionSet inherited from
+// its parent that are not parameters of the child action, so that a restore
+// can be created from a backup whose action declares other parameters. The
+// options passed explicitly are kept and checked against the parameters.
+func dropUndeclaredOptions(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet, explicit map[string]string) error {
+	for i, a := range as.Spec.Actions {
+		bp, err := actionBlueprint(ctx, crCli, namespace, a)
+		if err != nil {
+			return err
+		}
+		bpa, ok := bp.Actions[a.Name]
+		if !ok || len(bpa.Parameters) == 0 {
+			continue
+		}
+		declared := make(map[string]bool, len(bpa.Parameters))
+		for _, p := range bpa.Parameters {
+			declared[p.Name] = true
+		}
+		for name := range a.Options {
+			if _, ok := explicit[name]; !ok && !declared[name] {
+				delete(as.Spec.Actions[i].Options, name)
+			}
+		}
+	}
+	return nil
+}
+
 func createActionSet(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
 	as, err := crCli.CrV1alpha1().ActionSets(namespace).Create(ctx, as, metav1.CreateOptions{})
 	if err == nil {
@@ -298,6 +345,76 @@ func printActionSet(as *crv1alpha1.ActionSet) error {
 	return nil
 }
 
+// actionPlan is an action of an ActionSet with its phases rendered.
+type actionPlan struct {
+	Action    string                     `json:"action"`
+	Blueprint string                     `json:"blueprint"`
+	Object    crv1alpha1.ObjectReference `json:"object"`
+	Phases    []kanister.PhasePlan       `json:"phases"`
+}
+
+// printActionSetPlan renders the phases of the actions of the ActionSet with
+// the template params they would be run with, and prints them without
+// running them.
+func printActionSetPlan(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
+	cli, _, osCli, err := initializeClients()
+	if err != nil {
+		return err
+	}
+	dynCli, err := kube.NewDynamicClient()
+	if err != nil {
+		return err
+	}
+	plans := make([]actionPlan, 0, len(as.Spec.Actions))
+	for _, a := range as.Spec.Actions {
+		bp, err := actionBlueprint(ctx, crCli, namespace, a)
+		if err != nil {
+			return err
+		}
+		var params []crv1alpha1.BlueprintParameter
+		if bpa, ok := bp.Actions[a.Name]; ok {
+			params = bpa.Parameters
+		}
+		tp, err := param.New(ctx, cli, dynCli, crCli, osCli, a, params...)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get the template params of action %s on %s %s/%s", a.Name, a.Object.Kind, a.Object.Namespace, a.Object.Name)
+		}
+		phases, err := kanister.Plan(ctx, cli, *bp, a.Name, a.PreferredVersion, *tp)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to render action %s on %s %s/%s", a.Name, a.Object.Kind, a.Object.Namespace, a.Object.Name)
+		}
+		plans = append(plans, actionPlan{
+			Action:    a.Name,
+			Blueprint: a.Blueprint,
+			Object:    a.Object,
+			Phases:    phases,
+		})
+	}
+	planYAML, err := yaml.Marshal(plans)
+	if err != nil {
+		return errors.Wrap(err, "could not convert the plan of the action set to YAML")
+	}
+	fmt.Printf("%s", planYAML)
+	return nil
+}
+
+// actionBlueprint returns the Blueprint of the action, with the actions of its
+// revision if it is pinned to one.
+func actionBlueprint(ctx context.Context, crCli versioned.Interface, namespace string, a crv1alpha1.ActionSpec) (*crv1alpha1.Blueprint, error) {
+	if a.BlueprintRevision != "" {
+		return blueprint.GetRevision(ctx, crCli, namespace, a.Blueprint, a.BlueprintRevision)
+	}
+	bp, err := crCli.CrV1alpha1().Blueprints(namespace).Get(ctx, a.Blueprint, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get blueprint %s", a.Blueprint)
+	}
+	bp, err = kanister.ResolveBlueprint(ctx, blueprint.NewClientGetter(crCli, namespace), bp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to resolve blueprint %s", a.Blueprint)
+	}
+	return bp, nil
+}
+
 func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Interface, osCli osversioned.Interface) (*PerformParams, error) {
 	if len(args) != 0 {
 		return nil, newArgsLengthError("expected 0 arguments. got %#v", args)
@@ -310,7 +427,11 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 	actionSetName, _ := cmd.Flags().GetString(actionSetFlagName)
 	parentName, _ := cmd.Flags().GetString(sourceFlagName)
 	blueprint, _ := cmd.Flags().GetString(blueprintFlagName)
-	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	blueprintRevision, _ := cmd.Flags().GetString(blueprintRevisionFlagName)
+	dryRun, plan, err := getDryRun(cmd)
+	if err != nil {
+		return nil, err
+	}
 	labels, _ := cmd.Flags().GetString(labelsFlagName)
 	profile, err := parseProfile(cmd, ns)
 	if err != nil {
@@ -342,19 +463,21 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 	}
 
 	return &PerformParams{
-		Namespace:        ns,
-		ActionName:       actionName,
-		ActionSetName:    actionSetName,
-		ParentName:       parentName,
-		Blueprint:        blueprint,
-		DryRun:           dryRun,
-		Objects:          objects,
-		Options:          options,
-		Secrets:          secrets,
-		ConfigMaps:       cms,
-		Profile:          profile,
-		RepositoryServer: repositoryServer,
-		Labels:           ls,
+		Namespace:         ns,
+		ActionName:        actionName,
+		ActionSetName:     actionSetName,
+		ParentName:        parentName,
+		Blueprint:         blueprint,
+		BlueprintRevision: blueprintRevision,
+		DryRun:            dryRun,
+		Plan:              plan,
+		Objects:           objects,
+		Options:           options,
+		Secrets:           secrets,
+		ConfigMaps:        cms,
+		Profile:           profile,
+		RepositoryServer:  repositoryServer,
+		Labels:            ls,
 	}, nil
 }
 
@@ -711,10 +834,23 @@ func verifyParams(ctx context.Context, p *PerformParams, cli kubernetes.Interfac
 	// Blueprint
 	go func() {
 		defer wg.Done()
-		if p.Blueprint != "" {
-			_, err := crCli.CrV1alpha1().Blueprints(p.Namespace).Get(ctx, p.Blueprint, metav1.GetOptions{})
+		if p.Blueprint != "" && p.BlueprintRevision != "" {
+			bp, err := blueprint.GetRevision(ctx, crCli, p.Namespace, p.Blueprint, p.BlueprintRevision)
+			if err != nil {
+				msgs <- err
+				return
+			}
+			if err := verifyOptions(ctx, crCli, bp, p); err != nil {
+				msgs <- err
+			}
+		} else if p.Blueprint != "" {
+			bp, err := crCli.CrV1alpha1().Blueprints(p.Namespace).Get(ctx, p.Blueprint, metav1.GetOptions{})
 			if err != nil {
 				msgs <- errors.Wrapf(err, notFoundTmpl, "blueprint", p.Blueprint, p.Namespace)
+				return
+			}
+			if err := verifyOptions(ctx, crCli, bp, p); err != nil {
+				msgs <- err
 			}
 		}
 	}()
@@ -787,6 +923,44 @@ func verifyParams(ctx context.Context, p *PerformParams, cli kubernetes.Interfac
 	return nil
 }
 
+// verifyOptions checks the options against the parameters declared by the
+// action of the blueprint, and lists the parameters if they do not match
+func verifyOptions(ctx context.Context, crCli versioned.Interface, bp *crv1alpha1.Blueprint, p *PerformParams) error {
+	bp, err := kanister.ResolveBlueprint(ctx, blueprint.NewClientGetter(crCli, p.Namespace), bp)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to resolve blueprint %s", p.Blueprint)
+	}
+	a, ok := bp.Actions[p.ActionName]
+	if !ok || len(a.Parameters) == 0 {
+		return nil
+	}
+	if _, err := param.ApplyParameters(p.Options, a.Parameters); err != nil {
+		return errors.Errorf("%s\nParameters of action %s:\n%s", err, p.ActionName, describeParameters(a.Parameters))
+	}
+	return nil
+}
+
+func describeParameters(params []crv1alpha1.BlueprintParameter) string {
+	buf := &bytes.Buffer{}
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tREQUIRED\tDEFAULT\tALLOWED\tDESCRIPTION")
+	for _, prm := range params {
+		typ, def, allowed := string(prm.Type), prm.Default, "-"
+		if typ == "" {
+			typ = string(crv1alpha1.ParameterTypeString)
+		}
+		if def == "" {
+			def = "-"
+		}
+		if len(prm.Enum) > 0 {
+			allowed = strings.Join(prm.Enum, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%t\t%s\t%s\t%s\n", prm.Name, typ, prm.Required, def, allowed, prm.Description)
+	}
+	_ = tw.Flush()
+	return buf.String()
+}
+
 func max(x, y int) int {
 	if x > y {
 		return x
@@ -15,11 +15,16 @@
 package kanctl
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+	"github.com/kanisterio/kanister/pkg/param"
 )
 
 type KanctlTestSuite struct{}
@@ -181,3 +186,136 @@ func (k *KanctlTestSuite) TestParseLabels(c *C) {
 		c.Assert(op, DeepEquals, tc.expectedLabels)
 	}
 }
+
+func (k *KanctlTestSuite) TestVerifyOptions(c *C) {
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "ns"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Parameters: []crv1alpha1.BlueprintParameter{
+					{Name: "retention", Type: crv1alpha1.ParameterTypeInteger, Default: "7", Description: "Days"},
+					{Name: "mode", Enum: []string{"full", "incremental"}},
+				},
+			},
+			"restore": {},
+		},
+	}
+	cli := crfake.NewSimpleClientset()
+	for _, tc := range []struct {
+		action  string
+		options map[string]string
+		err     string
+	}{
+		{action: "backup", options: map[string]string{"retention": "30", "mode": "full"}},
+		{action: "restore", options: map[string]string{"anything": "goes"}},
+		{
+			action:  "backup",
+			options: map[string]string{"retention": "a-week"},
+			err: `Invalid option retention: expected an integer, got "a-week"
+Parameters of action backup:
+NAME       TYPE     REQUIRED  DEFAULT  ALLOWED           DESCRIPTION
+retention  integer  false     7        -                 Days
+mode       string   false     -        full,incremental`,
+		},
+	} {
+		p := &PerformParams{ActionName: tc.action, Blueprint: "bp", Namespace: "ns", Options: tc.options}
+		err := verifyOptions(context.Background(), cli, bp, p)
+		if tc.err == "" {
+			c.Assert(err, IsNil)
+			continue
+		}
+		c.Assert(err, NotNil)
+		c.Assert(strings.TrimSpace(err.Error()), Equals, tc.err)
+	}
+}
+
+func (k *KanctlTestSuite) TestChildActionSetRevision(c *C) {
+	parent := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-abc", Namespace: "ns"},
+		Spec:       &crv1alpha1.ActionSetSpec{Actions: []crv1alpha1.ActionSpec{{Name: "backup", Blueprint: "bp"}}},
+		Status: &crv1alpha1.ActionSetStatus{
+			State:   crv1alpha1.StateComplete,
+			Actions: []crv1alpha1.ActionStatus{{Name: "backup", Blueprint: "bp", BlueprintRevision: "bp-0123456789"}},
+		},
+	}
+	for _, tc := range []struct {
+		params    *PerformParams
+		blueprint string
+		revision  string
+	}{
+		{params: &PerformParams{ActionName: "restore"}, blueprint: "bp", revision: "bp-0123456789"},
+		{params: &PerformParams{ActionName: "restore", BlueprintRevision: "bp-abcdef0123"}, blueprint: "bp", revision: "bp-abcdef0123"},
+		{params: &PerformParams{ActionName: "restore", Blueprint: "other"}, blueprint: "other"},
+	} {
+		as, err := ChildActionSet(parent, tc.params)
+		c.Assert(err, IsNil)
+		c.Assert(as.Spec.Actions, HasLen, 1)
+		c.Assert(as.Spec.Actions[0].Blueprint, Equals, tc.blueprint)
+		c.Assert(as.Spec.Actions[0].BlueprintRevision, Equals, tc.revision)
+	}
+}
+
+func (k *KanctlTestSuite) TestChildActionSetOptions(c *C) {
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp", Namespace: "ns"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Parameters: []crv1alpha1.BlueprintParameter{
+					{Name: "retention", Type: crv1alpha1.ParameterTypeInteger},
+					{Name: "mode", Enum: []string{"full", "incremental"}},
+				},
+			},
+			"restore": {
+				Parameters: []crv1alpha1.BlueprintParameter{
+					{Name: "mode", Enum: []string{"full", "incremental"}},
+					{Name: "target"},
+				},
+			},
+		},
+	}
+	parent := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-abc", Namespace: "ns"},
+		Spec: &crv1alpha1.ActionSetSpec{Actions: []crv1alpha1.ActionSpec{{
+			Name:      "backup",
+			Blueprint: "bp",
+			Options:   map[string]string{"retention": "7", "mode": "full"},
+		}}},
+		Status: &crv1alpha1.ActionSetStatus{
+			State:   crv1alpha1.StateComplete,
+			Actions: []crv1alpha1.ActionStatus{{Name: "backup", Blueprint: "bp"}},
+		},
+	}
+	cli := crfake.NewSimpleClientset(bp)
+	for _, tc := range []struct {
+		options  map[string]string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			expected: map[string]string{"mode": "full"},
+		},
+		{
+			options:  map[string]string{"target": "db"},
+			expected: map[string]string{"mode": "full", "target": "db"},
+		},
+		{
+			options:  map[string]string{"retention": "30"},
+			expected: map[string]string{"mode": "full", "retention": "30"},
+			err:      true,
+		},
+	} {
+		params := &PerformParams{ActionName: "restore", Namespace: "ns", Options: tc.options}
+		as, err := ChildActionSet(parent, params)
+		c.Assert(err, IsNil)
+		err = dropUndeclaredOptions(context.Background(), cli, "ns", as, tc.options)
+		c.Assert(err, IsNil)
+		c.Assert(as.Spec.Actions, HasLen, 1)
+		c.Assert(as.Spec.Actions[0].Options, DeepEquals, tc.expected)
+		_, err = param.ApplyParameters(as.Spec.Actions[0].Options, bp.Actions["restore"].Parameters)
+		if tc.err {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+	}
+}
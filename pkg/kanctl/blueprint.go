@@ -15,13 +15,17 @@
 package kanctl
 
 import (
+	"context"
 	"errors"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	"github.com/kanisterio/kanister/pkg/blueprint"
 	"github.com/kanisterio/kanister/pkg/blueprint/validate"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/kube"
 )
 
-func performBlueprintValidation(p *validateParams) error {
+func performBlueprintValidation(ctx context.Context, p *validateParams) error {
 	if p.filename == "" {
 		return errors.New("--name is not supported for blueprint resources, please specify blueprint manifest using -f.")
 	}
@@ -32,5 +36,33 @@ func performBlueprintValidation(p *validateParams) error {
 		return err
 	}
 
+	// resolve the references to other Blueprints and BlueprintLibraries
+	g, err := referenceGetter(p.refFiles, p.namespace)
+	if err != nil {
+		return err
+	}
+	bp, err = kanister.ResolveBlueprint(ctx, g, bp)
+	if err != nil {
+		return err
+	}
+
 	return validate.Do(bp, p.functionVersion)
 }
+
+// referenceGetter returns a getter reading the --ref-file manifests or,
+// if there are none, the resources of the cluster when it is reachable.
+func referenceGetter(refFiles []string, namespace string) (kanister.BlueprintGetter, error) {
+	if len(refFiles) != 0 {
+		return blueprint.ReadReferencesFromFiles(refFiles...)
+	}
+	config, err := kube.LoadConfig()
+	if err != nil {
+		// Only the references to the Blueprint itself can be resolved
+		return nil, nil
+	}
+	crCli, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return blueprint.NewClientGetter(crCli, namespace), nil
+}
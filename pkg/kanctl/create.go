@@ -15,12 +15,17 @@
 package kanctl
 
 import (
+	"strconv"
+
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 const (
 	dryRunFlag         = "dry-run"
 	skipValidationFlag = "skip-validation"
+	// dryRunPlan is the value of --dry-run rendering the phases of ActionSets
+	dryRunPlan = "plan"
 )
 
 func newCreateCommand() *cobra.Command {
@@ -31,7 +36,21 @@ func newCreateCommand() *cobra.Command {
 	cmd.AddCommand(newActionSetCmd())
 	cmd.AddCommand(newProfileCommand())
 	cmd.AddCommand(newRepositoryServerCommand())
-	cmd.PersistentFlags().Bool(dryRunFlag, false, "if set, resource YAML will be printed but not created")
+	cmd.PersistentFlags().String(dryRunFlag, "false", "if set, resource YAML will be printed but not created. If set to plan, the phases of the actions of an ActionSet are rendered and printed instead")
+	cmd.PersistentFlags().Lookup(dryRunFlag).NoOptDefVal = "true"
 	cmd.PersistentFlags().Bool(skipValidationFlag, false, "if set, resource is not validated before creation")
 	return cmd
 }
+
+// getDryRun returns whether --dry-run is set and whether it is set to plan.
+func getDryRun(cmd *cobra.Command) (dryRun, plan bool, err error) {
+	v, _ := cmd.Flags().GetString(dryRunFlag)
+	if v == dryRunPlan {
+		return true, true, nil
+	}
+	dryRun, err = strconv.ParseBool(v)
+	if err != nil {
+		return false, false, errors.Errorf("Invalid --%s %s, expected true, false or %s", dryRunFlag, v, dryRunPlan)
+	}
+	return dryRun, false, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	_ "github.com/kanisterio/kanister/pkg/function"
+)
+
+func newFunctionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "functions [name]",
+		Short: "List the Kanister functions, or describe the arguments and outputs of a function",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			version, _ := cmd.Flags().GetString(funcVersionFlag)
+			if jsonSchema, _ := cmd.Flags().GetBool(jsonSchemaFlag); jsonSchema {
+				return printBlueprintJSONSchema(cmd.OutOrStdout(), version)
+			}
+			if len(args) == 0 {
+				return listFunctions(cmd.OutOrStdout(), version)
+			}
+			return describeFunction(cmd.OutOrStdout(), args[0], version)
+		},
+	}
+	cmd.Flags().StringP(funcVersionFlag, "v", kanister.DefaultVersion, "kanister function version, e.g., v0.0.0")
+	cmd.Flags().Bool(jsonSchemaFlag, false, "print the JSON Schema of Blueprints with the args of the functions, e.g. for editors")
+	return cmd
+}
+
+const jsonSchemaFlag = "json-schema"
+
+func listFunctions(w io.Writer, version string) error {
+	names := make([]string, 0)
+	for name := range kanister.RegisteredFunctions() {
+		if kanister.KanisterFuncForName(name, version) != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+func describeFunction(w io.Writer, name, version string) error {
+	f := kanister.KanisterFuncForName(name, version)
+	if f == nil {
+		return errors.Errorf("function %s with version %s is not registered", name, version)
+	}
+	required := make(map[string]bool)
+	for _, a := range f.RequiredArgs() {
+		required[a] = true
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Function: %s\n\n", f.Name())
+	if d, ok := f.(kanister.ArgsDescriber); ok {
+		fmt.Fprintln(tw, "ARGUMENT\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION")
+		for _, a := range d.ArgSchemas() {
+			def := "-"
+			if a.Default != nil {
+				def = fmt.Sprint(a.Default)
+			}
+			desc := a.Description
+			if len(a.Enum) > 0 {
+				desc = fmt.Sprintf("%s, one of %v", desc, a.Enum)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%t\t%s\t%s\n", a.Name, a.Type, required[a.Name], def, desc)
+		}
+	} else {
+		fmt.Fprintln(tw, "ARGUMENT\tREQUIRED")
+		for _, a := range f.Arguments() {
+			fmt.Fprintf(tw, "%s\t%t\n", a, required[a])
+		}
+	}
+	d, ok := f.(kanister.OutputsDescriber)
+	if !ok {
+		fmt.Fprintln(tw, "\nThe function does not declare its outputs")
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, "\nOUTPUT\tTYPE\tDESCRIPTION")
+	for _, o := range d.Outputs() {
+		typ := o.Type
+		if typ == "" {
+			typ = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", o.Name, typ, o.Description)
+	}
+	return tw.Flush()
+}
+
+func printBlueprintJSONSchema(w io.Writer, version string) error {
+	schema, err := kanister.BlueprintJSONSchema(version)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the JSON Schema")
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"bytes"
+
+	. "gopkg.in/check.v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+)
+
+func (k *KanctlTestSuite) TestDescribeFunction(c *C) {
+	buf := &bytes.Buffer{}
+	err := describeFunction(buf, "CreateCSISnapshot", kanister.DefaultVersion)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Matches, `(?s).*pvc +string +true +- +.*labels +object +false.*snapshotContent +string +Name of the bound VolumeSnapshotContent.*`)
+
+	err = describeFunction(buf, "Unknown", kanister.DefaultVersion)
+	c.Assert(err, NotNil)
+
+	buf.Reset()
+	err = listFunctions(buf, kanister.DefaultVersion)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Matches, `(?s).*\nScaleWorkload\n.*`)
+
+	buf.Reset()
+	err = printBlueprintJSONSchema(buf, kanister.DefaultVersion)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Matches, `(?s).*"ScaleWorkloadArgs": \{.*`)
+}
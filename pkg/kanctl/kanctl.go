@@ -63,6 +63,8 @@ func newRootCommand() *cobra.Command {
 	rootCmd.PersistentFlags().BoolVar(&Verbose, verboseFlagName, false, "Display verbose output")
 	rootCmd.AddCommand(newValidateCommand())
 	rootCmd.AddCommand(newCreateCommand())
+	rootCmd.AddCommand(newFunctionsCommand())
+	rootCmd.AddCommand(newLintCommand())
 	return rootCmd
 }
 
@@ -0,0 +1,166 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/utils/strings/slices"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/blueprint"
+	"github.com/kanisterio/kanister/pkg/blueprint/lint"
+)
+
+const (
+	disableRuleFlag = "disable"
+	failOnFlag      = "fail-on"
+)
+
+type lintParams struct {
+	filename  string
+	namespace string
+	refFiles  []string
+	disabled  []string
+	failOn    lint.Severity
+}
+
+func newLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Report risky patterns in custom Kanister resources",
+	}
+	cmd.AddCommand(newLintBlueprintCommand())
+	cmd.AddCommand(&cobra.Command{
+		Use:   "rules",
+		Short: "List the rules of kanctl lint",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listLintRules(cmd.OutOrStdout())
+		},
+	})
+	return cmd
+}
+
+func newLintBlueprintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blueprint",
+		Short: "Report risky patterns in a Blueprint",
+		Long: fmt.Sprintf(`Report risky patterns in a Blueprint.
+
+Findings can be suppressed with the %s annotation of the Blueprint, a comma
+separated list of rule names optionally followed by :<action> or
+:<action>/<phase>, e.g. "latest-image,secret-in-command:backup/dump".`, lint.IgnoreAnnotation),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := extractLintParams(cmd)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			return performBlueprintLint(cmd.Context(), cmd.OutOrStdout(), p)
+		},
+	}
+	cmd.Flags().StringP(filenameFlag, "f", "", "yaml or json file of the blueprint to lint")
+	cmd.Flags().String(resourceNamespaceFlag, "default", "namespace of the Blueprints and BlueprintLibraries referred to by the blueprint, if --ref-file is not set")
+	cmd.Flags().StringSlice(refFileFlag, nil, "yaml or json files of the Blueprints and BlueprintLibraries referred to by the blueprint. If not set, they are read from the --resource-namespace of the cluster")
+	cmd.Flags().StringSlice(disableRuleFlag, nil, "rules that are not run")
+	cmd.Flags().String(failOnFlag, lint.SeverityError.String(), "lowest severity of the findings failing the command, one of info, warning or error")
+	_ = cmd.MarkFlagRequired(filenameFlag)
+	return cmd
+}
+
+func extractLintParams(cmd *cobra.Command) (*lintParams, error) {
+	filename, _ := cmd.Flags().GetString(filenameFlag)
+	rns, _ := cmd.Flags().GetString(resourceNamespaceFlag)
+	refFiles, _ := cmd.Flags().GetStringSlice(refFileFlag)
+	disabled, _ := cmd.Flags().GetStringSlice(disableRuleFlag)
+	fo, _ := cmd.Flags().GetString(failOnFlag)
+	failOn, err := lint.ParseSeverity(fo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid --%s", failOnFlag)
+	}
+	return &lintParams{
+		filename:  filename,
+		namespace: rns,
+		refFiles:  refFiles,
+		disabled:  disabled,
+		failOn:    failOn,
+	}, nil
+}
+
+func performBlueprintLint(ctx context.Context, w io.Writer, p *lintParams) error {
+	rules, err := enabledRules(p.disabled)
+	if err != nil {
+		return err
+	}
+	bp, err := blueprint.ReadFromFile(p.filename)
+	if err != nil {
+		return err
+	}
+	g, err := referenceGetter(p.refFiles, p.namespace)
+	if err != nil {
+		return err
+	}
+	bp, err = kanister.ResolveBlueprint(ctx, g, bp)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, f := range lint.Lint(bp, rules) {
+		fmt.Fprintln(w, f)
+		if f.Severity >= p.failOn {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return errors.Errorf("Found %d issue(s) with severity %s or higher", failed, p.failOn)
+	}
+	return nil
+}
+
+func enabledRules(disabled []string) ([]lint.Rule, error) {
+	all := lint.Rules()
+	names := make([]string, 0, len(all))
+	for _, r := range all {
+		names = append(names, r.Name())
+	}
+	for _, d := range disabled {
+		if !slices.Contains(names, d) {
+			return nil, errors.Errorf("Unknown rule %s, expected one of %v", d, names)
+		}
+	}
+	rules := make([]lint.Rule, 0, len(all))
+	for _, r := range all {
+		if !slices.Contains(disabled, r.Name()) {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func listLintRules(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tSEVERITY\tDESCRIPTION")
+	for _, r := range lint.Rules() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Name(), r.Severity(), r.Description())
+	}
+	return tw.Flush()
+}
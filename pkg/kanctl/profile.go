@@ -152,7 +152,13 @@ func createNewProfile(cmd *cobra.Command, args []string) error {
 	}
 	ctx := context.Background()
 	skipValidation, _ := cmd.Flags().GetBool(skipValidationFlag)
-	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	dryRun, plan, err := getDryRun(cmd)
+	if err != nil {
+		return err
+	}
+	if plan {
+		return errors.Errorf("--%s=%s is only supported for ActionSets", dryRunFlag, dryRunPlan)
+	}
 	cli, crCli, _, err := initializeClients()
 	if err != nil {
 		return err
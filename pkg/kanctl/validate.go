@@ -27,6 +27,7 @@ type validateParams struct {
 	namespace            string
 	schemaValidationOnly bool
 	functionVersion      string
+	refFiles             []string
 }
 
 const (
@@ -35,6 +36,7 @@ const (
 	funcVersionFlag          = "functionVersion"
 	resourceNamespaceFlag    = "resource-namespace"
 	schemaValidationOnlyFlag = "schema-validation-only"
+	refFileFlag              = "ref-file"
 )
 
 func newValidateCommand() *cobra.Command {
@@ -51,6 +53,7 @@ func newValidateCommand() *cobra.Command {
 	cmd.Flags().String(resourceNamespaceFlag, "default", "namespace of the custom resource. Used when validating resource specified using --name.")
 	cmd.Flags().Bool(schemaValidationOnlyFlag, false, "if set, only schema of resource will be validated")
 	cmd.Flags().StringP(funcVersionFlag, "v", kanister.DefaultVersion, "kanister function version, e.g., v0.0.0")
+	cmd.Flags().StringSlice(refFileFlag, nil, "yaml or json files of the Blueprints and BlueprintLibraries referred to by the blueprint. If not set, they are read from the --resource-namespace of the cluster")
 	return cmd
 }
 
@@ -64,7 +67,7 @@ func performValidation(cmd *cobra.Command, args []string) error {
 	case "profile":
 		return performProfileValidation(p)
 	case "blueprint":
-		return performBlueprintValidation(p)
+		return performBlueprintValidation(cmd.Context(), p)
 	case "repository-server-secrets":
 		return performRepoServerSecretsValidation(cmd.Context(), p)
 	default:
@@ -85,6 +88,7 @@ func extractValidateParams(cmd *cobra.Command, args []string) (*validateParams,
 	rns, _ := cmd.Flags().GetString(resourceNamespaceFlag)
 	schemaValidationOnly, _ := cmd.Flags().GetBool(schemaValidationOnlyFlag)
 	funcVersion, _ := cmd.Flags().GetString(funcVersionFlag)
+	refFiles, _ := cmd.Flags().GetStringSlice(refFileFlag)
 
 	return &validateParams{
 		resourceKind:         resourceKind,
@@ -93,5 +97,6 @@ func extractValidateParams(cmd *cobra.Command, args []string) (*validateParams,
 		namespace:            rns,
 		schemaValidationOnly: schemaValidationOnly,
 		functionVersion:      funcVersion,
+		refFiles:             refFiles,
 	}, nil
 }
@@ -15,16 +15,30 @@
 package kando
 
 import (
+	"encoding/json"
+	"io"
+	"os"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/kanisterio/kanister/pkg/output"
 )
 
+const (
+	outputFileFlagName = "file"
+	outputJSONFlagName = "json"
+)
+
 func newOutputCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "output <key> <value>",
 		Short: "Create phase output with given key:value",
+		Long: `Create phase output with given key:value.
+
+With --file the output is written to the result file given by $` + output.ResultFileEnv + `
+instead of the logs. A value of "-" is then read from stdin and --json stores the
+value as JSON, so large and nested outputs are possible.`,
 		Args: func(c *cobra.Command, args []string) error {
 			return validateArguments(c, args)
 		},
@@ -33,6 +47,8 @@ func newOutputCommand() *cobra.Command {
 			return runOutputCommand(c, args)
 		},
 	}
+	cmd.Flags().Bool(outputFileFlagName, false, "Write the output to the result file instead of the logs")
+	cmd.Flags().Bool(outputJSONFlagName, false, "Store the value as JSON. Requires --file")
 	return cmd
 }
 
@@ -44,7 +60,33 @@ func validateArguments(c *cobra.Command, args []string) error {
 	return output.ValidateKey(args[0])
 }
 
-//nolint:unparam
 func runOutputCommand(c *cobra.Command, args []string) error {
-	return output.PrintOutput(args[0], args[1])
+	toFile, _ := c.Flags().GetBool(outputFileFlagName)
+	asJSON, _ := c.Flags().GetBool(outputJSONFlagName)
+	if !toFile {
+		if asJSON {
+			return errors.Errorf("--%s requires --%s", outputJSONFlagName, outputFileFlagName)
+		}
+		return output.PrintOutput(args[0], args[1])
+	}
+	path, ok := os.LookupEnv(output.ResultFileEnv)
+	if !ok || path == "" {
+		return errors.Errorf("No result file, %s is not set", output.ResultFileEnv)
+	}
+	value := args[1]
+	if value == "-" {
+		b, err := io.ReadAll(c.InOrStdin())
+		if err != nil {
+			return errors.Wrap(err, "Failed to read value from stdin")
+		}
+		value = string(b)
+	}
+	if !asJSON {
+		return output.WriteResult(path, args[0], value)
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return errors.Wrap(err, "Failed to parse value as JSON")
+	}
+	return output.WriteResult(path, args[0], v)
 }
@@ -43,6 +43,54 @@ type Func interface {
 	ExecutionProgress() (crv1alpha1.PhaseProgress, error)
 }
 
+// ArgsValidator is implemented by Funcs whose required arguments depend on the
+// arguments that are set. ValidateArgs is called in addition to checking the
+// RequiredArgs.
+type ArgsValidator interface {
+	ValidateArgs(args map[string]interface{}) error
+}
+
+// Output types of the outputs declared by Funcs
+const (
+	OutputTypeString  = "string"
+	OutputTypeInteger = "integer"
+	OutputTypeArray   = "array"
+	OutputTypeObject  = "object"
+)
+
+// AnyOutput is the name of the Output declared by Funcs whose outputs are set
+// by the commands they run, e.g. with `kando output`.
+const AnyOutput = "*"
+
+// Output describes an output of a Func.
+type Output struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// OutputsDescriber is implemented by Funcs that declare the outputs returned
+// by Exec.
+type OutputsDescriber interface {
+	Outputs() []Output
+}
+
+// FuncOutputs returns the outputs declared by the Func. The bool is false if
+// the Func does not declare its outputs or declares AnyOutput.
+func FuncOutputs(f Func) ([]Output, bool) {
+	d, ok := f.(OutputsDescriber)
+	if !ok {
+		return nil, false
+	}
+	outputs := d.Outputs()
+	for _, o := range outputs {
+		if o.Name == AnyOutput {
+			return outputs, false
+		}
+	}
+	return outputs, true
+}
+
 // Register allows Funcs to be referenced by User Defined YAMLs
 func Register(f Func) error {
 	version := *semver.MustParse(DefaultVersion)
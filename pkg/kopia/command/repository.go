@@ -92,10 +92,20 @@ func RepositoryCreateCommand(cmdArgs RepositoryCommandArgs) ([]string, error) {
 		args = args.AppendLoggableKV(overrideUsernameFlag, cmdArgs.Username)
 	}
 
+	// Fall back to the object lock configured in the location, if any
+	retentionMode, retentionPeriod := cmdArgs.RetentionMode, cmdArgs.RetentionPeriod
+	if retentionMode == "" {
+		var err error
+		retentionMode, retentionPeriod, err = storage.S3ObjectLockFromMap(cmdArgs.Location)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate retention args")
+		}
+	}
+
 	// During creation, both should be set. Technically RetentionPeriod should be >= 24 * time.Hour
-	if cmdArgs.RetentionMode != "" && cmdArgs.RetentionPeriod > 0 {
-		args = args.AppendLoggableKV(retentionModeFlag, cmdArgs.RetentionMode)
-		args = args.AppendLoggableKV(retentionPeriodFlag, cmdArgs.RetentionPeriod.String())
+	if retentionMode != "" && retentionPeriod > 0 {
+		args = args.AppendLoggableKV(retentionModeFlag, retentionMode)
+		args = args.AppendLoggableKV(retentionPeriodFlag, retentionPeriod.String())
 	}
 
 	bsArgs, err := storage.KopiaStorageArgs(&storage.StorageCommandParams{
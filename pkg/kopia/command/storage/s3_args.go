@@ -16,9 +16,13 @@ package storage
 
 import (
 	"strings"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/kanisterio/kanister/pkg/log"
 	"github.com/kanisterio/kanister/pkg/logsafe"
+	"github.com/kanisterio/kanister/pkg/secrets/repositoryserver"
 )
 
 const (
@@ -27,6 +31,10 @@ const (
 	s3DisableTLSVerifyFlag = "--disable-tls-verification"
 	s3EndpointFlag         = "--endpoint"
 	s3RegionFlag           = "--region"
+
+	// The blob retention modes of kopia
+	s3ObjectLockModeGovernance = "GOVERNANCE"
+	s3ObjectLockModeCompliance = "COMPLIANCE"
 )
 
 func s3Args(location map[string][]byte, repoPathPrefix string) logsafe.Cmd {
@@ -60,6 +68,28 @@ func s3Args(location map[string][]byte, repoPathPrefix string) logsafe.Cmd {
 	return args
 }
 
+// S3ObjectLockFromMap returns the kopia blob retention mode and period
+// configured for an S3 location using object lock. The mode is empty if
+// object lock is not configured.
+func S3ObjectLockFromMap(location map[string][]byte) (string, time.Duration, error) {
+	mode := string(location[repositoryserver.ObjectLockModeKey])
+	if mode == "" {
+		return "", 0, nil
+	}
+	if lt := locationType(location); lt != repositoryserver.LocTypeS3 && lt != repositoryserver.LocTypes3Compliant {
+		return "", 0, errors.Errorf("object lock is not supported for the location type: %s", lt)
+	}
+	mode = strings.ToUpper(mode)
+	if mode != s3ObjectLockModeGovernance && mode != s3ObjectLockModeCompliance {
+		return "", 0, errors.Errorf("Unsupported object lock mode: %s", location[repositoryserver.ObjectLockModeKey])
+	}
+	period, err := time.ParseDuration(string(location[repositoryserver.ObjectLockRetentionPeriodKey]))
+	if err != nil {
+		return "", 0, errors.Wrap(err, "Failed to parse object lock retention period")
+	}
+	return mode, period, nil
+}
+
 // ResolveS3Endpoint removes the trailing slash and
 // protocol from provided endpoint and returns the absolute
 // endpoint string
@@ -16,6 +16,7 @@ package storage
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/check.v1"
 
@@ -70,6 +71,62 @@ func (s *StorageUtilsSuite) TestS3ArgsUtil(c *check.C) {
 	}
 }
 
+func (s *StorageUtilsSuite) TestS3ObjectLockFromMap(c *check.C) {
+	for _, tc := range []struct {
+		location       map[string][]byte
+		expectedMode   string
+		expectedPeriod time.Duration
+		errChecker     check.Checker
+	}{
+		{
+			location: map[string][]byte{
+				repositoryserver.TypeKey:   []byte(repositoryserver.LocTypeS3),
+				repositoryserver.BucketKey: []byte("test-bucket"),
+			},
+			expectedMode: "",
+			errChecker:   check.IsNil,
+		},
+		{
+			location: map[string][]byte{
+				repositoryserver.TypeKey:                      []byte(repositoryserver.LocTypeS3),
+				repositoryserver.ObjectLockModeKey:            []byte("compliance"),
+				repositoryserver.ObjectLockRetentionPeriodKey: []byte("720h"),
+			},
+			expectedMode:   "COMPLIANCE",
+			expectedPeriod: 720 * time.Hour,
+			errChecker:     check.IsNil,
+		},
+		{
+			location: map[string][]byte{
+				repositoryserver.TypeKey:           []byte(repositoryserver.LocTypes3Compliant),
+				repositoryserver.ObjectLockModeKey: []byte("governance"),
+			},
+			errChecker: check.NotNil,
+		},
+		{
+			location: map[string][]byte{
+				repositoryserver.TypeKey:                      []byte(repositoryserver.LocTypeS3),
+				repositoryserver.ObjectLockModeKey:            []byte("legal-hold"),
+				repositoryserver.ObjectLockRetentionPeriodKey: []byte("24h"),
+			},
+			errChecker: check.NotNil,
+		},
+		{
+			location: map[string][]byte{
+				repositoryserver.TypeKey:                      []byte(repositoryserver.LocTypeGCS),
+				repositoryserver.ObjectLockModeKey:            []byte("governance"),
+				repositoryserver.ObjectLockRetentionPeriodKey: []byte("24h"),
+			},
+			errChecker: check.NotNil,
+		},
+	} {
+		mode, period, err := S3ObjectLockFromMap(tc.location)
+		c.Assert(err, tc.errChecker)
+		c.Assert(mode, check.Equals, tc.expectedMode)
+		c.Assert(period, check.Equals, tc.expectedPeriod)
+	}
+}
+
 func (s *StorageUtilsSuite) TestResolveS3Endpoint(c *check.C) {
 	for _, tc := range []struct {
 		endpoint       string
@@ -16,6 +16,7 @@ package storage
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
@@ -175,3 +176,22 @@ func GetMapForLocationValues(
 	}
 	return m
 }
+
+// GetMapForProfileLocation returns a map with valid keys for the location of
+// a Profile. The object lock of the location, if any, is added so that the
+// kopia repository created in it retains its blobs accordingly.
+func GetMapForProfileLocation(location v1alpha1.Location, skipSSLVerify bool) map[string][]byte {
+	m := GetMapForLocationValues(
+		repositoryserver.LocType(location.Type),
+		location.Prefix,
+		location.Region,
+		location.Bucket,
+		location.Endpoint,
+		strconv.FormatBool(skipSSLVerify),
+	)
+	if location.ObjectLock != nil {
+		m[repositoryserver.ObjectLockModeKey] = []byte(location.ObjectLock.Mode)
+		m[repositoryserver.ObjectLockRetentionPeriodKey] = []byte(location.ObjectLock.RetentionPeriod.Duration.String())
+	}
+	return m
+}
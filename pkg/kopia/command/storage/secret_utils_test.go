@@ -298,3 +298,28 @@ func (s *StorageUtilsSuite) TestGetMapForLocationValues(c *check.C) {
 		c.Assert(op, check.DeepEquals, tc.expectedOutput)
 	}
 }
+
+func (s *StorageUtilsSuite) TestGetMapForProfileLocation(c *check.C) {
+	location := v1alpha1.Location{
+		Type:   v1alpha1.LocationTypeS3Compliant,
+		Bucket: "test-bucket",
+		Region: "test-region",
+	}
+	c.Assert(GetMapForProfileLocation(location, false), check.DeepEquals, map[string][]byte{
+		repositoryserver.TypeKey:          []byte(repositoryserver.LocTypeS3),
+		repositoryserver.RegionKey:        []byte("test-region"),
+		repositoryserver.BucketKey:        []byte("test-bucket"),
+		repositoryserver.SkipSSLVerifyKey: []byte("false"),
+	})
+
+	location.ObjectLock = &v1alpha1.ObjectLock{
+		Mode:            v1alpha1.ObjectLockModeCompliance,
+		RetentionPeriod: metav1.Duration{Duration: 720 * time.Hour},
+	}
+	m := GetMapForProfileLocation(location, true)
+	c.Assert(string(m[repositoryserver.SkipSSLVerifyKey]), check.Equals, "true")
+	mode, period, err := S3ObjectLockFromMap(m)
+	c.Assert(err, check.IsNil)
+	c.Assert(mode, check.Equals, "COMPLIANCE")
+	c.Assert(period, check.Equals, 720*time.Hour)
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ksprig provides the functions of the templates of Blueprints: the
+// sprig functions that do not access the environment or the network, and
+// Kanister specific functions.
+package ksprig
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// BackupPathTimeFormat is the format of the timestamp of the paths returned by
+// backupPath. It has no colons, which some object stores and file systems do
+// not support in paths.
+const BackupPathTimeFormat = "2006-01-02T15-04-05Z"
+
+// unsafeFuncs are the sprig functions that access the environment of the
+// controller or the network.
+var unsafeFuncs = []string{"env", "expandenv", "getHostByName"}
+
+// TxtFuncMap returns the functions of the templates of Blueprints.
+func TxtFuncMap() template.FuncMap {
+	fm := sprig.TxtFuncMap()
+	for _, name := range unsafeFuncs {
+		delete(fm, name)
+	}
+	for name, f := range funcMap() {
+		fm[name] = f
+	}
+	return fm
+}
+
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		"backupPath":   backupPath,
+		"decodeBase64": decodeBase64,
+		"jsonPath":     jsonPath,
+		"quantity":     quantity,
+		"secretValue":  secretValue,
+	}
+}
+
+// quantity returns the value of a Kubernetes quantity like 10Gi, rounded up
+// to an integer, e.g. `{{ quantity .Object.spec.resources.requests.storage }}`.
+func quantity(q interface{}) (int64, error) {
+	s := strings.TrimSpace(fmt.Sprint(q))
+	parsed, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to parse quantity %q", s)
+	}
+	return parsed.Value(), nil
+}
+
+// secretValue returns the value of the key of a Secret of the template params,
+// e.g. `{{ secretValue .Secrets "db" "password" }}`. Only the Secrets referenced
+// by the ActionSet are searched, first by their reference name in the ActionSet
+// and then by their metadata.name. Other Secrets of the cluster are not read.
+func secretValue(secrets map[string]v1.Secret, name, key string) (string, error) {
+	s, ok := secrets[name]
+	if !ok {
+		found := false
+		for _, secret := range secrets {
+			if secret.GetName() == name {
+				s, found = secret, true
+				break
+			}
+		}
+		if !found {
+			return "", errors.Errorf("Secret %s not found", name)
+		}
+	}
+	if v, ok := s.Data[key]; ok {
+		return string(v), nil
+	}
+	if v, ok := s.StringData[key]; ok {
+		return v, nil
+	}
+	return "", errors.Errorf("Key %s not found in secret %s", key, name)
+}
+
+// jsonPath returns the value of a JSONPath expression in an object, e.g.
+// `{{ jsonPath .Object "{.spec.replicas}" }}`. The braces are optional.
+func jsonPath(obj interface{}, expr string) (string, error) {
+	if !strings.Contains(expr, "{") {
+		expr = fmt.Sprintf("{%s}", expr)
+	}
+	jp := jsonpath.New("jsonPath")
+	if err := jp.Parse(expr); err != nil {
+		return "", errors.Wrapf(err, "Failed to parse JSONPath %s", expr)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := jp.Execute(buf, obj); err != nil {
+		return "", errors.Wrapf(err, "Failed to evaluate JSONPath %s", expr)
+	}
+	return buf.String(), nil
+}
+
+// backupPath joins the elements and the UTC time formatted with
+// BackupPathTimeFormat, e.g. `{{ backupPath .Time "backups" .Namespace.Name }}`
+// returns `backups/ns/2023-01-02T15-04-05Z`. The time is in the RFC3339 format
+// of the Time template param.
+func backupPath(t string, elems ...string) (string, error) {
+	parsed, err := time.Parse(time.RFC3339Nano, t)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to parse time %s", t)
+	}
+	return path.Join(append(elems, parsed.UTC().Format(BackupPathTimeFormat))...), nil
+}
+
+// decodeBase64 decodes a base64 string or byte slice, and fails if it is not
+// valid base64 unlike sprig's b64dec, e.g.
+// `{{ .Options.encodedKey | decodeBase64 }}`.
+func decodeBase64(v interface{}) (string, error) {
+	var s string
+	switch e := v.(type) {
+	case string:
+		s = e
+	case []byte:
+		s = string(e)
+	default:
+		return "", errors.Errorf("Cannot decode %T from base64", v)
+	}
+	d, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to decode base64")
+	}
+	return string(d), nil
+}
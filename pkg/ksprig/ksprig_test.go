@@ -0,0 +1,141 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ksprig
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test(t *testing.T) { TestingT(t) }
+
+type KsprigSuite struct{}
+
+var _ = Suite(&KsprigSuite{})
+
+func render(text string, data interface{}) (string, error) {
+	t, err := template.New("test").Option("missingkey=error").Funcs(TxtFuncMap()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	buf := bytes.NewBuffer(nil)
+	err = t.Execute(buf, data)
+	return buf.String(), err
+}
+
+type testCase struct {
+	text string
+	out  string
+	err  string
+}
+
+func (s *KsprigSuite) check(c *C, data interface{}, tcs []testCase) {
+	for _, tc := range tcs {
+		out, err := render(tc.text, data)
+		if tc.err != "" {
+			c.Check(err, ErrorMatches, tc.err, Commentf("%s", tc.text))
+			continue
+		}
+		c.Check(err, IsNil, Commentf("%s", tc.text))
+		c.Check(out, Equals, tc.out, Commentf("%s", tc.text))
+	}
+}
+
+func (s *KsprigSuite) TestFuncMap(c *C) {
+	fm := TxtFuncMap()
+	for _, name := range unsafeFuncs {
+		c.Check(fm[name], IsNil, Commentf("%s", name))
+	}
+	for name := range funcMap() {
+		c.Check(fm[name], NotNil, Commentf("%s", name))
+	}
+	// The other sprig functions are available
+	c.Check(fm["toString"], NotNil)
+	_, err := render(`{{ env "HOME" }}`, nil)
+	c.Assert(err, ErrorMatches, `.*function "env" not defined`)
+}
+
+func (s *KsprigSuite) TestQuantity(c *C) {
+	data := map[string]interface{}{"storage": "10Gi", "cpu": "250m", "count": float64(1e9)}
+	s.check(c, data, []testCase{
+		{text: `{{ quantity .storage }}`, out: "10737418240"},
+		{text: `{{ quantity "1.5G" }}`, out: "1500000000"},
+		{text: `{{ quantity .cpu }}`, out: "1"},
+		{text: `{{ quantity .count }}`, out: "1000000000"},
+		{text: `{{ if gt (quantity .storage) (quantity "5Gi") }}larger{{ end }}`, out: "larger"},
+		{text: `{{ quantity "ten" }}`, err: `.*Failed to parse quantity "ten".*`},
+	})
+}
+
+func (s *KsprigSuite) TestSecretValue(c *C) {
+	data := map[string]interface{}{"Secrets": map[string]v1.Secret{
+		"db": {
+			ObjectMeta: metav1.ObjectMeta{Name: "db-credentials"},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+			StringData: map[string]string{"username": "admin"},
+		},
+	}}
+	s.check(c, data, []testCase{
+		{text: `{{ secretValue .Secrets "db" "password" }}`, out: "hunter2"},
+		{text: `{{ secretValue .Secrets "db-credentials" "password" }}`, out: "hunter2"},
+		{text: `{{ secretValue .Secrets "db" "username" }}`, out: "admin"},
+		{text: `{{ secretValue .Secrets "db" "token" }}`, err: ".*Key token not found in secret db"},
+		{text: `{{ secretValue .Secrets "other" "password" }}`, err: ".*Secret other not found"},
+	})
+}
+
+func (s *KsprigSuite) TestJSONPath(c *C) {
+	data := map[string]interface{}{"Object": map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "db"},
+		"spec": map[string]interface{}{
+			"replicas":   int64(3),
+			"containers": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}},
+		},
+	}}
+	s.check(c, data, []testCase{
+		{text: `{{ jsonPath .Object "{.metadata.name}" }}`, out: "db"},
+		{text: `{{ jsonPath .Object ".spec.replicas" }}`, out: "3"},
+		{text: `{{ jsonPath .Object "{.spec.containers[*].name}" }}`, out: "a b"},
+		{text: `{{ jsonPath .Object "{.spec.containers[1].name}" }}`, out: "b"},
+		{text: `{{ jsonPath .Object "{.status.phase}" }}`, err: ".*Failed to evaluate JSONPath {.status.phase}.*"},
+		{text: `{{ jsonPath .Object "{.spec[}" }}`, err: ".*Failed to parse JSONPath.*"},
+	})
+}
+
+func (s *KsprigSuite) TestBackupPath(c *C) {
+	data := map[string]interface{}{"Time": "2023-01-02T15:04:05.999999999Z", "Local": "2023-01-02T16:04:05+01:00"}
+	s.check(c, data, []testCase{
+		{text: `{{ backupPath .Time "backups" "ns" "db" }}`, out: "backups/ns/db/2023-01-02T15-04-05Z"},
+		{text: `{{ backupPath .Local "/backups/" }}`, out: "/backups/2023-01-02T15-04-05Z"},
+		{text: `{{ backupPath .Time }}`, out: "2023-01-02T15-04-05Z"},
+		{text: `{{ backupPath "yesterday" "backups" }}`, err: ".*Failed to parse time yesterday.*"},
+	})
+}
+
+func (s *KsprigSuite) TestDecodeBase64(c *C) {
+	data := map[string]interface{}{"bytes": []byte("aHVudGVyMg=="), "number": 42}
+	s.check(c, data, []testCase{
+		{text: `{{ decodeBase64 "aHVudGVyMg==" }}`, out: "hunter2"},
+		{text: `{{ .bytes | decodeBase64 }}`, out: "hunter2"},
+		{text: `{{ decodeBase64 "aHVudGVyMg==\n" }}`, out: "hunter2"},
+		{text: `{{ decodeBase64 "not base64!" }}`, err: ".*Failed to decode base64.*"},
+		{text: `{{ decodeBase64 .number }}`, err: ".*Cannot decode int from base64"},
+	})
+}
@@ -40,6 +40,9 @@ type FakePodController struct {
 	GetFileWriterRet    *FakePodFileWriter
 	GetFileWriterErr    error
 
+	GetResultRet map[string]interface{}
+	GetResultErr error
+
 	StopPodCalled        bool
 	StopPodErr           error
 	InStopPodStopTimeout time.Duration
@@ -85,6 +88,10 @@ func (fpc *FakePodController) GetFileWriter() (PodFileWriter, error) {
 	return fpc.GetFileWriterRet, fpc.GetFileWriterErr
 }
 
+func (fpc *FakePodController) GetResult(_ context.Context) (map[string]interface{}, error) {
+	return fpc.GetResultRet, fpc.GetResultErr
+}
+
 func (fpc *FakePodController) StopPod(ctx context.Context, stopTimeout time.Duration, gracePeriodSeconds int64) error {
 	fpc.StopPodCalled = true
 	fpc.InStopPodStopTimeout = stopTimeout
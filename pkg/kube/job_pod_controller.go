@@ -0,0 +1,427 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+// JobOptions specify how a pod is run through a Job. The Job replaces the
+// pod if it fails, e.g. because its node is drained.
+type JobOptions struct {
+	// BackoffLimit is the number of retries before the Job is marked as failed
+	BackoffLimit *int32
+	// ActiveDeadlineSeconds limits the duration of the Job, including retries
+	ActiveDeadlineSeconds *int64
+}
+
+// jobPodController implements PodController for a pod run by a Job. It
+// follows the current pod of the Job across retries. The pods are deleted
+// through the PodControllerProcessor.
+type jobPodController struct {
+	cli        kubernetes.Interface
+	podOptions *PodOptions
+	pcp        PodControllerProcessor
+
+	job      *batchv1.Job
+	pod      *corev1.Pod
+	podReady bool
+}
+
+var _ PodController = (*jobPodController)(nil)
+
+func newJobPodController(cli kubernetes.Interface, options *PodOptions, opts ...PodControllerOption) PodController {
+	// The options apply to a podController, whose processor is used
+	pc := &podController{}
+	for _, opt := range opts {
+		opt(pc)
+	}
+	if pc.pcp == nil {
+		pc.pcp = &podControllerProcessor{
+			cli: cli,
+		}
+	}
+	return &jobPodController{
+		cli:        cli,
+		podOptions: options,
+		pcp:        pc.pcp,
+	}
+}
+
+func (p *jobPodController) PodName() string {
+	if p.pod == nil {
+		return ""
+	}
+	return p.pod.Name
+}
+
+func (p *jobPodController) Pod() *corev1.Pod {
+	return p.pod
+}
+
+// StartPod creates the Job and waits until it created its first pod
+func (p *jobPodController) StartPod(ctx context.Context) error {
+	if p.job != nil {
+		return errors.Wrap(ErrPodControllerPodAlreadyStarted, "Failed to create job")
+	}
+
+	if p.cli == nil || p.podOptions == nil || p.podOptions.Job == nil {
+		return errors.Wrap(ErrPodControllerNotInitialized, "Failed to create job")
+	}
+
+	// The result container would keep failed pods from terminating, so the
+	// Job could not retry them
+	if p.podOptions.ResultFile {
+		return errors.New("Result files are not supported for pods run through a Job")
+	}
+
+	pod, err := GetPodObjectFromPodOptions(ctx, p.cli, p.podOptions)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get pod from podOptions. Namespace: %s, NameFmt: %s", p.podOptions.Namespace, p.podOptions.GenerateName)
+	}
+	// Jobs don't support restarting pods that exited successfully
+	if pod.Spec.RestartPolicy == corev1.RestartPolicyAlways {
+		pod.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			GenerateName:    pod.GenerateName,
+			Namespace:       pod.Namespace,
+			Labels:          pod.Labels,
+			Annotations:     pod.Annotations,
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          p.podOptions.Job.BackoffLimit,
+			ActiveDeadlineSeconds: p.podOptions.Job.ActiveDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      pod.Labels,
+					Annotations: pod.Annotations,
+				},
+				Spec: pod.Spec,
+			},
+		},
+	}
+
+	log.Debug().WithContext(ctx).Print("Creating Job", field.M{"name": job.Name, "namespace": job.Namespace})
+	job, err = p.cli.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		log.WithError(err).Print("Failed to create job", field.M{"JobName": p.podOptions.Name, "Namespace": p.podOptions.Namespace})
+		return errors.Wrap(err, "Failed to create job")
+	}
+	p.job = job
+
+	err = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		if err := p.checkJobFailed(ctx); err != nil {
+			return false, err
+		}
+		pod, err := p.currentPod(ctx)
+		p.pod = pod
+		return pod != nil, err
+	})
+	return errors.Wrapf(err, "Job %s did not create a pod", job.Name)
+}
+
+// WaitForPodReady waits until a pod of the Job exits the pending state. Pods
+// that fail before are replaced by the Job.
+func (p *jobPodController) WaitForPodReady(ctx context.Context) error {
+	if p.job == nil {
+		return ErrPodControllerPodNotStarted
+	}
+
+	timeoutCtx, waitCancel := context.WithTimeout(ctx, GetPodReadyWaitTimeout())
+	defer waitCancel()
+	err := poll.Wait(timeoutCtx, func(ctx context.Context) (bool, error) {
+		if err := p.checkJobFailed(ctx); err != nil {
+			return false, err
+		}
+		pod, err := p.currentPod(ctx)
+		if err != nil || pod == nil {
+			return false, err
+		}
+		p.pod = pod
+		return pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded, nil
+	})
+	if err != nil {
+		log.WithError(err).Print("Pod failed to become ready in time", field.M{"JobName": p.job.Name, "Namespace": p.job.Namespace})
+		return errors.Wrap(err, "Pod failed to become ready in time")
+	}
+
+	p.podReady = true
+
+	return nil
+}
+
+// WaitForPodCompletion waits for the Job to complete or to run out of retries
+func (p *jobPodController) WaitForPodCompletion(ctx context.Context) error {
+	if p.job == nil {
+		return ErrPodControllerPodNotStarted
+	}
+
+	if !p.podReady {
+		return ErrPodControllerPodNotReady
+	}
+
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		job, err := p.cli.BatchV1().Jobs(p.job.Namespace).Get(ctx, p.job.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to get job %s", p.job.Name)
+		}
+		if err := jobFailedError(job); err != nil {
+			return false, err
+		}
+		return jobCondition(job, batchv1.JobComplete) != nil, nil
+	})
+	if err != nil {
+		log.WithError(err).Print("Job failed to complete in time", field.M{"JobName": p.job.Name, "Namespace": p.job.Namespace})
+		return errors.Wrap(err, "Job failed to complete in time")
+	}
+
+	p.podReady = false
+
+	return nil
+}
+
+// StopPod deletes the Job and its pods
+func (p *jobPodController) StopPod(ctx context.Context, stopTimeout time.Duration, gracePeriodSeconds int64) error {
+	if p.job == nil {
+		return ErrPodControllerPodNotStarted
+	}
+
+	if stopTimeout != PodControllerInfiniteStopTime {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, stopTimeout)
+		defer cancel()
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds, PropagationPolicy: &propagation}
+	if err := p.cli.BatchV1().Jobs(p.job.Namespace).Delete(ctx, p.job.Name, opts); err != nil {
+		log.WithError(err).Print("Failed to delete job", field.M{"JobName": p.job.Name, "Namespace": p.job.Namespace})
+		return err
+	}
+	// The grace period of the Job does not apply to its pods, which the
+	// garbage collector deletes
+	if p.pod != nil {
+		err := p.pcp.DeletePod(ctx, p.pod.Namespace, p.pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.WithError(err).Print("Failed to delete pod", field.M{"PodName": p.pod.Name, "Namespace": p.pod.Namespace})
+			return err
+		}
+	}
+
+	p.podReady = false
+	p.job = nil
+	p.pod = nil
+
+	return nil
+}
+
+// StreamPodLogs streams the logs of the current pod. If the pod fails and the
+// Job retries it, the logs of the next pod are appended.
+func (p *jobPodController) StreamPodLogs(ctx context.Context) (io.ReadCloser, error) {
+	if p.pod == nil {
+		return nil, ErrPodControllerPodNotStarted
+	}
+
+	container := ContainerNameFromPodOptsOrDefault(p.podOptions)
+	pod := p.pod
+	r, err := StreamPodLogs(ctx, p.cli, pod.Namespace, pod.Name, container)
+	if err != nil {
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			_, err := io.Copy(pw, r)
+			r.Close() //nolint:errcheck
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if pod, err = p.nextPod(ctx, pod); err != nil || pod == nil {
+				pw.CloseWithError(err)
+				return
+			}
+			log.WithContext(ctx).Print("Following logs of retried pod", field.M{"JobName": p.job.Name, "PodName": pod.Name})
+			if r, err = StreamPodLogs(ctx, p.cli, pod.Namespace, pod.Name, container); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr, nil
+}
+
+// GetResult fails since result files are not supported for pods run through a Job
+func (p *jobPodController) GetResult(ctx context.Context) (map[string]interface{}, error) {
+	return nil, ErrPodControllerNoResult
+}
+
+// nextPod waits until the pod terminates and returns the pod replacing it, or
+// nil if the pod was not replaced
+func (p *jobPodController) nextPod(ctx context.Context, pod *corev1.Pod) (*corev1.Pod, error) {
+	var next *corev1.Pod
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		job, err := p.cli.BatchV1().Jobs(p.job.Namespace).Get(ctx, p.job.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to get job %s", p.job.Name)
+		}
+		// Errors of finished Jobs are reported by WaitForPodCompletion
+		if jobCondition(job, batchv1.JobComplete) != nil || jobCondition(job, batchv1.JobFailed) != nil {
+			return true, nil
+		}
+		cur, err := p.cli.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err == nil && cur.DeletionTimestamp == nil {
+			switch cur.Status.Phase {
+			case corev1.PodSucceeded:
+				return true, nil
+			case corev1.PodFailed:
+			default:
+				// The log stream ended before the pod terminated
+				return false, nil
+			}
+		}
+		newPod, err := p.currentPod(ctx)
+		if err != nil || newPod == nil || newPod.Name == pod.Name {
+			return false, err
+		}
+		// Logs are available once the pod started
+		if newPod.Status.Phase == corev1.PodPending {
+			return false, nil
+		}
+		next = newPod
+		return true, nil
+	})
+	return next, err
+}
+
+// currentPod returns the most recently created pod of the Job that is not
+// being deleted
+func (p *jobPodController) currentPod(ctx context.Context) (*corev1.Pod, error) {
+	selector := labels.Set{"job-name": p.job.Name}.AsSelector()
+	if p.job.Spec.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(p.job.Spec.Selector); err != nil {
+			return nil, errors.Wrapf(err, "Invalid selector of job %s", p.job.Name)
+		}
+	}
+	pods, err := p.cli.CoreV1().Pods(p.job.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list pods of job %s", p.job.Name)
+	}
+	var cur *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if cur == nil || cur.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			cur = pod
+		}
+	}
+	return cur, nil
+}
+
+func (p *jobPodController) checkJobFailed(ctx context.Context) error {
+	job, err := p.cli.BatchV1().Jobs(p.job.Namespace).Get(ctx, p.job.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get job %s", p.job.Name)
+	}
+	return jobFailedError(job)
+}
+
+func jobFailedError(job *batchv1.Job) error {
+	if c := jobCondition(job, batchv1.JobFailed); c != nil {
+		return errors.Errorf("Job %s failed, reason: %s, message: %s", job.Name, c.Reason, c.Message)
+	}
+	return nil
+}
+
+func jobCondition(job *batchv1.Job, t batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		c := &job.Status.Conditions[i]
+		if c.Type == t && c.Status == corev1.ConditionTrue {
+			return c
+		}
+	}
+	return nil
+}
+
+// GetCommandExecutor returns PodCommandExecutor instance for the current pod of the Job.
+// If pod is not created or not ready yet, it will fail with an appropriate error.
+func (p *jobPodController) GetCommandExecutor() (PodCommandExecutor, error) {
+	if p.pod == nil {
+		return nil, ErrPodControllerPodNotStarted
+	}
+
+	if !p.podReady {
+		return nil, ErrPodControllerPodNotReady
+	}
+
+	pce := &podCommandExecutor{
+		cli:           p.cli,
+		namespace:     p.pod.Namespace,
+		podName:       p.pod.Name,
+		containerName: ContainerNameFromPodOptsOrDefault(p.podOptions),
+	}
+
+	pce.pcep = &podCommandExecutorProcessor{
+		cli: p.cli,
+	}
+
+	return pce, nil
+}
+
+// GetFileWriter returns PodFileWriter instance for the current pod of the Job.
+// If pod is not created or not ready yet, it will fail with an appropriate error.
+func (p *jobPodController) GetFileWriter() (PodFileWriter, error) {
+	if p.pod == nil {
+		return nil, ErrPodControllerPodNotStarted
+	}
+
+	if !p.podReady {
+		return nil, ErrPodControllerPodNotReady
+	}
+
+	pfw := &podFileWriter{
+		cli:           p.cli,
+		namespace:     p.pod.Namespace,
+		podName:       p.pod.Name,
+		containerName: ContainerNameFromPodOptsOrDefault(p.podOptions),
+	}
+
+	pfw.fileWriterProcessor = &podFileWriterProcessor{
+		cli: p.cli,
+	}
+
+	return pfw, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	. "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type JobPodControllerTestSuite struct{}
+
+var _ = Suite(&JobPodControllerTestSuite{})
+
+const jobPodControllerJobName = "test-job"
+
+func (s *JobPodControllerTestSuite) SetUpSuite(c *C) {
+	os.Setenv("POD_NAMESPACE", podControllerNS)
+}
+
+func (s *JobPodControllerTestSuite) jobPod(name string, phase corev1.PodPhase, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         podControllerNS,
+			Labels:            map[string]string{"job-name": jobPodControllerJobName},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: defaultContainerName}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func (s *JobPodControllerTestSuite) podOptions() *PodOptions {
+	backoffLimit := int32(2)
+	return &PodOptions{
+		Namespace:          podControllerNS,
+		Name:               jobPodControllerJobName,
+		Image:              "image",
+		Command:            []string{"sh", "-c", "date"},
+		ServiceAccountName: "sa",
+		Job:                &JobOptions{BackoffLimit: &backoffLimit},
+	}
+}
+
+func (s *JobPodControllerTestSuite) setJobCondition(ctx context.Context, c *C, cli *fake.Clientset, t batchv1.JobConditionType) {
+	job, err := cli.BatchV1().Jobs(podControllerNS).Get(ctx, jobPodControllerJobName, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: t, Status: corev1.ConditionTrue})
+	_, err = cli.BatchV1().Jobs(podControllerNS).UpdateStatus(ctx, job, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+}
+
+func (s *JobPodControllerTestSuite) TestJobPodControllerRetries(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	now := time.Now()
+	first := s.jobPod("test-job-1", corev1.PodRunning, now)
+	cli := fake.NewSimpleClientset(first)
+
+	pc := NewPodController(cli, s.podOptions())
+	_, ok := pc.(*jobPodController)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(pc.StartPod(ctx), IsNil)
+	c.Assert(pc.PodName(), Equals, first.Name)
+	job, err := cli.BatchV1().Jobs(podControllerNS).Get(ctx, jobPodControllerJobName, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(*job.Spec.BackoffLimit, Equals, int32(2))
+	c.Assert(job.Spec.Template.Spec.Containers[0].Image, Equals, "image")
+	c.Assert(job.Spec.Template.Spec.RestartPolicy, Equals, corev1.RestartPolicyNever)
+
+	c.Assert(pc.WaitForPodReady(ctx), IsNil)
+	_, err = pc.GetCommandExecutor()
+	c.Assert(err, IsNil)
+
+	// The first pod fails and the Job replaces it
+	first.Status.Phase = corev1.PodFailed
+	_, err = cli.CoreV1().Pods(podControllerNS).Update(ctx, first, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+	_, err = cli.CoreV1().Pods(podControllerNS).Create(ctx, s.jobPod("test-job-2", corev1.PodSucceeded, now.Add(time.Second)), metav1.CreateOptions{})
+	c.Assert(err, IsNil)
+
+	r, err := pc.StreamPodLogs(ctx)
+	c.Assert(err, IsNil)
+	logs, err := io.ReadAll(r)
+	c.Assert(err, IsNil)
+	// The fake client returns the same logs for every pod
+	c.Assert(string(logs), Equals, "fake logsfake logs")
+
+	s.setJobCondition(ctx, c, cli, batchv1.JobComplete)
+	c.Assert(pc.WaitForPodCompletion(ctx), IsNil)
+
+	c.Assert(pc.StopPod(ctx, PodControllerDefaultStopTime, 0), IsNil)
+	_, err = cli.BatchV1().Jobs(podControllerNS).Get(ctx, jobPodControllerJobName, metav1.GetOptions{})
+	c.Assert(err, NotNil)
+	c.Assert(pc.StopPod(ctx, PodControllerDefaultStopTime, 0), Equals, ErrPodControllerPodNotStarted)
+}
+
+func (s *JobPodControllerTestSuite) TestJobPodControllerFailed(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cli := fake.NewSimpleClientset(s.jobPod("test-job-1", corev1.PodRunning, time.Now()))
+
+	pc := NewPodController(cli, s.podOptions())
+	c.Assert(pc.WaitForPodReady(ctx), Equals, ErrPodControllerPodNotStarted)
+	c.Assert(pc.StartPod(ctx), IsNil)
+	c.Assert(pc.StartPod(ctx), NotNil)
+	c.Assert(pc.WaitForPodCompletion(ctx), Equals, ErrPodControllerPodNotReady)
+	c.Assert(pc.WaitForPodReady(ctx), IsNil)
+
+	// The Job runs out of retries
+	s.setJobCondition(ctx, c, cli, batchv1.JobFailed)
+	c.Assert(pc.WaitForPodCompletion(ctx), NotNil)
+}
+
+func (s *JobPodControllerTestSuite) TestJobPodControllerProcessor(c *C) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cli := fake.NewSimpleClientset(s.jobPod("test-job-1", corev1.PodRunning, time.Now()))
+
+	// The processor passed by the caller deletes the pod
+	pcp := &FakePodControllerProcessor{}
+	pc := NewPodController(cli, s.podOptions(), WithPodControllerProcessor(pcp))
+	c.Assert(pc.StartPod(ctx), IsNil)
+	c.Assert(pc.StopPod(ctx, PodControllerDefaultStopTime, 5), IsNil)
+	c.Assert(pcp.InDeletePodPodName, Equals, "test-job-1")
+	c.Assert(*pcp.InDeletePodOptions.GracePeriodSeconds, Equals, int64(5))
+}
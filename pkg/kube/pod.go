@@ -79,6 +79,19 @@ type PodOptions struct {
 	OwnerReferences          []metav1.OwnerReference
 	EnvironmentVariables     []v1.EnvVar
 	Lifecycle                *v1.Lifecycle
+	// Containers are run in the pod next to the main container. A container
+	// with the name of the main container is used as the base spec of the
+	// main container, e.g. to set its volume mounts.
+	Containers     []v1.Container
+	InitContainers []v1.Container
+	// PodVolumes are added to the volumes of the pod, e.g. an emptyDir shared
+	// between the containers
+	PodVolumes []v1.Volume
+	// Job runs the pod through a Job if set, see NewPodController
+	Job *JobOptions
+	// ResultFile adds a volume in which the containers can write a JSON result
+	// file, see output.ResultFileEnv. It is collected by PodController.GetResult.
+	ResultFile bool
 }
 
 func GetPodObjectFromPodOptions(ctx context.Context, cli kubernetes.Interface, opts *PodOptions) (*v1.Pod, error) {
@@ -115,18 +128,14 @@ func GetPodObjectFromPodOptions(ctx context.Context, cli kubernetes.Interface, o
 		return nil, errors.Wrapf(err, "Failed to create raw block volume spec")
 	}
 	podVolumes = append(podVolumes, blockVolumes...)
+	podVolumes = append(podVolumes, opts.PodVolumes...)
+	containers, err := podContainers(opts, volumeMounts, volumeDevices)
+	if err != nil {
+		return nil, err
+	}
 	defaultSpecs := v1.PodSpec{
-		Containers: []v1.Container{
-			{
-				Name:            ContainerNameFromPodOptsOrDefault(opts),
-				Image:           opts.Image,
-				Command:         opts.Command,
-				ImagePullPolicy: v1.PullPolicy(v1.PullIfNotPresent),
-				VolumeMounts:    volumeMounts,
-				VolumeDevices:   volumeDevices,
-				Resources:       opts.Resources,
-			},
-		},
+		Containers:     containers,
+		InitContainers: opts.InitContainers,
 		// RestartPolicy dictates when the containers of the pod should be
 		// restarted.  The possible values include Always, OnFailure and Never
 		// with Never being the default.  OnFailure policy will result in
@@ -137,7 +146,7 @@ func GetPodObjectFromPodOptions(ctx context.Context, cli kubernetes.Interface, o
 	}
 
 	if opts.EnvironmentVariables != nil && len(opts.EnvironmentVariables) > 0 {
-		defaultSpecs.Containers[0].Env = opts.EnvironmentVariables
+		defaultSpecs.Containers[0].Env = append(defaultSpecs.Containers[0].Env, opts.EnvironmentVariables...)
 	}
 
 	// Patch default Pod Specs if needed
@@ -151,6 +160,10 @@ func GetPodObjectFromPodOptions(ctx context.Context, cli kubernetes.Interface, o
 		return patchedSpecs.Containers[i].Name == ContainerNameFromPodOptsOrDefault(opts)
 	})
 
+	if opts.ResultFile {
+		addResultCollector(&patchedSpecs)
+	}
+
 	pod := &v1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: opts.GenerateName,
@@ -199,6 +212,45 @@ func GetPodObjectFromPodOptions(ctx context.Context, cli kubernetes.Interface, o
 	return pod, nil
 }
 
+// podContainers returns the main container followed by the additional
+// containers of the pod options
+func podContainers(opts *PodOptions, volumeMounts []v1.VolumeMount, volumeDevices []v1.VolumeDevice) ([]v1.Container, error) {
+	main := v1.Container{
+		Name: ContainerNameFromPodOptsOrDefault(opts),
+	}
+	containers := []v1.Container{}
+	names := make(map[string]bool, len(opts.Containers))
+	for _, c := range opts.Containers {
+		if names[c.Name] {
+			return nil, errors.Errorf("Duplicate container name %s", c.Name)
+		}
+		names[c.Name] = true
+		if c.Name == main.Name {
+			main = *c.DeepCopy()
+			continue
+		}
+		if c.ImagePullPolicy == "" {
+			c.ImagePullPolicy = v1.PullIfNotPresent
+		}
+		containers = append(containers, c)
+	}
+	if opts.Image != "" {
+		main.Image = opts.Image
+	}
+	if len(opts.Command) > 0 {
+		main.Command = opts.Command
+	}
+	if main.ImagePullPolicy == "" {
+		main.ImagePullPolicy = v1.PullIfNotPresent
+	}
+	if opts.Resources.Limits != nil || opts.Resources.Requests != nil {
+		main.Resources = opts.Resources
+	}
+	main.VolumeMounts = append(main.VolumeMounts, volumeMounts...)
+	main.VolumeDevices = append(main.VolumeDevices, volumeDevices...)
+	return append([]v1.Container{main}, containers...), nil
+}
+
 // ContainerNameFromPodOptsOrDefault returns the container name if it's set in
 // the passed `podOptions` value. If not, it's returns the default container
 // name. This should be used whenever we create pods for Kanister functions.
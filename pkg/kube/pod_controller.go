@@ -53,6 +53,8 @@ type PodController interface {
 
 	GetCommandExecutor() (PodCommandExecutor, error)
 	GetFileWriter() (PodFileWriter, error)
+
+	GetResult(ctx context.Context) (map[string]interface{}, error)
 }
 
 // podController keeps Kubernetes Client and PodOptions needed for creating a Pod.
@@ -78,8 +80,14 @@ func WithPodControllerProcessor(processor PodControllerProcessor) PodControllerO
 	}
 }
 
-// NewPodController returns a new PodController given Kubernetes Client and PodOptions
+// NewPodController returns a new PodController given Kubernetes Client and PodOptions.
+// If Job is set in the PodOptions, the pod is run through a Job and the
+// PodController follows the current pod of the Job.
 func NewPodController(cli kubernetes.Interface, options *PodOptions, opts ...PodControllerOption) PodController {
+	if options != nil && options.Job != nil {
+		return newJobPodController(cli, options, opts...)
+	}
+
 	r := &podController{
 		cli:        cli,
 		podOptions: options,
@@ -215,6 +223,20 @@ func (p *podController) StopPod(ctx context.Context, stopTimeout time.Duration,
 	return nil
 }
 
+// GetResult waits until the main container terminated and returns the outputs of its result file.
+// Requires ResultFile to be set in the PodOptions, otherwise it will fail with ErrPodControllerNoResult.
+func (p *podController) GetResult(ctx context.Context) (map[string]interface{}, error) {
+	if p.podName == "" {
+		return nil, ErrPodControllerPodNotStarted
+	}
+
+	if !p.podOptions.ResultFile {
+		return nil, ErrPodControllerNoResult
+	}
+
+	return collectResult(ctx, p.cli, p.pod.Namespace, p.podName, ContainerNameFromPodOptsOrDefault(p.podOptions))
+}
+
 func (p *podController) StreamPodLogs(ctx context.Context) (io.ReadCloser, error) {
 	if p.podName == "" {
 		return nil, ErrPodControllerPodNotStarted
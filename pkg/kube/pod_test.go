@@ -36,6 +36,7 @@ import (
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/output"
 )
 
 type PodSuite struct {
@@ -957,3 +958,89 @@ func (s *PodControllerTestSuite) TestContainerNameFromPodOptsOrDefault(c *C) {
 	name = ContainerNameFromPodOptsOrDefault(nil)
 	c.Assert(name, Equals, defaultContainerName)
 }
+
+func (s *PodControllerTestSuite) TestGetPodObjectWithContainers(c *C) {
+	shared := corev1.VolumeMount{Name: "shared", MountPath: "/shared"}
+	po := &PodOptions{
+		Namespace:          "ns",
+		GenerateName:       "test-",
+		ContainerName:      "upload",
+		ServiceAccountName: "sa",
+		Containers: []corev1.Container{
+			{
+				Name:         "dump",
+				Image:        "dump-image",
+				Command:      []string{"sh", "-c", "dump > /shared/fifo"},
+				VolumeMounts: []corev1.VolumeMount{shared},
+			},
+			{
+				Name:         "upload",
+				Image:        "upload-image",
+				Command:      []string{"sh", "-c", "upload < /shared/fifo"},
+				VolumeMounts: []corev1.VolumeMount{shared},
+			},
+		},
+		InitContainers: []corev1.Container{
+			{
+				Name:         "init",
+				Image:        "init-image",
+				Command:      []string{"mkfifo", "/shared/fifo"},
+				VolumeMounts: []corev1.VolumeMount{shared},
+			},
+		},
+		PodVolumes: []corev1.Volume{
+			{
+				Name:         "shared",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+		},
+		EnvironmentVariables: []corev1.EnvVar{{Name: "KEY", Value: "value"}},
+	}
+	pod, err := GetPodObjectFromPodOptions(context.Background(), fake.NewSimpleClientset(), po)
+	c.Assert(err, IsNil)
+	// The main container is always the first
+	c.Assert(pod.Spec.Containers, HasLen, 2)
+	c.Assert(pod.Spec.Containers[0].Name, Equals, "upload")
+	c.Assert(pod.Spec.Containers[0].Image, Equals, "upload-image")
+	c.Assert(pod.Spec.Containers[0].VolumeMounts, DeepEquals, []corev1.VolumeMount{shared})
+	c.Assert(pod.Spec.Containers[0].Env, DeepEquals, po.EnvironmentVariables)
+	c.Assert(pod.Spec.Containers[1].Name, Equals, "dump")
+	c.Assert(pod.Spec.Containers[1].ImagePullPolicy, Equals, corev1.PullIfNotPresent)
+	c.Assert(pod.Spec.InitContainers, HasLen, 1)
+	c.Assert(pod.Spec.Volumes, DeepEquals, po.PodVolumes)
+
+	po.Containers = append(po.Containers, corev1.Container{Name: "dump"})
+	_, err = GetPodObjectFromPodOptions(context.Background(), fake.NewSimpleClientset(), po)
+	c.Assert(err, NotNil)
+}
+
+func (s *PodControllerTestSuite) TestGetPodObjectWithResultFile(c *C) {
+	po := &PodOptions{
+		Namespace:          "ns",
+		GenerateName:       "test-",
+		Image:              "image",
+		Command:            []string{"sh", "-c", "kando output --file key value"},
+		ServiceAccountName: "sa",
+		InitContainers:     []corev1.Container{{Name: "init", Image: "init-image"}},
+		ResultFile:         true,
+	}
+	pod, err := GetPodObjectFromPodOptions(context.Background(), fake.NewSimpleClientset(), po)
+	c.Assert(err, IsNil)
+	c.Assert(pod.Spec.Containers, HasLen, 2)
+	c.Assert(pod.Spec.Containers[0].Name, Equals, defaultContainerName)
+	c.Assert(pod.Spec.Containers[1].Name, Equals, ResultContainerName)
+	mount := corev1.VolumeMount{Name: resultVolumeName, MountPath: ResultDir}
+	for _, ctr := range append(pod.Spec.Containers, pod.Spec.InitContainers...) {
+		c.Assert(ctr.VolumeMounts, DeepEquals, []corev1.VolumeMount{mount})
+	}
+	env := corev1.EnvVar{Name: output.ResultFileEnv, Value: ResultDir + "/" + resultFileName}
+	c.Assert(pod.Spec.Containers[0].Env, DeepEquals, []corev1.EnvVar{env})
+	c.Assert(pod.Spec.InitContainers[0].Env, DeepEquals, []corev1.EnvVar{env})
+	c.Assert(pod.Spec.Volumes, HasLen, 1)
+	c.Assert(pod.Spec.Volumes[0].Name, Equals, resultVolumeName)
+
+	// Results are not collected from pods of a Job
+	po.Job = &JobOptions{}
+	err = NewPodController(fake.NewSimpleClientset(), po).StartPod(context.Background())
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/consts"
+	"github.com/kanisterio/kanister/pkg/output"
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+const (
+	// ResultContainerName is the name of the container holding the result
+	// file until it is collected
+	ResultContainerName = "kanister-result"
+	// ResultDir is the directory of the result file in all containers
+	ResultDir = "/kanister-result"
+
+	resultVolumeName = "kanister-result"
+	resultFileName   = "result.json"
+	resultDoneFile   = ".done"
+)
+
+var (
+	// ErrPodControllerNoResult is returned when the result of a pod without result file is requested
+	ErrPodControllerNoResult = errors.New("pod has no result file")
+)
+
+// addResultCollector adds a volume for the result file to all containers and
+// a container keeping it available until the result is collected
+func addResultCollector(spec *v1.PodSpec) {
+	resultFile := filepath.Join(ResultDir, resultFileName)
+	spec.Volumes = append(spec.Volumes, v1.Volume{
+		Name:         resultVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	mount := v1.VolumeMount{Name: resultVolumeName, MountPath: ResultDir}
+	env := v1.EnvVar{Name: output.ResultFileEnv, Value: resultFile}
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, mount)
+		spec.Containers[i].Env = append(spec.Containers[i].Env, env)
+	}
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].VolumeMounts = append(spec.InitContainers[i].VolumeMounts, mount)
+		spec.InitContainers[i].Env = append(spec.InitContainers[i].Env, env)
+	}
+	spec.Containers = append(spec.Containers, v1.Container{
+		Name:            ResultContainerName,
+		Image:           consts.GetKanisterToolsImage(),
+		Command:         []string{"sh", "-c", fmt.Sprintf("until [ -f %s ]; do sleep 1; done", filepath.Join(ResultDir, resultDoneFile))},
+		ImagePullPolicy: v1.PullIfNotPresent,
+		VolumeMounts:    []v1.VolumeMount{mount},
+	})
+}
+
+// collectResult waits until the container terminated and returns the outputs
+// of its result file. The result container exits afterwards, so that the pod
+// can complete.
+func collectResult(ctx context.Context, cli kubernetes.Interface, namespace, podName, container string) (map[string]interface{}, error) {
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		pod, err := cli.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "Failed to get pod %s", podName)
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == container {
+				return cs.State.Terminated != nil, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Container %s of pod %s did not terminate", container, podName)
+	}
+	cmd := fmt.Sprintf("cat %s 2>/dev/null; touch %s", filepath.Join(ResultDir, resultFileName), filepath.Join(ResultDir, resultDoneFile))
+	stdout, stderr, err := Exec(cli, namespace, podName, ResultContainerName, []string{"sh", "-c", cmd}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read result file of pod %s, stderr: %s", podName, stderr)
+	}
+	return output.ParseResult([]byte(stdout))
+}
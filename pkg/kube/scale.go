@@ -0,0 +1,124 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/kanisterio/kanister/pkg/poll"
+)
+
+const scaleSubresource = "scale"
+
+var podGVR = corev1.SchemeGroupVersion.WithResource("pods")
+
+// ScaleReplicas returns the desired replica count of a resource exposing the
+// scale subresource.
+func ScaleReplicas(ctx context.Context, cli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (int32, error) {
+	scale, err := getScale(ctx, cli, gvr, namespace, name)
+	if err != nil {
+		return 0, err
+	}
+	replicas, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid scale of %s %s/%s", gvr.Resource, namespace, name)
+	}
+	return int32(replicas), nil
+}
+
+// ScaleResource sets the replica count of a resource through its scale
+// subresource. If waitForReady is set, it waits until the replica count in
+// the status of the scale subresource matches and, if the scale subresource
+// reports a pod selector, until exactly that many pods are selected and all
+// of them are ready. Without a selector only the replica count is awaited.
+func ScaleResource(ctx context.Context, cli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, replicas int32, waitForReady bool) error {
+	scale, err := getScale(ctx, cli, gvr, namespace, name)
+	if err != nil {
+		return err
+	}
+	if err = unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+		return errors.Wrapf(err, "Invalid scale of %s %s/%s", gvr.Resource, namespace, name)
+	}
+	if _, err = cli.Resource(gvr).Namespace(namespace).Update(ctx, scale, metav1.UpdateOptions{}, scaleSubresource); err != nil {
+		return errors.Wrapf(err, "Could not scale %s{Namespace %s, Name: %s}", gvr.Resource, namespace, name)
+	}
+	if !waitForReady {
+		return nil
+	}
+	err = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		return scaleReady(ctx, cli, gvr, namespace, name, replicas)
+	})
+	return errors.Wrapf(err, "%s %s/%s did not reach %d ready replicas", gvr.Resource, namespace, name, replicas)
+}
+
+// scaleReady returns whether the scale subresource reports the replica count
+// and the pods it selects, if any, are that many and ready. Terminating pods
+// are not ready, so scaling down waits for them to complete.
+func scaleReady(ctx context.Context, cli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string, replicas int32) (bool, error) {
+	scale, err := getScale(ctx, cli, gvr, namespace, name)
+	if err != nil {
+		return false, err
+	}
+	observed, found, err := unstructured.NestedInt64(scale.Object, "status", "replicas")
+	if err != nil || !found || observed != int64(replicas) {
+		return false, nil
+	}
+	selector, _, err := unstructured.NestedString(scale.Object, "status", "selector")
+	if err != nil || selector == "" {
+		return true, nil
+	}
+	pods, err := cli.Resource(podGVR).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return false, errors.Wrapf(err, "Could not list pods of %s{Namespace %s, Name: %s}", gvr.Resource, namespace, name)
+	}
+	if len(pods.Items) != int(replicas) {
+		return false, nil
+	}
+	for _, item := range pods.Items {
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, pod); err != nil {
+			return false, errors.Wrapf(err, "Invalid pod %s/%s", namespace, item.GetName())
+		}
+		if pod.DeletionTimestamp != nil || !podReady(pod) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func getScale(ctx context.Context, cli dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	scale, err := cli.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, scaleSubresource)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not get scale of %s{Namespace %s, Name: %s}", gvr.Resource, namespace, name)
+	}
+	return scale, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"context"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynfake "k8s.io/client-go/dynamic/fake"
+)
+
+type ScaleSuite struct{}
+
+var _ = Suite(&ScaleSuite{})
+
+func (s *ScaleSuite) TestScaleResource(c *C) {
+	ctx := context.Background()
+	gvr := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "ns"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+	}}
+	cli := dynfake.NewSimpleDynamicClient(runtime.NewScheme(), rollout)
+
+	replicas, err := ScaleReplicas(ctx, cli, gvr, "ns", "app")
+	c.Assert(err, IsNil)
+	c.Assert(replicas, Equals, int32(3))
+
+	c.Assert(ScaleResource(ctx, cli, gvr, "ns", "app", 0, false), IsNil)
+	replicas, err = ScaleReplicas(ctx, cli, gvr, "ns", "app")
+	c.Assert(err, IsNil)
+	c.Assert(replicas, Equals, int32(0))
+
+	_, err = ScaleReplicas(ctx, cli, gvr, "ns", "missing")
+	c.Assert(err, NotNil)
+}
+
+func (s *ScaleSuite) TestScaleResourceWaitForReady(c *C) {
+	gvr := schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "ns"},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+	}}
+	pod := func(name string, ready bool) *unstructured.Unstructured {
+		status := "False"
+		if ready {
+			status = "True"
+		}
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "ns", "labels": map[string]interface{}{"app": "web"}},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": status}},
+			},
+		}}
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		gvr:    "RolloutList",
+		podGVR: "PodList",
+	}
+	for _, tc := range []struct {
+		status  map[string]interface{}
+		pods    []runtime.Object
+		checker Checker
+	}{
+		{
+			// A scale without status is not ready
+			status:  nil,
+			checker: NotNil,
+		},
+		{
+			status:  map[string]interface{}{"replicas": int64(2)},
+			checker: IsNil,
+		},
+		{
+			status:  map[string]interface{}{"replicas": int64(2), "selector": "app=web"},
+			pods:    []runtime.Object{pod("web-0", true), pod("web-1", false)},
+			checker: NotNil,
+		},
+		{
+			status:  map[string]interface{}{"replicas": int64(2), "selector": "app=web"},
+			pods:    []runtime.Object{pod("web-0", true)},
+			checker: NotNil,
+		},
+		{
+			status:  map[string]interface{}{"replicas": int64(2), "selector": "app=web"},
+			pods:    []runtime.Object{pod("web-0", true), pod("web-1", true)},
+			checker: IsNil,
+		},
+	} {
+		obj := rollout.DeepCopy()
+		if tc.status != nil {
+			obj.Object["status"] = tc.status
+		}
+		cli := dynfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, append(tc.pods, obj)...)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		err := ScaleResource(ctx, cli, gvr, "ns", "app", 2, true)
+		cancel()
+		c.Check(err, tc.checker)
+	}
+}
@@ -302,6 +302,42 @@ func DeletePVC(cli kubernetes.Interface, namespace, pvcName string) error {
 	})
 }
 
+// WaitOnPVCsDetached waits until the PVs bound to the given PVCs are not
+// attached to any node, i.e. until they have no VolumeAttachment.
+func WaitOnPVCsDetached(ctx context.Context, cli kubernetes.Interface, namespace string, pvcNames []string) error {
+	pvs := make(map[string]string, len(pvcNames))
+	for _, name := range pvcNames {
+		pvc, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to get PVC. Namespace: %s, Name: %s", namespace, name)
+		}
+		// An unbound PVC cannot be attached
+		if pvc.Spec.VolumeName != "" {
+			pvs[pvc.Spec.VolumeName] = name
+		}
+	}
+	if len(pvs) == 0 {
+		return nil
+	}
+	var attached []string
+	err := poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		vas, err := cli.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "Failed to list VolumeAttachments")
+		}
+		attached = nil
+		for _, va := range vas.Items {
+			if pv := va.Spec.Source.PersistentVolumeName; pv != nil {
+				if pvc, ok := pvs[*pv]; ok {
+					attached = append(attached, pvc)
+				}
+			}
+		}
+		return len(attached) == 0, nil
+	})
+	return errors.Wrapf(err, "PVCs %v are still attached", attached)
+}
+
 var labelDenyList = map[string]struct{}{
 	"chart":    {},
 	"heritage": {},
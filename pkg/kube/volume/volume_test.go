@@ -20,9 +20,11 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	. "gopkg.in/check.v1"
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -183,6 +185,32 @@ func (s *TestVolSuite) TestGetPVCRestoreSize(c *C) {
 	}
 }
 
+func (s *TestVolSuite) TestWaitOnPVCsDetached(c *C) {
+	pv := "pv-data"
+	cli := fake.NewSimpleClientset(
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "ns"},
+			Spec:       v1.PersistentVolumeClaimSpec{VolumeName: pv},
+		},
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "unbound", Namespace: "ns"},
+		},
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "va"},
+			Spec:       storagev1.VolumeAttachmentSpec{Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: &pv}},
+		},
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	c.Assert(WaitOnPVCsDetached(ctx, cli, "ns", []string{"data", "unbound"}), NotNil)
+	c.Assert(WaitOnPVCsDetached(ctx, cli, "ns", []string{"unbound"}), IsNil)
+	c.Assert(WaitOnPVCsDetached(ctx, cli, "ns", []string{"missing"}), NotNil)
+
+	err := cli.StorageV1().VolumeAttachments().Delete(context.Background(), "va", metav1.DeleteOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(WaitOnPVCsDetached(context.Background(), cli, "ns", []string{"data"}), IsNil)
+}
+
 func (s *TestVolSuite) fakeUnstructuredSnasphotWSize(vsName, namespace, size string) *unstructured.Unstructured {
 	gvr := schema.GroupVersionResource{Group: "snapshot.storage.k8s.io", Version: "v1", Resource: "volumesnapshots"}
 	Object := map[string]interface{}{
@@ -19,6 +19,7 @@ import (
 	"context"
 	"io"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -50,11 +51,17 @@ func Write(ctx context.Context, in io.Reader, profile param.Profile, suffix stri
 	if err != nil {
 		return err
 	}
+	if err := checkObjectLock(osType, profile.Location.ObjectLock); err != nil {
+		return err
+	}
 	path := filepath.Join(
 		profile.Location.Prefix,
 		suffix,
 	)
-	return writeData(ctx, osType, profile, in, path)
+	if err := writeData(ctx, osType, profile, in, path); err != nil {
+		return err
+	}
+	return putObjectRetention(ctx, osType, profile, path)
 }
 
 // Read pipes data from `in` into the location specified by `profile` and `suffix`.
@@ -161,7 +168,65 @@ func deleteData(ctx context.Context, pType objectstore.ProviderType, profile par
 	if err != nil {
 		return err
 	}
-	return bucket.DeleteAllWithPrefix(ctx, path)
+	err = bucket.DeleteAllWithPrefix(ctx, path)
+	if objectstore.IsObjectLockedError(err) {
+		return errors.Wrapf(err, "objects under '%s' are protected by object lock and cannot be deleted until their retention period expires", path)
+	}
+	return err
+}
+
+// checkObjectLock verifies that the object lock settings of a Location can
+// be applied to the given provider type.
+func checkObjectLock(pType objectstore.ProviderType, ol *crv1alpha1.ObjectLock) error {
+	if ol == nil {
+		return nil
+	}
+	if pType != objectstore.ProviderTypeS3 {
+		return errors.Errorf("Object lock is not supported for provider type '%s'", pType)
+	}
+	if _, err := objectLockMode(ol.Mode); err != nil {
+		return err
+	}
+	if ol.RetentionPeriod.Duration <= 0 {
+		return errors.Errorf("Invalid object lock retention period '%s'", ol.RetentionPeriod.Duration)
+	}
+	return nil
+}
+
+func objectLockMode(mode crv1alpha1.ObjectLockMode) (objectstore.ObjectLockMode, error) {
+	switch mode {
+	case crv1alpha1.ObjectLockModeGovernance:
+		return objectstore.ObjectLockModeGovernance, nil
+	case crv1alpha1.ObjectLockModeCompliance:
+		return objectstore.ObjectLockModeCompliance, nil
+	default:
+		return "", errors.Errorf("Unsupported object lock mode '%s'", mode)
+	}
+}
+
+// putObjectRetention protects the object at path from deletion for the
+// retention period configured in the profile, if any.
+func putObjectRetention(ctx context.Context, pType objectstore.ProviderType, profile param.Profile, path string) error {
+	ol := profile.Location.ObjectLock
+	if ol == nil {
+		return nil
+	}
+	mode, err := objectLockMode(ol.Mode)
+	if err != nil {
+		return err
+	}
+	secret, err := getOSSecret(ctx, pType, profile.Credential)
+	if err != nil {
+		return err
+	}
+	r := objectstore.ObjectRetention{
+		Mode:        mode,
+		RetainUntil: time.Now().Add(ol.RetentionPeriod.Duration),
+	}
+	if err := objectstore.PutObjectRetention(ctx, providerConfig(pType, profile), *secret, profile.Location.Bucket, path, r); err != nil {
+		return errors.Wrapf(err, "failed to lock contents in bucket '%s'", profile.Location.Bucket)
+	}
+	return nil
 }
 
 func getProviderType(lType crv1alpha1.LocationType) (objectstore.ProviderType, error) {
@@ -177,13 +242,17 @@ func getProviderType(lType crv1alpha1.LocationType) (objectstore.ProviderType, e
 	}
 }
 
-func getBucket(ctx context.Context, pType objectstore.ProviderType, profile param.Profile) (objectstore.Bucket, error) {
-	pc := objectstore.ProviderConfig{
+func providerConfig(pType objectstore.ProviderType, profile param.Profile) objectstore.ProviderConfig {
+	return objectstore.ProviderConfig{
 		Type:          pType,
 		Endpoint:      profile.Location.Endpoint,
 		Region:        profile.Location.Region,
 		SkipSSLVerify: profile.SkipSSLVerify,
 	}
+}
+
+func getBucket(ctx context.Context, pType objectstore.ProviderType, profile param.Profile) (objectstore.Bucket, error) {
+	pc := providerConfig(pType, profile)
 	secret, err := getOSSecret(ctx, pType, profile.Credential)
 	if err != nil {
 		return nil, err
@@ -26,6 +26,7 @@ import (
 
 	. "gopkg.in/check.v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/blockstorage"
@@ -317,3 +318,54 @@ func (s *LocationSuite) TestGetAzureSecret(c *C) {
 		}
 	}
 }
+
+type ObjectLockSuite struct{}
+
+var _ = Suite(&ObjectLockSuite{})
+
+func (s *ObjectLockSuite) TestCheckObjectLock(c *C) {
+	for _, tc := range []struct {
+		pType   objectstore.ProviderType
+		ol      *crv1alpha1.ObjectLock
+		checker Checker
+	}{
+		{
+			pType:   objectstore.ProviderTypeGCS,
+			ol:      nil,
+			checker: IsNil,
+		},
+		{
+			pType: objectstore.ProviderTypeS3,
+			ol: &crv1alpha1.ObjectLock{
+				Mode:            crv1alpha1.ObjectLockModeCompliance,
+				RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+			},
+			checker: IsNil,
+		},
+		{
+			pType: objectstore.ProviderTypeAzure,
+			ol: &crv1alpha1.ObjectLock{
+				Mode:            crv1alpha1.ObjectLockModeGovernance,
+				RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+			},
+			checker: NotNil,
+		},
+		{
+			pType: objectstore.ProviderTypeS3,
+			ol: &crv1alpha1.ObjectLock{
+				Mode:            "legal-hold",
+				RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+			},
+			checker: NotNil,
+		},
+		{
+			pType: objectstore.ProviderTypeS3,
+			ol: &crv1alpha1.ObjectLock{
+				Mode: crv1alpha1.ObjectLockModeGovernance,
+			},
+			checker: NotNil,
+		},
+	} {
+		c.Check(checkObjectLock(tc.pType, tc.ol), tc.checker)
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	kaws "github.com/kanisterio/kanister/pkg/aws"
 	"github.com/pkg/errors"
@@ -33,6 +34,13 @@ const (
 	gcsS3NotFound  = "not found"
 )
 
+// Messages returned by S3 and S3 compatible stores when an object protected
+// by Object Lock is deleted or overwritten
+var objectLockedMessages = []string{
+	"protected by object lock",
+	"worm protected",
+}
+
 func IsBucketNotFoundError(err error) bool {
 	if err == nil {
 		return false
@@ -44,6 +52,56 @@ func IsBucketNotFoundError(err error) bool {
 	return strings.Contains(err.Error(), gcsS3NotFound)
 }
 
+// IsObjectLockedError returns true if the error was caused by an attempt to
+// delete or overwrite an object that is protected by S3 Object Lock.
+func IsObjectLockedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return IsObjectLockedMessage(err.Error())
+}
+
+// IsObjectLockedMessage returns true if msg, e.g. the output of a data mover
+// operating on the bucket, reports objects protected by S3 Object Lock.
+func IsObjectLockedMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, m := range objectLockedMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// PutObjectRetention places an S3 Object Lock retention on the current
+// version of the named object. The bucket must have Object Lock enabled.
+func PutObjectRetention(ctx context.Context, pc ProviderConfig, s Secret, bucketName, objectName string, r ObjectRetention) error {
+	if s.Aws == nil {
+		return errors.New("AWS Secret required to set object retention")
+	}
+	cfg, err := s3BucketConfig(ctx, pc, &s, bucketName)
+	if err != nil {
+		return err
+	}
+	c, reg, err := awsConfig(ctx, cfg, *s.Aws)
+	if err != nil {
+		return err
+	}
+	sess, err := session.NewSession(c)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create session, region = %s", reg)
+	}
+	_, err = s3.New(sess).PutObjectRetentionWithContext(ctx, &s3.PutObjectRetentionInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(cloudName(objectName)),
+		Retention: &s3.ObjectLockRetention{
+			Mode:            aws.String(string(r.Mode)),
+			RetainUntilDate: aws.Time(r.RetainUntil),
+		},
+	})
+	return errors.Wrapf(err, "failed to set retention on object %s", objectName)
+}
+
 func awsConfig(ctx context.Context, pc ProviderConfig, s SecretAws) (*aws.Config, string, error) {
 	c := map[string]string{
 		kaws.AccessKeyID:     s.AccessKeyID,
@@ -0,0 +1,55 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+)
+
+type AWSSuite struct{}
+
+var _ = Suite(&AWSSuite{})
+
+func (s *AWSSuite) TestIsObjectLockedError(c *C) {
+	for _, tc := range []struct {
+		err    error
+		locked bool
+	}{
+		{
+			err:    nil,
+			locked: false,
+		},
+		{
+			err:    errors.New("some error"),
+			locked: false,
+		},
+		{
+			err:    errors.Wrap(awserr.New("AccessDenied", "Access Denied because object protected by object lock.", nil), "Failed to delete item"),
+			locked: true,
+		},
+		{
+			err:    awserr.New("AccessDenied", "Object is WORM protected and cannot be overwritten", nil),
+			locked: true,
+		},
+		{
+			err:    awserr.New("AccessDenied", "Access Denied", nil),
+			locked: false,
+		},
+	} {
+		c.Check(IsObjectLockedError(tc.err), Equals, tc.locked)
+	}
+}
@@ -40,3 +40,13 @@ const (
 	// SecretTypeAzStorageAccount captures enum value "AzStorageAccount"
 	SecretTypeAzStorageAccount SecretType = "AzStorageAccount"
 )
+
+// ObjectLockMode enum for S3 Object Lock retention modes
+type ObjectLockMode string
+
+const (
+	// ObjectLockModeGovernance captures enum value "GOVERNANCE"
+	ObjectLockModeGovernance ObjectLockMode = "GOVERNANCE"
+	// ObjectLockModeCompliance captures enum value "COMPLIANCE"
+	ObjectLockModeCompliance ObjectLockMode = "COMPLIANCE"
+)
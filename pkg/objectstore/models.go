@@ -14,6 +14,8 @@
 
 package objectstore
 
+import "time"
+
 // ProviderConfig describes the config for the object store (which provider to use)
 type ProviderConfig struct {
 	// object store type
@@ -68,3 +70,11 @@ type Secret struct {
 	// type
 	Type SecretType
 }
+
+// ObjectRetention describes the S3 Object Lock retention of an object
+type ObjectRetention struct {
+	// retention mode
+	Mode ObjectLockMode
+	// time until which the object cannot be deleted or overwritten
+	RetainUntil time.Time
+}
@@ -16,6 +16,8 @@ package output
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -82,3 +84,25 @@ func (s *OutputSuite) TestParseNoOutput(c *C) {
 		c.Assert(o, IsNil)
 	}
 }
+
+func (s *OutputSuite) TestWriteResult(c *C) {
+	path := filepath.Join(c.MkDir(), "result.json")
+	c.Assert(WriteResult(path, "foo", "bar"), IsNil)
+	c.Assert(WriteResult(path, "nested", map[string]interface{}{"list": []interface{}{"a", "b"}}), IsNil)
+	c.Assert(WriteResult(path, "invalid-key", "bar"), NotNil)
+
+	data, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	result, err := ParseResult(data)
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, map[string]interface{}{
+		"foo":    "bar",
+		"nested": map[string]interface{}{"list": []interface{}{"a", "b"}},
+	})
+
+	result, err = ParseResult(nil)
+	c.Assert(err, IsNil)
+	c.Assert(result, HasLen, 0)
+	_, err = ParseResult([]byte("###Phase-output###"))
+	c.Assert(err, NotNil)
+}
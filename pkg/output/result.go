@@ -0,0 +1,77 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// ResultFileEnv is set to the path of the result file in containers whose
+// result file is collected as phase output
+const ResultFileEnv = "KANISTER_RESULT_FILE"
+
+// WriteResult sets key to value in the JSON result file at path. Values are
+// not limited to strings, so nested outputs can be written.
+func WriteResult(path, key string, value interface{}) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	result := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return errors.Wrapf(err, "Failed to read result file %s", path)
+	default:
+		if result, err = ParseResult(data); err != nil {
+			return err
+		}
+	}
+	result[key] = value
+	if data, err = json.Marshal(result); err != nil {
+		return errors.Wrap(err, "Failed to marshal result")
+	}
+	// Rename the file into place so that partially written results are never collected
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create result file %s", path)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrapf(err, "Failed to write result file %s", path)
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrapf(err, "Failed to write result file %s", path)
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "Failed to write result file %s", path)
+}
+
+// ParseResult parses the contents of a result file. An empty result file has
+// no outputs.
+func ParseResult(data []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if len(data) == 0 {
+		return result, nil
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal result file")
+	}
+	return result, nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -48,6 +49,7 @@ type TemplateParams struct {
 	StatefulSet      *StatefulSetParams
 	DeploymentConfig *DeploymentConfigParams
 	Deployment       *DeploymentParams
+	Workload         *WorkloadParams
 	PVC              *PVCParams
 	Namespace        *NamespaceParams
 	ArtifactsIn      map[string]crv1alpha1.Artifact
@@ -174,8 +176,14 @@ const (
 	SecretKind           = "secret"
 )
 
-// New function fetches and returns the desired params
-func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, crCli versioned.Interface, osCli osversioned.Interface, as crv1alpha1.ActionSpec) (*TemplateParams, error) {
+// New function fetches and returns the desired params. The options of the
+// ActionSpec are checked against the parameters declared by the action, if any,
+// and defaulted.
+func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface, crCli versioned.Interface, osCli osversioned.Interface, as crv1alpha1.ActionSpec, params ...crv1alpha1.BlueprintParameter) (*TemplateParams, error) {
+	options, err := ApplyParameters(as.Options, params)
+	if err != nil {
+		return nil, err
+	}
 	secrets, err := fetchSecrets(ctx, cli, as.Secrets)
 	if err != nil {
 		return nil, err
@@ -200,10 +208,11 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		Profile:          prof,
 		RepositoryServer: repoServer,
 		Time:             now.Format(timeFormat),
-		Options:          as.Options,
+		Options:          options,
 		PodOverride:      as.PodOverride,
 	}
 	var gvr schema.GroupVersionResource
+	var generic bool
 	namespace := as.Object.Namespace
 	switch strings.ToLower(as.Object.Kind) {
 	case StatefulSetKind:
@@ -240,6 +249,7 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		// `Namespace` is a global resource
 		namespace = ""
 	default:
+		generic = true
 		gvr = schema.GroupVersionResource{
 			Group:    as.Object.Group,
 			Version:  as.Object.APIVersion,
@@ -251,10 +261,24 @@ func New(ctx context.Context, cli kubernetes.Interface, dynCli dynamic.Interface
 		return nil, errors.Wrapf(err, "could not fetch object name: %s, namespace: %s, group: %s, version: %s, resource: %s", as.Object.Name, namespace, gvr.Group, gvr.Version, gvr.Resource)
 	}
 	tp.Object = u.UnstructuredContent()
+	// Objects of other kinds can be workloads, too
+	if uu, ok := u.(*unstructured.Unstructured); ok && generic {
+		if tp.Workload, err = resolveWorkload(ctx, cli, uu); err != nil {
+			return nil, err
+		}
+		if tp.Workload != nil {
+			tp.Workload.addToObject(tp.Object)
+		}
+	}
 
 	return &tp, nil
 }
 
+// FetchProfile fetches the Profile referenced by ref along with its credentials.
+func FetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*Profile, error) {
+	return fetchProfile(ctx, cli, crCli, ref)
+}
+
 func fetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*Profile, error) {
 	if ref == nil {
 		log.Debug().Print("Executing the action without a profile")
@@ -0,0 +1,127 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"k8s.io/utils/strings/slices"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// ApplyParameters checks the options of an ActionSet against the parameters
+// declared by the BlueprintAction and returns them with the defaults of the
+// parameters that are not set. Any options are accepted if the action does
+// not declare parameters.
+func ApplyParameters(options map[string]string, params []crv1alpha1.BlueprintParameter) (map[string]string, error) {
+	if len(params) == 0 {
+		return options, nil
+	}
+	declared := make(map[string]crv1alpha1.BlueprintParameter, len(params))
+	for _, p := range params {
+		declared[p.Name] = p
+	}
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make(map[string]string, len(params))
+	for _, name := range names {
+		p, ok := declared[name]
+		if !ok {
+			return nil, errors.Errorf("Option %s is not a parameter of the action, expected one of %v", name, ParameterNames(params))
+		}
+		if err := checkParameterValue(p, options[name]); err != nil {
+			return nil, errors.Wrapf(err, "Invalid option %s", name)
+		}
+		out[name] = options[name]
+	}
+	for _, p := range params {
+		if _, ok := out[p.Name]; ok {
+			continue
+		}
+		switch {
+		case p.Default != "":
+			out[p.Name] = p.Default
+		case p.Required:
+			return nil, errors.Errorf("Required option %s is not set", p.Name)
+		}
+	}
+	return out, nil
+}
+
+// ValidateParameters checks the declarations of the parameters of a
+// BlueprintAction.
+func ValidateParameters(params []crv1alpha1.BlueprintParameter) error {
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		if p.Name == "" {
+			return errors.New("Parameter name cannot be empty")
+		}
+		if seen[p.Name] {
+			return errors.Errorf("Duplicated parameter %s", p.Name)
+		}
+		seen[p.Name] = true
+		switch p.Type {
+		case "", crv1alpha1.ParameterTypeString, crv1alpha1.ParameterTypeInteger, crv1alpha1.ParameterTypeBoolean:
+		default:
+			return errors.Errorf("Unsupported type %s of parameter %s", p.Type, p.Name)
+		}
+		if p.Required && p.Default != "" {
+			return errors.Errorf("Parameter %s cannot be required and have a default", p.Name)
+		}
+		for _, v := range p.Enum {
+			if err := checkParameterValue(crv1alpha1.BlueprintParameter{Type: p.Type}, v); err != nil {
+				return errors.Wrapf(err, "Invalid allowed value of parameter %s", p.Name)
+			}
+		}
+		if p.Default != "" {
+			if err := checkParameterValue(p, p.Default); err != nil {
+				return errors.Wrapf(err, "Invalid default of parameter %s", p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// ParameterNames returns the names of the parameters.
+func ParameterNames(params []crv1alpha1.BlueprintParameter) []string {
+	names := make([]string, 0, len(params))
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func checkParameterValue(p crv1alpha1.BlueprintParameter, val string) error {
+	switch p.Type {
+	case crv1alpha1.ParameterTypeInteger:
+		if _, err := strconv.ParseInt(val, 10, 64); err != nil {
+			return errors.Errorf("expected an integer, got %q", val)
+		}
+	case crv1alpha1.ParameterTypeBoolean:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return errors.Errorf("expected a boolean, got %q", val)
+		}
+	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, val) {
+		return errors.Errorf("expected one of %v, got %q", p.Enum, val)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type ParametersSuite struct{}
+
+var _ = Suite(&ParametersSuite{})
+
+var testParameters = []crv1alpha1.BlueprintParameter{
+	{Name: "retention", Type: crv1alpha1.ParameterTypeInteger, Default: "7"},
+	{Name: "compress", Type: crv1alpha1.ParameterTypeBoolean},
+	{Name: "mode", Enum: []string{"full", "incremental"}, Required: true},
+}
+
+func (s *ParametersSuite) TestApplyParameters(c *C) {
+	for _, tc := range []struct {
+		options  map[string]string
+		params   []crv1alpha1.BlueprintParameter
+		expected map[string]string
+		err      string
+	}{
+		{
+			options:  map[string]string{"anything": "goes"},
+			expected: map[string]string{"anything": "goes"},
+		},
+		{
+			options:  map[string]string{"mode": "full"},
+			params:   testParameters,
+			expected: map[string]string{"mode": "full", "retention": "7"},
+		},
+		{
+			options:  map[string]string{"mode": "incremental", "retention": "30", "compress": "true"},
+			params:   testParameters,
+			expected: map[string]string{"mode": "incremental", "retention": "30", "compress": "true"},
+		},
+		{
+			options: map[string]string{"mode": "full", "retension": "30"},
+			params:  testParameters,
+			err:     `Option retension is not a parameter of the action, expected one of \[retention compress mode\]`,
+		},
+		{
+			options: map[string]string{"mode": "full", "retention": "a week"},
+			params:  testParameters,
+			err:     `Invalid option retention: expected an integer, got "a week"`,
+		},
+		{
+			options: map[string]string{"mode": "full", "compress": "maybe"},
+			params:  testParameters,
+			err:     `Invalid option compress: expected a boolean, got "maybe"`,
+		},
+		{
+			options: map[string]string{"mode": "differential"},
+			params:  testParameters,
+			err:     `Invalid option mode: expected one of \[full incremental\], got "differential"`,
+		},
+		{
+			params: testParameters,
+			err:    "Required option mode is not set",
+		},
+	} {
+		options, err := ApplyParameters(tc.options, tc.params)
+		if tc.err != "" {
+			c.Assert(err, ErrorMatches, tc.err)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(options, DeepEquals, tc.expected)
+	}
+}
+
+func (s *ParametersSuite) TestValidateParameters(c *C) {
+	c.Assert(ValidateParameters(testParameters), IsNil)
+	for _, tc := range []struct {
+		param crv1alpha1.BlueprintParameter
+		err   string
+	}{
+		{
+			param: crv1alpha1.BlueprintParameter{},
+			err:   "Parameter name cannot be empty",
+		},
+		{
+			param: crv1alpha1.BlueprintParameter{Name: "mode"},
+			err:   "Duplicated parameter mode",
+		},
+		{
+			param: crv1alpha1.BlueprintParameter{Name: "size", Type: "float"},
+			err:   "Unsupported type float of parameter size",
+		},
+		{
+			param: crv1alpha1.BlueprintParameter{Name: "size", Required: true, Default: "1"},
+			err:   "Parameter size cannot be required and have a default",
+		},
+		{
+			param: crv1alpha1.BlueprintParameter{Name: "size", Type: crv1alpha1.ParameterTypeInteger, Enum: []string{"1", "many"}},
+			err:   `Invalid allowed value of parameter size: expected an integer, got "many"`,
+		},
+		{
+			param: crv1alpha1.BlueprintParameter{Name: "size", Enum: []string{"small", "large"}, Default: "medium"},
+			err:   `Invalid default of parameter size: expected one of \[small large\], got "medium"`,
+		},
+	} {
+		err := ValidateParameters(append(testParameters[:len(testParameters):len(testParameters)], tc.param))
+		c.Assert(err, ErrorMatches, tc.err)
+	}
+}
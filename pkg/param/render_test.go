@@ -118,6 +118,22 @@ func (s *RenderSuite) TestRender(c *C) {
 			out:     map[interface{}]interface{}{"HELLO": []interface{}{"HELLO"}},
 			checker: IsNil,
 		},
+		{
+			// Kanister template functions are available
+			arg: `{{ backupPath .Time "backups" (jsonPath .Object "{.metadata.name}") }}`,
+			tp: TemplateParams{
+				Time:   "2023-01-02T15:04:05Z",
+				Object: map[string]interface{}{"metadata": map[string]interface{}{"name": "db"}},
+			},
+			out:     "backups/db/2023-01-02T15-04-05Z",
+			checker: IsNil,
+		},
+		{
+			// Functions accessing the environment are not available
+			arg:     `{{ env "HOME" }}`,
+			tp:      TemplateParams{},
+			checker: NotNil,
+		},
 		{
 			// Render should fail if referenced key doesn't exist
 			arg: "{{ .Options.hello }}",
@@ -0,0 +1,218 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kanisterio/kanister/pkg/kube"
+)
+
+// WorkloadParams are params for workloads resolved by a WorkloadResolver.
+// They are also available in the Object params as `.Object.Pods`,
+// `.Object.Containers` and `.Object.PersistentVolumeClaims`.
+type WorkloadParams struct {
+	Name                   string
+	Namespace              string
+	Pods                   []string
+	Containers             [][]string
+	PersistentVolumeClaims map[string]map[string]string
+}
+
+// WorkloadResolver resolves the params of a workload.
+type WorkloadResolver func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) (*WorkloadParams, error)
+
+// PodsResolver returns the pods of a workload.
+type PodsResolver func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error)
+
+var (
+	workloadResolversMu sync.RWMutex
+	workloadResolvers   = map[schema.GroupVersionKind]WorkloadResolver{}
+)
+
+func init() {
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "apps", Kind: "DaemonSet"}, NewWorkloadResolver(ownedPods))
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "apps", Kind: "ReplicaSet"}, NewWorkloadResolver(ownedPods))
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "batch", Kind: "Job"}, NewWorkloadResolver(ownedPods))
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "batch", Kind: "CronJob"}, NewWorkloadResolver(cronJobPods))
+	// Operators label the pods of their CRs with the CR name
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "postgresql.cnpg.io", Kind: "Cluster"}, NewWorkloadResolver(labelPods("cnpg.io/cluster")))
+	RegisterWorkloadResolver(schema.GroupVersionKind{Group: "kafka.strimzi.io", Kind: "Kafka"}, NewWorkloadResolver(labelPods("strimzi.io/cluster")))
+}
+
+// RegisterWorkloadResolver registers the resolver of the workloads of the
+// given kind. An empty version matches all the versions of the kind.
+func RegisterWorkloadResolver(gvk schema.GroupVersionKind, r WorkloadResolver) {
+	workloadResolversMu.Lock()
+	defer workloadResolversMu.Unlock()
+	workloadResolvers[gvk] = r
+}
+
+// NewWorkloadResolver returns a resolver of the workload params from the
+// pods of the workload.
+func NewWorkloadResolver(podsResolver PodsResolver) WorkloadResolver {
+	return func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) (*WorkloadParams, error) {
+		pods, err := podsResolver(ctx, cli, obj)
+		if err != nil {
+			return nil, err
+		}
+		return workloadParams(obj, pods), nil
+	}
+}
+
+func workloadResolver(gvk schema.GroupVersionKind) WorkloadResolver {
+	workloadResolversMu.RLock()
+	defer workloadResolversMu.RUnlock()
+	if r, ok := workloadResolvers[gvk]; ok {
+		return r
+	}
+	gvk.Version = ""
+	return workloadResolvers[gvk]
+}
+
+// resolveWorkload returns the params of the object if it is a namespaced
+// workload, i.e. if a resolver is registered for its kind or it declares the
+// pod selector of the scale subresource in `status.selector`.
+func resolveWorkload(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) (*WorkloadParams, error) {
+	// Pods of cluster-scoped objects would be looked up in all namespaces
+	if obj.GetNamespace() == "" {
+		return nil, nil
+	}
+	r := workloadResolver(obj.GroupVersionKind())
+	if r == nil {
+		selector, err := podSelector(obj)
+		if err != nil || selector == nil {
+			return nil, err
+		}
+		r = NewWorkloadResolver(func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error) {
+			return selectedPods(ctx, cli, obj.GetNamespace(), selector)
+		})
+	}
+	wp, err := r(ctx, cli, obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to resolve workload %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return wp, nil
+}
+
+// podSelector returns the pod selector the object declares for the scale
+// subresource in `status.selector`. Other selectors, like the one in
+// `spec.selector`, do not always select pods and are ignored.
+func podSelector(obj *unstructured.Unstructured) (labels.Selector, error) {
+	s, ok, _ := unstructured.NestedString(obj.Object, "status", "selector")
+	if !ok || s == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid pod selector")
+	}
+	return selector, nil
+}
+
+func workloadParams(obj *unstructured.Unstructured, pods []v1.Pod) *WorkloadParams {
+	wp := &WorkloadParams{
+		Name:                   obj.GetName(),
+		Namespace:              obj.GetNamespace(),
+		Pods:                   []string{},
+		Containers:             [][]string{},
+		PersistentVolumeClaims: make(map[string]map[string]string),
+	}
+	for _, p := range pods {
+		wp.Pods = append(wp.Pods, p.Name)
+		wp.Containers = append(wp.Containers, containerNames(p))
+		volToPvc := make(map[string]string)
+		for _, v := range p.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil {
+				volToPvc[v.Name] = v.PersistentVolumeClaim.ClaimName
+			}
+		}
+		if pvcToMountPath := volumes(p, volToPvc); len(pvcToMountPath) > 0 {
+			wp.PersistentVolumeClaims[p.Name] = pvcToMountPath
+		}
+	}
+	return wp
+}
+
+// addToObject makes the workload params available in the Object params
+func (wp *WorkloadParams) addToObject(obj map[string]interface{}) {
+	obj["Pods"] = wp.Pods
+	obj["Containers"] = wp.Containers
+	obj["PersistentVolumeClaims"] = wp.PersistentVolumeClaims
+}
+
+func ownedPods(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error) {
+	pods, _, err := kube.FetchPods(cli, obj.GetNamespace(), obj.GetUID())
+	return pods, err
+}
+
+func cronJobPods(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error) {
+	jobs, err := cli.BatchV1().Jobs(obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list Jobs of CronJob %s", obj.GetName())
+	}
+	var pods []v1.Pod
+	for _, j := range jobs.Items {
+		if !ownedBy(j.OwnerReferences, obj.GetUID()) {
+			continue
+		}
+		jobPods, _, err := kube.FetchPods(cli, obj.GetNamespace(), j.UID)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, jobPods...)
+	}
+	return pods, nil
+}
+
+func labelPods(label string) PodsResolver {
+	return func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error) {
+		return selectedPods(ctx, cli, obj.GetNamespace(), labels.SelectorFromSet(labels.Set{label: obj.GetName()}))
+	}
+}
+
+// selectedPods returns the running pods matching the selector
+func selectedPods(ctx context.Context, cli kubernetes.Interface, namespace string, selector labels.Selector) ([]v1.Pod, error) {
+	pods, err := cli.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to list pods matching %s", selector)
+	}
+	var running []v1.Pod
+	for _, p := range pods.Items {
+		if p.Status.Phase == v1.PodRunning {
+			running = append(running, p)
+		}
+	}
+	return running, nil
+}
+
+func ownedBy(refs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package param
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakedyncli "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
+)
+
+type WorkloadSuite struct{}
+
+var _ = Suite(&WorkloadSuite{})
+
+func workloadPod(name string, labels map[string]string, owner types.UID) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{
+				Name:         "app",
+				VolumeMounts: []v1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}},
+			Volumes: []v1.Volume{{
+				Name:         "data",
+				VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: name + "-data"}},
+			}},
+		},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			ContainerStatuses: []v1.ContainerStatus{{Name: "app"}},
+		},
+	}
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{UID: owner}}
+	}
+	return pod
+}
+
+func workloadObject(apiVersion, kind string, uid types.UID, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "ns", "uid": string(uid)},
+	}
+	for k, v := range fields {
+		obj[k] = v
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func (s *WorkloadSuite) TestResolveWorkload(c *C) {
+	ctx := context.Background()
+	cli := fake.NewSimpleClientset(
+		workloadPod("ds-pod", nil, "ds-uid"),
+		workloadPod("pg-1", map[string]string{"cnpg.io/cluster": "app"}, ""),
+		workloadPod("selected", map[string]string{"app": "app"}, ""),
+	)
+	for _, tc := range []struct {
+		obj  *unstructured.Unstructured
+		pods []string
+	}{
+		{
+			obj:  workloadObject("apps/v1", "DaemonSet", "ds-uid", nil),
+			pods: []string{"ds-pod"},
+		},
+		{
+			obj:  workloadObject("postgresql.cnpg.io/v1", "Cluster", "", nil),
+			pods: []string{"pg-1"},
+		},
+		{
+			obj:  workloadObject("example.io/v1", "App", "", map[string]interface{}{"status": map[string]interface{}{"selector": "app=app"}}),
+			pods: []string{"selected"},
+		},
+	} {
+		wp, err := resolveWorkload(ctx, cli, tc.obj)
+		c.Assert(err, IsNil)
+		c.Assert(wp, NotNil)
+		c.Assert(wp.Name, Equals, "app")
+		c.Assert(wp.Pods, DeepEquals, tc.pods)
+		c.Assert(wp.Containers, DeepEquals, [][]string{{"app"}})
+		c.Assert(wp.PersistentVolumeClaims, DeepEquals, map[string]map[string]string{
+			tc.pods[0]: {tc.pods[0] + "-data": "/data"},
+		})
+	}
+
+	clusterScoped := workloadObject("example.io/v1", "App", "", map[string]interface{}{"status": map[string]interface{}{"selector": "app=app"}})
+	clusterScoped.SetNamespace("")
+	for _, obj := range []*unstructured.Unstructured{
+		workloadObject("v1", "ServiceAccount", "", nil),
+		// Only the selector of the scale subresource selects pods
+		workloadObject("v1", "Service", "", map[string]interface{}{
+			"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "app"}},
+		}),
+		clusterScoped,
+	} {
+		// Not a workload
+		wp, err := resolveWorkload(ctx, cli, obj)
+		c.Assert(err, IsNil)
+		c.Assert(wp, IsNil)
+	}
+}
+
+func (s *WorkloadSuite) TestRegisterWorkloadResolver(c *C) {
+	gvk := schema.GroupVersionKind{Group: "example.io", Kind: "Registered"}
+	RegisterWorkloadResolver(gvk, NewWorkloadResolver(func(ctx context.Context, cli kubernetes.Interface, obj *unstructured.Unstructured) ([]v1.Pod, error) {
+		return []v1.Pod{*workloadPod("registered", nil, "")}, nil
+	}))
+	defer func() {
+		workloadResolversMu.Lock()
+		delete(workloadResolvers, gvk)
+		workloadResolversMu.Unlock()
+	}()
+
+	obj := workloadObject("example.io/v1beta1", "Registered", "", nil)
+	dynCli := fakedyncli.NewSimpleDynamicClient(runtime.NewScheme(), obj)
+	tp, err := New(context.Background(), fake.NewSimpleClientset(), dynCli, crfake.NewSimpleClientset(), nil, crv1alpha1.ActionSpec{
+		Object: crv1alpha1.ObjectReference{Name: "app", Namespace: "ns", Group: "example.io", APIVersion: "v1beta1", Resource: "registereds"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(tp.Workload, NotNil)
+	c.Assert(tp.Workload.Pods, DeepEquals, []string{"registered"})
+	c.Assert(tp.Object["Pods"], DeepEquals, []string{"registered"})
+
+	out, err := renderStringArg("{{ index .Object.Pods 0 }}/{{ .Object.metadata.name }}", *tp)
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "registered/app")
+}
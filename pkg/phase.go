@@ -61,8 +61,11 @@ func (p *Phase) Objects() map[string]crv1alpha1.ObjectReference {
 func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) (map[string]interface{}, error) {
 	if p.args == nil {
 		// Get the action from Blueprint
-		a, ok := bp.Actions[action]
-		if !ok {
+		a, err := resolveAction(bp, action)
+		if err != nil {
+			return nil, err
+		}
+		if a == nil {
 			return nil, errors.Errorf("Action {%s} not found in action map", action)
 		}
 		// Render the argument templates for the Phase's function
@@ -86,7 +89,11 @@ func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string
 				return nil, errors.Wrapf(err, "Required args missing for function %s", p.f.Name())
 			}
 
-			if err = checkSupportedArgs(p.f.Arguments(), args); err != nil {
+			if err = validateArgs(p.f, args); err != nil {
+				return nil, errors.Wrapf(err, "Required args missing for function %s", p.f.Name())
+			}
+
+			if err = checkArgs(p.f, args); err != nil {
 				return nil, errors.Wrapf(err, "Checking supported args for function %s.", p.f.Name())
 			}
 
@@ -119,8 +126,11 @@ func checkSupportedArgs(supportedArgs []string, args map[string]interface{}) err
 }
 
 func GetDeferPhase(bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) (*Phase, error) {
-	a, ok := bp.Actions[action]
-	if !ok {
+	a, err := resolveAction(bp, action)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
 		return nil, errors.Errorf("Action {%s} not found in blueprint actions", action)
 	}
 
@@ -173,9 +183,15 @@ func regFuncVersion(f, version string) (semver.Version, error) {
 }
 
 // GetPhases renders the returns a list of Phases with pre-rendered arguments.
+// The references of the action to other phases or actions of the Blueprint
+// are resolved, references to other Blueprints or BlueprintLibraries must be
+// resolved beforehand with ResolveBlueprint.
 func GetPhases(bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) ([]*Phase, error) {
-	a, ok := bp.Actions[action]
-	if !ok {
+	a, err := resolveAction(bp, action)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
 		return nil, errors.Errorf("Action {%s} not found in action map", action)
 	}
 
@@ -202,11 +218,22 @@ func GetPhases(bp crv1alpha1.Blueprint, action, version string, tp param.Templat
 
 // Validate gets the provided arguments from a blueprint and verifies that the required arguments are present
 func (p *Phase) Validate(args map[string]interface{}) error {
-	if err := checkSupportedArgs(p.f.Arguments(), args); err != nil {
+	if err := checkArgs(p.f, args); err != nil {
+		return err
+	}
+
+	if err := checkRequiredArgs(p.f.RequiredArgs(), args); err != nil {
 		return err
 	}
 
-	return checkRequiredArgs(p.f.RequiredArgs(), args)
+	return validateArgs(p.f, args)
+}
+
+func validateArgs(f Func, args map[string]interface{}) error {
+	if v, ok := f.(ArgsValidator); ok {
+		return v.ValidateArgs(args)
+	}
+	return nil
 }
 
 func checkRequiredArgs(reqArgs []string, args map[string]interface{}) error {
@@ -0,0 +1,198 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Redacted replaces the values of the secrets and credentials in the phases
+// rendered by Plan.
+const Redacted = "<redacted>"
+
+// PhasePlan is a phase of an action rendered with the args and the object
+// references it would be run with.
+type PhasePlan struct {
+	Name    string                                `json:"name"`
+	Func    string                                `json:"func"`
+	Defer   bool                                  `json:"defer,omitempty"`
+	Objects map[string]crv1alpha1.ObjectReference `json:"objects,omitempty"`
+	Args    map[string]interface{}                `json:"args,omitempty"`
+}
+
+var outputRefRE = regexp.MustCompile(`\.Phases\.(\w+)\.Output\.(\w+)`)
+
+// Plan renders the phases of the action, including its deferPhase, like they
+// are rendered when the action is run, without executing them. The template
+// params should be created with param.New, and cli is used to fetch the
+// secrets referred to by the objects of the phases. The values of the secrets
+// and credentials are replaced by Redacted, and the outputs of the phases by
+// placeholders such as `<Phases.backup.Output.id>`.
+func Plan(ctx context.Context, cli kubernetes.Interface, bp crv1alpha1.Blueprint, action, version string, tp param.TemplateParams) ([]PhasePlan, error) {
+	a, err := resolveAction(bp, action)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, errors.Errorf("Action {%s} not found in action map", action)
+	}
+	phases, err := GetPhases(bp, action, version, tp)
+	if err != nil {
+		return nil, err
+	}
+	deferPhase, err := GetDeferPhase(bp, action, version, tp)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := outputRefs(a)
+	if err != nil {
+		return nil, err
+	}
+	redactTemplateParams(&tp)
+	tp.Phases = nil
+
+	plan := make([]PhasePlan, 0, len(phases)+1)
+	for i, p := range phases {
+		if err := param.InitPhaseParams(ctx, cli, &tp, p.Name(), p.Objects()); err != nil {
+			return nil, errors.Wrapf(err, "Failed to get the params of phase %s", p.Name())
+		}
+		tp.Phases[p.Name()].Secrets = redactSecrets(tp.Phases[p.Name()].Secrets)
+		tp.DeferPhase.Secrets = tp.Phases[p.Name()].Secrets
+		pp, err := planPhase(p, a.Phases[i], tp)
+		if err != nil {
+			return nil, err
+		}
+		plan = append(plan, pp)
+		param.UpdatePhaseParams(ctx, &tp, p.Name(), outputPlaceholders(p, refs[p.Name()]))
+	}
+	if deferPhase != nil {
+		pp, err := planPhase(deferPhase, *a.DeferPhase, tp)
+		if err != nil {
+			return nil, err
+		}
+		pp.Defer = true
+		plan = append(plan, pp)
+	}
+	return plan, nil
+}
+
+func planPhase(p *Phase, bpp crv1alpha1.BlueprintPhase, tp param.TemplateParams) (PhasePlan, error) {
+	args, err := renderFuncArgs(bpp.Func, bpp.Args, tp)
+	if err != nil {
+		return PhasePlan{}, errors.Wrapf(err, "Failed to render the args of phase %s", p.Name())
+	}
+	if err := p.Validate(args); err != nil {
+		return PhasePlan{}, errors.Wrapf(err, "Invalid args of phase %s", p.Name())
+	}
+	return PhasePlan{
+		Name:    p.Name(),
+		Func:    p.f.Name(),
+		Objects: p.Objects(),
+		Args:    args,
+	}, nil
+}
+
+// outputRefs returns the keys of the outputs of each phase referred to by
+// the templates of the action.
+func outputRefs(a *crv1alpha1.BlueprintAction) (map[string][]string, error) {
+	raw, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string][]string)
+	for _, m := range outputRefRE.FindAllStringSubmatch(string(raw), -1) {
+		refs[m[1]] = append(refs[m[1]], m[2])
+	}
+	return refs, nil
+}
+
+// outputPlaceholders returns placeholders for the outputs declared by the Func
+// of the phase and the ones referred to by the templates.
+func outputPlaceholders(p *Phase, keys []string) map[string]interface{} {
+	outputs, _ := FuncOutputs(p.f)
+	for _, o := range outputs {
+		if o.Name != AnyOutput {
+			keys = append(keys, o.Name)
+		}
+	}
+	out := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		out[k] = fmt.Sprintf("<Phases.%s.Output.%s>", p.Name(), k)
+	}
+	return out
+}
+
+// redactTemplateParams replaces the values of the secrets and credentials of
+// the template params.
+func redactTemplateParams(tp *param.TemplateParams) {
+	tp.Secrets = redactSecrets(tp.Secrets)
+	if tp.Profile != nil {
+		cred := tp.Profile.Credential
+		if cred.KeyPair != nil {
+			cred.KeyPair = &param.KeyPair{ID: cred.KeyPair.ID, Secret: Redacted}
+		}
+		if cred.Secret != nil {
+			cred.Secret = redactSecret(*cred.Secret)
+		}
+		if cred.KopiaServerSecret != nil {
+			kss := *cred.KopiaServerSecret
+			kss.Password = Redacted
+			cred.KopiaServerSecret = &kss
+		}
+		profile := *tp.Profile
+		profile.Credential = cred
+		tp.Profile = &profile
+	}
+	if tp.RepositoryServer != nil {
+		rs := *tp.RepositoryServer
+		rs.Credentials = param.RepositoryServerCredentials{
+			ServerTLS:        *redactSecret(rs.Credentials.ServerTLS),
+			ServerUserAccess: *redactSecret(rs.Credentials.ServerUserAccess),
+		}
+		tp.RepositoryServer = &rs
+	}
+}
+
+func redactSecrets(secrets map[string]v1.Secret) map[string]v1.Secret {
+	if secrets == nil {
+		return nil
+	}
+	r := make(map[string]v1.Secret, len(secrets))
+	for name, s := range secrets {
+		r[name] = *redactSecret(s)
+	}
+	return r
+}
+
+func redactSecret(s v1.Secret) *v1.Secret {
+	r := s.DeepCopy()
+	for k := range r.Data {
+		r.Data[k] = []byte(Redacted)
+	}
+	for k := range r.StringData {
+		r.StringData[k] = Redacted
+	}
+	return r
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type PlanSuite struct{}
+
+var _ = Suite(&PlanSuite{})
+
+// planFunc fails if it is executed.
+type planFunc struct{}
+
+func (*planFunc) Name() string            { return "PlanTestFunc" }
+func (*planFunc) RequiredArgs() []string  { return []string{"command"} }
+func (*planFunc) Arguments() []string     { return []string{"command"} }
+func (*planFunc) Outputs() []Output       { return []Output{{Name: "id", Type: OutputTypeString}} }
+func (*planFunc) ArgSchemas() []ArgSchema { return []ArgSchema{{Name: "command", Type: ArgTypeArray}} }
+func (*planFunc) ExecutionProgress() (crv1alpha1.PhaseProgress, error) {
+	return crv1alpha1.PhaseProgress{}, nil
+}
+
+func (*planFunc) Exec(context.Context, param.TemplateParams, map[string]interface{}) (map[string]interface{}, error) {
+	panic("PlanTestFunc must not be executed")
+}
+
+func (s *PlanSuite) TestPlan(c *C) {
+	err := Register(&planFunc{})
+	c.Assert(err, IsNil)
+
+	bp := crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "dump",
+						Func: "PlanTestFunc",
+						Args: map[string]interface{}{"command": []interface{}{
+							"dump", "--mode={{ .Options.mode }}", "--password={{ .Secrets.db.Data.password | toString }}",
+						}},
+						ObjectRefs: map[string]crv1alpha1.ObjectReference{
+							"pvc": {Kind: "pvc", Name: "{{ .Options.mode }}-pvc", Namespace: "ns"},
+						},
+					},
+					{
+						Name: "upload",
+						Func: "PlanTestFunc",
+						Args: map[string]interface{}{"command": []interface{}{
+							"upload", "{{ .Phases.dump.Output.id }}", "{{ .Phases.dump.Output.path }}", "{{ .Profile.Credential.KeyPair.Secret }}",
+						}},
+					},
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{
+					Name: "cleanup",
+					Func: "PlanTestFunc",
+					Args: map[string]interface{}{"command": []interface{}{"rm", "{{ .Phases.upload.Output.id }}"}},
+				},
+			},
+		},
+	}
+	secrets := map[string]v1.Secret{
+		"db": {ObjectMeta: metav1.ObjectMeta{Name: "db"}, Data: map[string][]byte{"password": []byte("hunter2")}},
+	}
+	tp := param.TemplateParams{
+		Time:    "2023-01-01T00:00:00Z",
+		Options: map[string]string{"mode": "full"},
+		Secrets: secrets,
+		Profile: &param.Profile{Credential: param.Credential{
+			Type:    param.CredentialTypeKeyPair,
+			KeyPair: &param.KeyPair{ID: "id", Secret: "secret"},
+		}},
+	}
+	plan, err := Plan(context.Background(), fake.NewSimpleClientset(), bp, "backup", DefaultVersion, tp)
+	c.Assert(err, IsNil)
+	c.Assert(plan, DeepEquals, []PhasePlan{
+		{
+			Name:    "dump",
+			Func:    "PlanTestFunc",
+			Objects: map[string]crv1alpha1.ObjectReference{"pvc": {Kind: "pvc", Name: "full-pvc", Namespace: "ns"}},
+			Args:    map[string]interface{}{"command": []interface{}{"dump", "--mode=full", "--password=<redacted>"}},
+		},
+		{
+			Name:    "upload",
+			Func:    "PlanTestFunc",
+			Objects: map[string]crv1alpha1.ObjectReference{},
+			Args: map[string]interface{}{"command": []interface{}{
+				"upload", "<Phases.dump.Output.id>", "<Phases.dump.Output.path>", "<redacted>",
+			}},
+		},
+		{
+			Name:    "cleanup",
+			Func:    "PlanTestFunc",
+			Defer:   true,
+			Objects: map[string]crv1alpha1.ObjectReference{},
+			Args:    map[string]interface{}{"command": []interface{}{"rm", "<Phases.upload.Output.id>"}},
+		},
+	})
+	// The template params are not modified
+	c.Assert(string(secrets["db"].Data["password"]), Equals, "hunter2")
+	c.Assert(tp.Profile.Credential.KeyPair.Secret, Equals, "secret")
+
+	_, err = Plan(context.Background(), fake.NewSimpleClientset(), bp, "restore", DefaultVersion, tp)
+	c.Assert(err, ErrorMatches, ".*Action {restore} not found.*")
+}
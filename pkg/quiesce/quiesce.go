@@ -0,0 +1,250 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quiesce quiesces and unquiesces applications. The quiesces are
+// recorded in the ActionSet status, so that the controller can unquiesce the
+// application if the blueprint does not.
+package quiesce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/format"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+const (
+	// HandlerFSFreeze freezes the filesystems mounted at the given paths of a container
+	HandlerFSFreeze = "fsfreeze"
+	// HandlerScaleToZero scales a Deployment or StatefulSet down to zero replicas
+	HandlerScaleToZero = "scaleToZero"
+	// HandlerExec runs arbitrary commands in a container
+	HandlerExec = "exec"
+
+	// OutputQuiesce is the phase output key of a quiesce record
+	OutputQuiesce = "quiesce"
+	// OutputUnquiesced is the phase output key of the ID of an undone quiesce
+	OutputUnquiesced = "unquiesced"
+)
+
+// Validate checks that the record has the fields required by its handler.
+func Validate(q *crv1alpha1.QuiesceStatus) error {
+	if q.Namespace == "" {
+		return errors.New("Namespace is required")
+	}
+	switch q.Handler {
+	case HandlerFSFreeze:
+		if q.Pod == "" || len(q.Paths) == 0 {
+			return errors.Errorf("Handler %s requires a pod and paths", q.Handler)
+		}
+	case HandlerScaleToZero:
+		if q.Name == "" {
+			return errors.Errorf("Handler %s requires a workload name", q.Handler)
+		}
+		if q.Kind != param.DeploymentKind && q.Kind != param.StatefulSetKind {
+			return errors.Errorf("Handler %s does not support kind %s", q.Handler, q.Kind)
+		}
+	case HandlerExec:
+		if q.Pod == "" || len(q.UnquiesceCommand) == 0 {
+			return errors.Errorf("Handler %s requires a pod and an unquiesce command", q.Handler)
+		}
+	default:
+		return errors.Errorf("Unknown quiesce handler %s", q.Handler)
+	}
+	return nil
+}
+
+// Quiesce quiesces the application described by the record. The exec handler
+// runs the quiesce command, which is not part of the record. The scaleToZero
+// handler records the original replica count. The record is passed to the
+// tracker of the context, if any, before the application is quiesced, and
+// is removed from it if quiescing fails.
+func Quiesce(ctx context.Context, cli kubernetes.Interface, q *crv1alpha1.QuiesceStatus, quiesceCommand []string) error {
+	if err := Validate(q); err != nil {
+		return err
+	}
+	switch q.Handler {
+	case HandlerFSFreeze:
+		if err := record(ctx, q); err != nil {
+			return err
+		}
+		for i, path := range q.Paths {
+			if err := execCommand(ctx, cli, q, []string{"fsfreeze", "--freeze", path}); err != nil {
+				// Do not leave the filesystems frozen so far behind
+				for _, frozen := range q.Paths[:i] {
+					_ = execCommand(ctx, cli, q, []string{"fsfreeze", "--unfreeze", frozen})
+				}
+				forget(ctx, q.ID)
+				return err
+			}
+		}
+		return nil
+	case HandlerScaleToZero:
+		replicas, err := workloadReplicas(ctx, cli, q)
+		if err != nil {
+			return err
+		}
+		q.Replicas = replicas
+		if err := record(ctx, q); err != nil {
+			return err
+		}
+		if err := scale(ctx, cli, q, 0); err != nil {
+			// The replica count may have been updated before waiting for the
+			// pods failed. The context may be done, so it is not used.
+			_ = scale(context.Background(), cli, q, q.Replicas)
+			forget(ctx, q.ID)
+			return err
+		}
+		return nil
+	default:
+		if len(quiesceCommand) == 0 {
+			return errors.Errorf("Handler %s requires a quiesce command", q.Handler)
+		}
+		if err := record(ctx, q); err != nil {
+			return err
+		}
+		if err := execCommand(ctx, cli, q, quiesceCommand); err != nil {
+			// The command may have quiesced the application before failing
+			_ = execCommand(context.Background(), cli, q, q.UnquiesceCommand)
+			forget(ctx, q.ID)
+			return err
+		}
+		return nil
+	}
+}
+
+// Unquiesce undoes the quiesce described by the record.
+func Unquiesce(ctx context.Context, cli kubernetes.Interface, q *crv1alpha1.QuiesceStatus) error {
+	if err := Validate(q); err != nil {
+		return err
+	}
+	switch q.Handler {
+	case HandlerFSFreeze:
+		var errs []string
+		for _, path := range q.Paths {
+			if err := execCommand(ctx, cli, q, []string{"fsfreeze", "--unfreeze", path}); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) != 0 {
+			return errors.Errorf("Failed to unfreeze filesystems: %v", errs)
+		}
+		return nil
+	case HandlerScaleToZero:
+		return scale(ctx, cli, q, q.Replicas)
+	default:
+		return execCommand(ctx, cli, q, q.UnquiesceCommand)
+	}
+}
+
+// Marshal encodes the record as a phase output value.
+func Marshal(q *crv1alpha1.QuiesceStatus) (string, error) {
+	b, err := json.Marshal(q)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to marshal quiesce record")
+	}
+	return string(b), nil
+}
+
+// Unmarshal decodes a record encoded by Marshal.
+func Unmarshal(s string) (*crv1alpha1.QuiesceStatus, error) {
+	q := &crv1alpha1.QuiesceStatus{}
+	if err := json.Unmarshal([]byte(s), q); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal quiesce record")
+	}
+	return q, nil
+}
+
+// FromOutput returns the quiesce created and the ID of the quiesce undone by
+// a phase, as given by its output. Either of them is empty if the phase did
+// not quiesce or unquiesce the application.
+func FromOutput(output map[string]interface{}) (*crv1alpha1.QuiesceStatus, string, error) {
+	var q *crv1alpha1.QuiesceStatus
+	if v, ok := output[OutputQuiesce]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return nil, "", errors.Errorf("Output %s is not a string", OutputQuiesce)
+		}
+		var err error
+		if q, err = Unmarshal(s); err != nil {
+			return nil, "", err
+		}
+	}
+	var id string
+	if v, ok := output[OutputUnquiesced]; ok {
+		id = fmt.Sprint(v)
+	}
+	return q, id, nil
+}
+
+// Remove removes the record with the given ID and returns it, if present.
+func Remove(status *crv1alpha1.ActionStatus, id string) *crv1alpha1.QuiesceStatus {
+	for i := range status.Quiesces {
+		if status.Quiesces[i].ID != id {
+			continue
+		}
+		q := status.Quiesces[i]
+		status.Quiesces = append(status.Quiesces[:i], status.Quiesces[i+1:]...)
+		return &q
+	}
+	return nil
+}
+
+func execCommand(ctx context.Context, cli kubernetes.Interface, q *crv1alpha1.QuiesceStatus, command []string) error {
+	stdout, stderr, err := kube.Exec(cli, q.Namespace, q.Pod, q.Container, command, nil)
+	format.LogWithCtx(ctx, q.Pod, q.Container, stdout)
+	format.LogWithCtx(ctx, q.Pod, q.Container, stderr)
+	return errors.Wrapf(err, "Failed to run %v in pod %s", command, q.Pod)
+}
+
+func workloadReplicas(ctx context.Context, cli kubernetes.Interface, q *crv1alpha1.QuiesceStatus) (int32, error) {
+	var replicas *int32
+	switch q.Kind {
+	case param.DeploymentKind:
+		d, err := cli.AppsV1().Deployments(q.Namespace).Get(ctx, q.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, errors.Wrapf(err, "Failed to get Deployment %s", q.Name)
+		}
+		replicas = d.Spec.Replicas
+	default:
+		ss, err := cli.AppsV1().StatefulSets(q.Namespace).Get(ctx, q.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, errors.Wrapf(err, "Failed to get StatefulSet %s", q.Name)
+		}
+		replicas = ss.Spec.Replicas
+	}
+	// Kubernetes defaults the replica count to 1
+	if replicas == nil {
+		return 1, nil
+	}
+	return *replicas, nil
+}
+
+func scale(ctx context.Context, cli kubernetes.Interface, q *crv1alpha1.QuiesceStatus, replicas int32) error {
+	// Scaling down waits for the pods to be gone, scaling up does not wait
+	// for them to be ready, so that the controller is not blocked.
+	waitForReady := replicas == 0
+	if q.Kind == param.DeploymentKind {
+		return kube.ScaleDeployment(ctx, cli, q.Namespace, q.Name, replicas, waitForReady)
+	}
+	return kube.ScaleStatefulSet(ctx, cli, q.Namespace, q.Name, replicas, waitForReady)
+}
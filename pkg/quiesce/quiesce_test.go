@@ -0,0 +1,161 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quiesce
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type QuiesceSuite struct{}
+
+var _ = Suite(&QuiesceSuite{})
+
+func (s *QuiesceSuite) TestValidate(c *C) {
+	for _, tc := range []struct {
+		q       crv1alpha1.QuiesceStatus
+		checker Checker
+	}{
+		{crv1alpha1.QuiesceStatus{Handler: HandlerFSFreeze, Namespace: "ns", Pod: "pod", Paths: []string{"/data"}}, IsNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerFSFreeze, Namespace: "ns", Pod: "pod"}, NotNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerScaleToZero, Namespace: "ns", Kind: param.StatefulSetKind, Name: "db"}, IsNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerScaleToZero, Namespace: "ns", Kind: "daemonset", Name: "db"}, NotNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerExec, Namespace: "ns", Pod: "pod", UnquiesceCommand: []string{"unlock"}}, IsNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerExec, Namespace: "ns", Pod: "pod"}, NotNil},
+		{crv1alpha1.QuiesceStatus{Handler: HandlerExec, Pod: "pod", UnquiesceCommand: []string{"unlock"}}, NotNil},
+		{crv1alpha1.QuiesceStatus{Handler: "unknown", Namespace: "ns"}, NotNil},
+	} {
+		c.Check(Validate(&tc.q), tc.checker, Commentf("%+v", tc.q))
+	}
+}
+
+func (s *QuiesceSuite) TestFromOutput(c *C) {
+	q := &crv1alpha1.QuiesceStatus{
+		ID:        "id",
+		Handler:   HandlerFSFreeze,
+		Namespace: "ns",
+		Pod:       "pod",
+		Paths:     []string{"/data"},
+		ExpiresAt: metav1.NewTime(time.Now().Add(time.Minute).Truncate(time.Second)),
+	}
+	out, err := Marshal(q)
+	c.Assert(err, IsNil)
+
+	got, unquiesced, err := FromOutput(map[string]interface{}{OutputQuiesce: out})
+	c.Assert(err, IsNil)
+	c.Assert(unquiesced, Equals, "")
+	c.Assert(got.ExpiresAt.Equal(&q.ExpiresAt), Equals, true)
+	got.ExpiresAt = q.ExpiresAt
+	c.Assert(got, DeepEquals, q)
+
+	got, unquiesced, err = FromOutput(map[string]interface{}{OutputUnquiesced: "id"})
+	c.Assert(err, IsNil)
+	c.Assert(got, IsNil)
+	c.Assert(unquiesced, Equals, "id")
+
+	_, _, err = FromOutput(map[string]interface{}{OutputQuiesce: 1})
+	c.Assert(err, NotNil)
+
+	status := &crv1alpha1.ActionStatus{Quiesces: []crv1alpha1.QuiesceStatus{{ID: "a"}, {ID: "id"}, {ID: "b"}}}
+	c.Assert(Remove(status, "id").ID, Equals, "id")
+	c.Assert(Remove(status, "id"), IsNil)
+	c.Assert(status.Quiesces, DeepEquals, []crv1alpha1.QuiesceStatus{{ID: "a"}, {ID: "b"}})
+}
+
+func (s *QuiesceSuite) TestUnquiesceScaleToZero(c *C) {
+	var zero int32
+	cli := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &zero},
+	})
+	q := &crv1alpha1.QuiesceStatus{Handler: HandlerScaleToZero, Namespace: "ns", Kind: param.DeploymentKind, Name: "app", Replicas: 3}
+	c.Assert(Unquiesce(context.Background(), cli, q), IsNil)
+	d, err := cli.AppsV1().Deployments("ns").Get(context.Background(), "app", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(*d.Spec.Replicas, Equals, int32(3))
+
+	// The replica count is defaulted
+	d.Spec.Replicas = nil
+	_, err = cli.AppsV1().Deployments("ns").Update(context.Background(), d, metav1.UpdateOptions{})
+	c.Assert(err, IsNil)
+	replicas, err := workloadReplicas(context.Background(), cli, q)
+	c.Assert(err, IsNil)
+	c.Assert(replicas, Equals, int32(1))
+}
+
+func (s *QuiesceSuite) TestQuiesceScaleToZeroFailure(c *C) {
+	three := int32(3)
+	cli := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &three},
+	})
+	// Waiting for the pods to be gone fails once the replica count is updated
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	q := &crv1alpha1.QuiesceStatus{Handler: HandlerScaleToZero, Namespace: "ns", Kind: param.DeploymentKind, Name: "app"}
+	c.Assert(Quiesce(ctx, cli, q, nil), NotNil)
+	d, err := cli.AppsV1().Deployments("ns").Get(context.Background(), "app", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(*d.Spec.Replicas, Equals, int32(3))
+}
+
+type fakeTracker struct {
+	recorded []crv1alpha1.QuiesceStatus
+	forgot   []string
+}
+
+func (t *fakeTracker) Record(ctx context.Context, q *crv1alpha1.QuiesceStatus) error {
+	t.recorded = append(t.recorded, *q)
+	return nil
+}
+
+func (t *fakeTracker) Forget(ctx context.Context, id string) error {
+	t.forgot = append(t.forgot, id)
+	return nil
+}
+
+func (t *fakeTracker) Unquiesce(ctx context.Context, id string) error {
+	return nil
+}
+
+func (s *QuiesceSuite) TestQuiesceTracker(c *C) {
+	three := int32(3)
+	cli := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &three},
+	})
+	t := &fakeTracker{}
+	ctx, cancel := context.WithTimeout(WithTracker(context.Background(), t), 100*time.Millisecond)
+	defer cancel()
+	q := &crv1alpha1.QuiesceStatus{ID: "id", Handler: HandlerScaleToZero, Namespace: "ns", Kind: param.DeploymentKind, Name: "app"}
+	c.Assert(Quiesce(ctx, cli, q, nil), NotNil)
+	// The record is kept with the replica count before scaling, and removed
+	// once scaling is rolled back
+	c.Assert(t.recorded, HasLen, 1)
+	c.Assert(t.recorded[0].Replicas, Equals, int32(3))
+	c.Assert(t.forgot, DeepEquals, []string{"id"})
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quiesce
+
+import (
+	"context"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/field"
+	"github.com/kanisterio/kanister/pkg/log"
+)
+
+// Tracker keeps the quiesces of an action in the ActionSet status. The
+// controller passes it to the phases through the context, so that a quiesce
+// is undone once, by the Unquiesce function or by the controller, and is
+// recorded before the application is quiesced.
+type Tracker interface {
+	// Record records the quiesce before the application is quiesced, so
+	// that it is undone if the controller restarts while the phase runs.
+	Record(ctx context.Context, q *crv1alpha1.QuiesceStatus) error
+	// Forget removes the quiesce when quiescing the application failed and
+	// was rolled back.
+	Forget(ctx context.Context, id string) error
+	// Unquiesce undoes the quiesce and removes it, unless it was undone
+	// already.
+	Unquiesce(ctx context.Context, id string) error
+}
+
+type trackerKey struct{}
+
+// WithTracker returns a context that has ctx as its parent context and
+// carries the tracker.
+func WithTracker(ctx context.Context, t Tracker) context.Context {
+	return context.WithValue(ctx, trackerKey{}, t)
+}
+
+// TrackerFromContext returns the tracker carried by ctx, if any.
+func TrackerFromContext(ctx context.Context) Tracker {
+	t, _ := ctx.Value(trackerKey{}).(Tracker)
+	return t
+}
+
+func record(ctx context.Context, q *crv1alpha1.QuiesceStatus) error {
+	if t := TrackerFromContext(ctx); t != nil {
+		return t.Record(ctx, q)
+	}
+	return nil
+}
+
+func forget(ctx context.Context, id string) {
+	if t := TrackerFromContext(ctx); t != nil {
+		// The context may be done, so it is not used
+		if err := t.Forget(context.Background(), id); err != nil {
+			log.WithContext(ctx).WithError(err).Print("Failed to forget quiesce", field.M{"ID": id})
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// Kinds of the resources Blueprints can refer to
+const (
+	RefKindBlueprint        = "Blueprint"
+	RefKindBlueprintLibrary = "BlueprintLibrary"
+)
+
+// BlueprintGetter gets the Blueprints and BlueprintLibraries referenced by
+// the actions and phases of a Blueprint.
+type BlueprintGetter interface {
+	GetBlueprint(ctx context.Context, name string) (*crv1alpha1.Blueprint, error)
+	GetBlueprintLibrary(ctx context.Context, name string) (*crv1alpha1.BlueprintLibrary, error)
+}
+
+// ResolveBlueprint returns a copy of the Blueprint in which the actions that
+// extend other actions and the phases that refer to other phases are
+// replaced by the referenced ones. The referenced Blueprints and
+// BlueprintLibraries are fetched with the getter, which can be nil if the
+// Blueprint only refers to itself.
+func ResolveBlueprint(ctx context.Context, g BlueprintGetter, bp *crv1alpha1.Blueprint) (*crv1alpha1.Blueprint, error) {
+	r := newResolver(ctx, g, bp)
+	out := bp.DeepCopy()
+	for name := range bp.Actions {
+		a, err := r.action(r.self, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to resolve action %s", name)
+		}
+		out.Actions[name] = a
+	}
+	return out, nil
+}
+
+// resolveAction returns the action of the Blueprint with its references
+// resolved. References to other Blueprints or BlueprintLibraries fail, they
+// must be resolved beforehand with ResolveBlueprint.
+func resolveAction(bp crv1alpha1.Blueprint, action string) (*crv1alpha1.BlueprintAction, error) {
+	a, ok := bp.Actions[action]
+	if !ok || !hasReferences(a) {
+		return a, nil
+	}
+	r := newResolver(context.Background(), nil, &bp)
+	a, err := r.action(r.self, action)
+	return a, errors.Wrapf(err, "Failed to resolve action %s", action)
+}
+
+func hasReferences(a *crv1alpha1.BlueprintAction) bool {
+	if a.Extends != nil || (a.DeferPhase != nil && a.DeferPhase.Ref != nil) {
+		return true
+	}
+	for _, p := range a.Phases {
+		if p.Ref != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// refSource is a Blueprint or a BlueprintLibrary references are resolved in
+type refSource struct {
+	kind    string
+	name    string
+	phases  []crv1alpha1.BlueprintPhase
+	actions map[string]*crv1alpha1.BlueprintAction
+}
+
+type resolver struct {
+	ctx     context.Context
+	getter  BlueprintGetter
+	self    refSource
+	sources map[string]refSource
+	// visiting holds the actions and phases being resolved to detect cycles
+	visiting []string
+}
+
+func newResolver(ctx context.Context, g BlueprintGetter, bp *crv1alpha1.Blueprint) *resolver {
+	self := refSource{kind: RefKindBlueprint, name: bp.GetName(), actions: bp.Actions}
+	return &resolver{
+		ctx:     ctx,
+		getter:  g,
+		self:    self,
+		sources: map[string]refSource{RefKindBlueprint + "/" + self.name: self},
+	}
+}
+
+func (r *resolver) source(from refSource, ref *crv1alpha1.BlueprintReference) (refSource, error) {
+	if ref.Name == "" {
+		return from, nil
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = RefKindBlueprintLibrary
+	}
+	key := kind + "/" + ref.Name
+	if s, ok := r.sources[key]; ok {
+		return s, nil
+	}
+	if r.getter == nil {
+		return refSource{}, errors.Errorf("Cannot get %s %s, the referenced resources are not available", kind, ref.Name)
+	}
+	var s refSource
+	switch kind {
+	case RefKindBlueprint:
+		bp, err := r.getter.GetBlueprint(r.ctx, ref.Name)
+		if err != nil {
+			return refSource{}, errors.Wrapf(err, "Failed to get %s %s", kind, ref.Name)
+		}
+		s = refSource{kind: kind, name: ref.Name, actions: bp.Actions}
+	case RefKindBlueprintLibrary:
+		lib, err := r.getter.GetBlueprintLibrary(r.ctx, ref.Name)
+		if err != nil {
+			return refSource{}, errors.Wrapf(err, "Failed to get %s %s", kind, ref.Name)
+		}
+		s = refSource{kind: kind, name: ref.Name, phases: lib.Phases, actions: lib.Actions}
+	default:
+		return refSource{}, errors.Errorf("Unsupported kind %s of reference to %s, expected %s or %s", kind, ref.Name, RefKindBlueprint, RefKindBlueprintLibrary)
+	}
+	r.sources[key] = s
+	return s, nil
+}
+
+func (r *resolver) enter(key string) error {
+	for _, v := range r.visiting {
+		if v == key {
+			return errors.Errorf("Cyclic reference %s", strings.Join(append(r.visiting, key), " -> "))
+		}
+	}
+	r.visiting = append(r.visiting, key)
+	return nil
+}
+
+func (r *resolver) leave() {
+	r.visiting = r.visiting[:len(r.visiting)-1]
+}
+
+// action returns a copy of the action of the source with its references resolved
+func (r *resolver) action(src refSource, name string) (*crv1alpha1.BlueprintAction, error) {
+	a, ok := src.actions[name]
+	if !ok || a == nil {
+		return nil, errors.Errorf("Action {%s} not found in %s %s", name, src.kind, src.name)
+	}
+	if err := r.enter(fmt.Sprintf("%s %s action %s", src.kind, src.name, name)); err != nil {
+		return nil, err
+	}
+	defer r.leave()
+
+	out := a.DeepCopy()
+	out.Extends = nil
+	out.Phases = make([]crv1alpha1.BlueprintPhase, 0, len(a.Phases))
+	out.DeferPhase = nil
+	for _, p := range a.Phases {
+		rp, err := r.phase(src, p)
+		if err != nil {
+			return nil, err
+		}
+		out.Phases = append(out.Phases, rp)
+	}
+	if a.DeferPhase != nil {
+		dp, err := r.phase(src, *a.DeferPhase)
+		if err != nil {
+			return nil, err
+		}
+		out.DeferPhase = &dp
+	}
+	if a.Extends == nil {
+		return out, nil
+	}
+
+	if a.Extends.Action == "" || a.Extends.Phase != "" {
+		return nil, errors.Errorf("Action %s must extend an action, not a phase", name)
+	}
+	bs, err := r.source(src, a.Extends)
+	if err != nil {
+		return nil, err
+	}
+	base, err := r.action(bs, a.Extends.Action)
+	if err != nil {
+		return nil, err
+	}
+	return inheritAction(base, out, a), nil
+}
+
+// inheritAction returns the base action overridden by the resolved action
+// out, whose original definition is a.
+func inheritAction(base, out, a *crv1alpha1.BlueprintAction) *crv1alpha1.BlueprintAction {
+	if out.Name != "" {
+		base.Name = out.Name
+	}
+	if out.Kind != "" {
+		base.Kind = out.Kind
+	}
+	if out.ConfigMapNames != nil {
+		base.ConfigMapNames = out.ConfigMapNames
+	}
+	if out.SecretNames != nil {
+		base.SecretNames = out.SecretNames
+	}
+	if out.InputArtifactNames != nil {
+		base.InputArtifactNames = out.InputArtifactNames
+	}
+	if len(out.OutputArtifacts) > 0 {
+		arts := make(map[string]crv1alpha1.Artifact, len(base.OutputArtifacts)+len(out.OutputArtifacts))
+		for k, v := range base.OutputArtifacts {
+			arts[k] = v
+		}
+		for k, v := range out.OutputArtifacts {
+			arts[k] = v
+		}
+		base.OutputArtifacts = arts
+	}
+	for _, p := range out.Parameters {
+		base.Parameters = overrideParameters(base.Parameters, p)
+	}
+	for i, p := range out.Phases {
+		base.Phases = overridePhases(base.Phases, p, isOverride(a.Phases[i]))
+	}
+	if out.DeferPhase != nil {
+		if base.DeferPhase != nil && base.DeferPhase.Name == out.DeferPhase.Name && isOverride(*a.DeferPhase) {
+			dp := overridePhase(*base.DeferPhase, *out.DeferPhase)
+			base.DeferPhase = &dp
+		} else {
+			base.DeferPhase = out.DeferPhase
+		}
+	}
+	return base
+}
+
+func overrideParameters(params []crv1alpha1.BlueprintParameter, p crv1alpha1.BlueprintParameter) []crv1alpha1.BlueprintParameter {
+	for i := range params {
+		if params[i].Name == p.Name {
+			params[i] = p
+			return params
+		}
+	}
+	return append(params, p)
+}
+
+// isOverride returns true if the phase only overrides the args and objects of
+// the inherited phase with the same name
+func isOverride(p crv1alpha1.BlueprintPhase) bool {
+	return p.Func == "" && p.Ref == nil
+}
+
+func overridePhases(phases []crv1alpha1.BlueprintPhase, p crv1alpha1.BlueprintPhase, override bool) []crv1alpha1.BlueprintPhase {
+	for i, bp := range phases {
+		if bp.Name != p.Name {
+			continue
+		}
+		if override {
+			phases[i] = overridePhase(bp, p)
+		} else {
+			phases[i] = p
+		}
+		return phases
+	}
+	return append(phases, p)
+}
+
+// phase returns a copy of the phase with its reference resolved
+func (r *resolver) phase(src refSource, p crv1alpha1.BlueprintPhase) (crv1alpha1.BlueprintPhase, error) {
+	if p.Ref == nil {
+		return p, nil
+	}
+	if p.Func != "" {
+		return crv1alpha1.BlueprintPhase{}, errors.Errorf("Phase %s cannot have both a func and a ref", p.Name)
+	}
+	if p.Ref.Phase == "" {
+		return crv1alpha1.BlueprintPhase{}, errors.Errorf("Phase %s must refer to a phase", p.Name)
+	}
+	rs, err := r.source(src, p.Ref)
+	if err != nil {
+		return crv1alpha1.BlueprintPhase{}, err
+	}
+	var ref *crv1alpha1.BlueprintPhase
+	if p.Ref.Action != "" {
+		a, err := r.action(rs, p.Ref.Action)
+		if err != nil {
+			return crv1alpha1.BlueprintPhase{}, err
+		}
+		ref = findPhase(a, p.Ref.Phase)
+	} else {
+		for i := range rs.phases {
+			if rs.phases[i].Name == p.Ref.Phase {
+				ref = &rs.phases[i]
+				break
+			}
+		}
+		if ref != nil {
+			if err := r.enter(fmt.Sprintf("%s %s phase %s", rs.kind, rs.name, ref.Name)); err != nil {
+				return crv1alpha1.BlueprintPhase{}, err
+			}
+			rp, err := r.phase(rs, *ref)
+			r.leave()
+			if err != nil {
+				return crv1alpha1.BlueprintPhase{}, err
+			}
+			ref = &rp
+		}
+	}
+	if ref == nil {
+		return crv1alpha1.BlueprintPhase{}, errors.Errorf("Phase %s referred to by phase %s not found in %s %s", p.Ref.Phase, p.Name, rs.kind, rs.name)
+	}
+	return overridePhase(*ref, p), nil
+}
+
+func findPhase(a *crv1alpha1.BlueprintAction, name string) *crv1alpha1.BlueprintPhase {
+	for i := range a.Phases {
+		if a.Phases[i].Name == name {
+			return &a.Phases[i]
+		}
+	}
+	if a.DeferPhase != nil && a.DeferPhase.Name == name {
+		return a.DeferPhase
+	}
+	return nil
+}
+
+// overridePhase returns a copy of the phase with the name, args and objects
+// of the override
+func overridePhase(p, o crv1alpha1.BlueprintPhase) crv1alpha1.BlueprintPhase {
+	out := crv1alpha1.BlueprintPhase{
+		Func: p.Func,
+		Name: p.Name,
+	}
+	if o.Name != "" {
+		out.Name = o.Name
+	}
+	if p.Args != nil || o.Args != nil {
+		out.Args = make(map[string]interface{}, len(p.Args)+len(o.Args))
+		for k, v := range p.Args {
+			out.Args[k] = v
+		}
+		for k, v := range o.Args {
+			out.Args[k] = v
+		}
+	}
+	if p.ObjectRefs != nil || o.ObjectRefs != nil {
+		out.ObjectRefs = make(map[string]crv1alpha1.ObjectReference, len(p.ObjectRefs)+len(o.ObjectRefs))
+		for k, v := range p.ObjectRefs {
+			out.ObjectRefs[k] = v
+		}
+		for k, v := range o.ObjectRefs {
+			out.ObjectRefs[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type ReferenceSuite struct{}
+
+var _ = Suite(&ReferenceSuite{})
+
+type mapGetter map[string]*crv1alpha1.BlueprintLibrary
+
+func (mapGetter) GetBlueprint(ctx context.Context, name string) (*crv1alpha1.Blueprint, error) {
+	return nil, errors.Errorf("Blueprint %s not found", name)
+}
+
+func (g mapGetter) GetBlueprintLibrary(ctx context.Context, name string) (*crv1alpha1.BlueprintLibrary, error) {
+	lib, ok := g[name]
+	if !ok {
+		return nil, errors.Errorf("BlueprintLibrary %s not found", name)
+	}
+	return lib, nil
+}
+
+func testLibrary() *crv1alpha1.BlueprintLibrary {
+	return &crv1alpha1.BlueprintLibrary{
+		ObjectMeta: metav1.ObjectMeta{Name: "lib"},
+		Phases: []crv1alpha1.BlueprintPhase{
+			{Name: "quiesce", Func: "KubeExec", Args: map[string]interface{}{"command": "quiesce", "pod": "db-0"}},
+			{Name: "unquiesce", Func: "KubeExec", Args: map[string]interface{}{"command": "unquiesce", "pod": "db-0"}},
+		},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Kind: "StatefulSet",
+				Parameters: []crv1alpha1.BlueprintParameter{
+					{Name: "pod", Default: "db-0"},
+					{Name: "path", Default: "/data"},
+				},
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce", Ref: &crv1alpha1.BlueprintReference{Phase: "quiesce"}},
+					{Name: "backup", Func: "BackupData", Args: map[string]interface{}{"pod": "db-0", "includePath": "/data"}},
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{Name: "unquiesce", Ref: &crv1alpha1.BlueprintReference{Phase: "unquiesce"}},
+			},
+		},
+	}
+}
+
+func (s *ReferenceSuite) TestResolvePhase(c *C) {
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "freeze", Ref: &crv1alpha1.BlueprintReference{Name: "lib", Phase: "quiesce"}, Args: map[string]interface{}{"pod": "db-1"}},
+					{Name: "dump", Func: "KubeTask"},
+				},
+			},
+		},
+	}
+	r, err := ResolveBlueprint(context.Background(), mapGetter{"lib": testLibrary()}, bp)
+	c.Assert(err, IsNil)
+	c.Assert(r.Actions["backup"].Phases, DeepEquals, []crv1alpha1.BlueprintPhase{
+		{Name: "freeze", Func: "KubeExec", Args: map[string]interface{}{"command": "quiesce", "pod": "db-1"}},
+		{Name: "dump", Func: "KubeTask"},
+	})
+	// The library and the Blueprint are not modified
+	c.Assert(testLibrary().Phases[0].Args["pod"], Equals, "db-0")
+	c.Assert(bp.Actions["backup"].Phases[0].Ref, NotNil)
+}
+
+func (s *ReferenceSuite) TestResolveExtends(c *C) {
+	bp := &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {
+				Extends:         &crv1alpha1.BlueprintReference{Kind: RefKindBlueprintLibrary, Name: "lib", Action: "backup"},
+				OutputArtifacts: map[string]crv1alpha1.Artifact{"backup": {KeyValue: map[string]string{"path": "/backup"}}},
+				Parameters:      []crv1alpha1.BlueprintParameter{{Name: "path", Default: "/var/lib/db"}, {Name: "verify"}},
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "backup", Args: map[string]interface{}{"includePath": "/var/lib/db"}},
+					{Name: "check", Func: "KubeTask"},
+				},
+			},
+			"backupAgain": {
+				Extends: &crv1alpha1.BlueprintReference{Kind: RefKindBlueprint, Action: "backup"},
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce", Func: "KubeTask"},
+				},
+			},
+		},
+	}
+	r, err := ResolveBlueprint(context.Background(), mapGetter{"lib": testLibrary()}, bp)
+	c.Assert(err, IsNil)
+	backup := r.Actions["backup"]
+	c.Assert(backup.Extends, IsNil)
+	c.Assert(backup.Kind, Equals, "StatefulSet")
+	c.Assert(backup.OutputArtifacts, HasLen, 1)
+	c.Assert(backup.Parameters, DeepEquals, []crv1alpha1.BlueprintParameter{
+		{Name: "pod", Default: "db-0"},
+		{Name: "path", Default: "/var/lib/db"},
+		{Name: "verify"},
+	})
+	c.Assert(backup.Phases, DeepEquals, []crv1alpha1.BlueprintPhase{
+		{Name: "quiesce", Func: "KubeExec", Args: map[string]interface{}{"command": "quiesce", "pod": "db-0"}},
+		{Name: "backup", Func: "BackupData", Args: map[string]interface{}{"pod": "db-0", "includePath": "/var/lib/db"}},
+		{Name: "check", Func: "KubeTask"},
+	})
+	c.Assert(backup.DeferPhase, DeepEquals, &crv1alpha1.BlueprintPhase{
+		Name: "unquiesce", Func: "KubeExec", Args: map[string]interface{}{"command": "unquiesce", "pod": "db-0"},
+	})
+	again := r.Actions["backupAgain"]
+	c.Assert(again.Phases, HasLen, 3)
+	c.Assert(again.Phases[0], DeepEquals, crv1alpha1.BlueprintPhase{Name: "quiesce", Func: "KubeTask"})
+	c.Assert(again.Phases[1].Args["includePath"], Equals, "/var/lib/db")
+}
+
+func (s *ReferenceSuite) TestResolveErrors(c *C) {
+	for _, tc := range []struct {
+		action *crv1alpha1.BlueprintAction
+		err    string
+	}{
+		{
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "p", Ref: &crv1alpha1.BlueprintReference{Name: "lib", Phase: "unknown"}},
+			}},
+			err: ".*Phase unknown referred to by phase p not found in BlueprintLibrary lib.*",
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "p", Func: "KubeTask", Ref: &crv1alpha1.BlueprintReference{Name: "lib", Phase: "quiesce"}},
+			}},
+			err: ".*Phase p cannot have both a func and a ref.*",
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "p", Ref: &crv1alpha1.BlueprintReference{Name: "other", Phase: "quiesce"}},
+			}},
+			err: ".*BlueprintLibrary other not found.*",
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "p", Ref: &crv1alpha1.BlueprintReference{Kind: "ConfigMap", Name: "lib", Phase: "quiesce"}},
+			}},
+			err: ".*Unsupported kind ConfigMap.*",
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{Extends: &crv1alpha1.BlueprintReference{Action: "action"}},
+			err:    ".*Cyclic reference Blueprint bp action action -> Blueprint bp action action.*",
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{Extends: &crv1alpha1.BlueprintReference{Name: "lib", Phase: "quiesce"}},
+			err:    ".*Action action must extend an action, not a phase.*",
+		},
+	} {
+		bp := &crv1alpha1.Blueprint{
+			ObjectMeta: metav1.ObjectMeta{Name: "bp"},
+			Actions:    map[string]*crv1alpha1.BlueprintAction{"action": tc.action},
+		}
+		_, err := ResolveBlueprint(context.Background(), mapGetter{"lib": testLibrary()}, bp)
+		c.Assert(err, ErrorMatches, tc.err)
+	}
+}
+
+func (s *ReferenceSuite) TestGetPhasesUnresolved(c *C) {
+	bp := crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": {Extends: &crv1alpha1.BlueprintReference{Name: "lib", Action: "backup"}},
+		},
+	}
+	_, err := GetPhases(bp, "backup", DefaultVersion, param.TemplateParams{})
+	c.Assert(err, ErrorMatches, ".*Cannot get BlueprintLibrary lib.*")
+	_, err = GetDeferPhase(bp, "backup", DefaultVersion, param.TemplateParams{})
+	c.Assert(err, ErrorMatches, ".*Cannot get BlueprintLibrary lib.*")
+}
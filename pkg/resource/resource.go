@@ -45,6 +45,8 @@ func CreateCustomResources(ctx context.Context, config *rest.Config) error {
 	resources := []customresource.CustomResource{
 		crv1alpha1.ActionSetResource,
 		crv1alpha1.BlueprintResource,
+		crv1alpha1.BlueprintLibraryResource,
+		crv1alpha1.BlueprintRevisionResource,
 		crv1alpha1.ProfileResource,
 	}
 	return customresource.CreateCustomResources(*crCTX, resources)
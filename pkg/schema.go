@@ -0,0 +1,279 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	"k8s.io/utils/strings/slices"
+)
+
+// Argument types of the argument schemas of Funcs
+const (
+	ArgTypeString  = "string"
+	ArgTypeInteger = "integer"
+	ArgTypeBoolean = "boolean"
+	ArgTypeArray   = "array"
+	ArgTypeObject  = "object"
+)
+
+// ArgSchema describes an argument of a Func. An empty Type accepts any value.
+type ArgSchema struct {
+	Name        string
+	Type        string
+	Default     interface{}
+	Enum        []string
+	Description string
+}
+
+// ArgsDescriber is implemented by Funcs that declare the schema of their
+// arguments. The schema is used instead of Arguments to check the arguments
+// of a phase and their types.
+type ArgsDescriber interface {
+	ArgSchemas() []ArgSchema
+}
+
+// checkArgs checks that the args are supported by the Func and, if it declares
+// their schema, that they have the declared types.
+func checkArgs(f Func, args map[string]interface{}) error {
+	d, ok := f.(ArgsDescriber)
+	if !ok {
+		return checkSupportedArgs(f.Arguments(), args)
+	}
+	schemas := make(map[string]ArgSchema)
+	for _, s := range d.ArgSchemas() {
+		schemas[s.Name] = s
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s, ok := schemas[name]
+		if !ok {
+			return errors.Errorf("argument %s is not supported", name)
+		}
+		if err := checkArgType(s, args[name]); err != nil {
+			return errors.Wrapf(err, "Invalid argument %s", name)
+		}
+	}
+	return nil
+}
+
+// checkArgType checks the value the way the functions decode it, i.e. strings
+// are accepted for the other types. Templates are checked once rendered.
+func checkArgType(s ArgSchema, val interface{}) error {
+	if val == nil {
+		return nil
+	}
+	if str, ok := val.(string); ok && strings.Contains(str, "{{") {
+		return nil
+	}
+	v := reflect.ValueOf(val)
+	switch s.Type {
+	case ArgTypeString:
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+			return errors.Errorf("expected a %s, got %T", s.Type, val)
+		}
+		if len(s.Enum) > 0 && v.Kind() == reflect.String && !slices.Contains(s.Enum, v.String()) {
+			return errors.Errorf("expected one of %v, got %s", s.Enum, v.String())
+		}
+	case ArgTypeInteger:
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		case reflect.Float32, reflect.Float64:
+			if f := v.Float(); f != math.Trunc(f) {
+				return errors.Errorf("expected an %s, got %v", s.Type, val)
+			}
+		case reflect.String:
+			// Empty strings are decoded as zero
+			if _, err := strconv.ParseInt(v.String(), 0, 64); err != nil && v.String() != "" {
+				return errors.Errorf("expected an %s, got %q", s.Type, v.String())
+			}
+		default:
+			return errors.Errorf("expected an %s, got %T", s.Type, val)
+		}
+	case ArgTypeBoolean:
+		switch v.Kind() {
+		case reflect.Bool:
+		case reflect.String:
+			if _, err := strconv.ParseBool(v.String()); err != nil && v.String() != "" {
+				return errors.Errorf("expected a %s, got %q", s.Type, v.String())
+			}
+		default:
+			return errors.Errorf("expected a %s, got %T", s.Type, val)
+		}
+	case ArgTypeArray:
+		// Lists can also be passed as a YAML string, e.g. from an artifact
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.String:
+		default:
+			return errors.Errorf("expected an %s, got %T", s.Type, val)
+		}
+	case ArgTypeObject:
+		switch v.Kind() {
+		case reflect.Map, reflect.Struct, reflect.Ptr, reflect.String:
+		default:
+			return errors.Errorf("expected an %s, got %T", s.Type, val)
+		}
+	}
+	return nil
+}
+
+// ArgsJSONSchema returns the JSON Schema of the args of the Func, or nil if
+// the Func does not declare the schema of its arguments.
+func ArgsJSONSchema(f Func) map[string]interface{} {
+	d, ok := f.(ArgsDescriber)
+	if !ok {
+		return nil
+	}
+	props := make(map[string]interface{})
+	for _, s := range d.ArgSchemas() {
+		props[s.Name] = argJSONSchema(s)
+	}
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+	if req := f.RequiredArgs(); len(req) > 0 {
+		schema["required"] = req
+	}
+	return schema
+}
+
+// templateJSONSchema matches the templates, which can render any type
+var templateJSONSchema = map[string]interface{}{"type": "string", "pattern": `\{\{`}
+
+func argJSONSchema(s ArgSchema) map[string]interface{} {
+	schema := map[string]interface{}{}
+	switch s.Type {
+	case ArgTypeString:
+		schema["type"] = []string{"string", "number", "boolean"}
+	case ArgTypeInteger:
+		schema["type"] = []string{"integer", "string"}
+	case ArgTypeBoolean:
+		schema["type"] = []string{"boolean", "string"}
+	case ArgTypeArray:
+		schema["type"] = []string{"array", "string"}
+	case ArgTypeObject:
+		schema["type"] = []string{"object", "string"}
+	}
+	if len(s.Enum) > 0 {
+		schema = map[string]interface{}{
+			"anyOf": []interface{}{map[string]interface{}{"enum": s.Enum}, templateJSONSchema},
+		}
+	}
+	if s.Description != "" {
+		schema["description"] = s.Description
+	}
+	if s.Default != nil {
+		schema["default"] = s.Default
+	}
+	return schema
+}
+
+// BlueprintJSONSchema returns a JSON Schema of Blueprints for editors. The
+// args of the phases are described by the schemas of the functions with the
+// given version.
+func BlueprintJSONSchema(version string) (map[string]interface{}, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid function version %s", version)
+	}
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	names := make([]string, 0, len(funcs))
+	for name, fs := range funcs {
+		if _, ok := fs[*v]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	definitions := map[string]interface{}{
+		"action": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"phases":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/definitions/phase"}},
+				"deferPhase": map[string]interface{}{"$ref": "#/definitions/phase"},
+				"extends":    map[string]interface{}{"$ref": "#/definitions/reference"},
+			},
+		},
+		"reference": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"kind":   map[string]interface{}{"enum": []string{RefKindBlueprint, RefKindBlueprintLibrary}},
+				"name":   map[string]interface{}{"type": "string"},
+				"action": map[string]interface{}{"type": "string"},
+				"phase":  map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	conditions := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		f := funcs[name][*v]
+		args := ArgsJSONSchema(f)
+		if args == nil {
+			continue
+		}
+		definitions[name+"Args"] = args
+		then := map[string]interface{}{
+			"properties": map[string]interface{}{"args": map[string]interface{}{"$ref": "#/definitions/" + name + "Args"}},
+		}
+		if len(f.RequiredArgs()) > 0 {
+			then["required"] = []string{"args"}
+		}
+		conditions = append(conditions, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"func": map[string]interface{}{"const": name}},
+				"required":   []string{"func"},
+			},
+			"then": then,
+		})
+	}
+	definitions["phase"] = map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]interface{}{
+			"func":    map[string]interface{}{"enum": names},
+			"name":    map[string]interface{}{"type": "string"},
+			"objects": map[string]interface{}{"type": "object"},
+			"args":    map[string]interface{}{"type": "object"},
+			"ref":     map[string]interface{}{"$ref": "#/definitions/reference"},
+		},
+		"allOf": conditions,
+	}
+	return map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title":   "Blueprint",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"const": "Blueprint"},
+			"metadata":   map[string]interface{}{"type": "object"},
+			"actions":    map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"$ref": "#/definitions/action"}},
+		},
+		"definitions": definitions,
+	}, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kanister
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type SchemaSuite struct{}
+
+var _ = Suite(&SchemaSuite{})
+
+type typedFunc struct {
+	testFunc
+}
+
+func (*typedFunc) Name() string {
+	return "typedTestFunc"
+}
+
+func (*typedFunc) RequiredArgs() []string {
+	return []string{"name"}
+}
+
+func (*typedFunc) ArgSchemas() []ArgSchema {
+	return []ArgSchema{
+		{Name: "name", Type: ArgTypeString, Description: "Name"},
+		{Name: "mode", Type: ArgTypeString, Enum: []string{"fast", "slow"}, Default: "fast"},
+		{Name: "replicas", Type: ArgTypeInteger},
+		{Name: "wait", Type: ArgTypeBoolean},
+		{Name: "paths", Type: ArgTypeArray},
+		{Name: "labels", Type: ArgTypeObject},
+		{Name: "any"},
+	}
+}
+
+func (s *SchemaSuite) TestCheckArgs(c *C) {
+	f := &typedFunc{}
+	for _, tc := range []struct {
+		args   map[string]interface{}
+		errMsg string
+	}{
+		{
+			args: map[string]interface{}{
+				"name":     "app",
+				"mode":     "slow",
+				"replicas": float64(2),
+				"wait":     "true",
+				"paths":    []interface{}{"/data"},
+				"labels":   map[string]interface{}{"app": "app"},
+				"any":      []interface{}{1},
+			},
+		},
+		{
+			// Strings are decoded into the other types
+			args: map[string]interface{}{"replicas": "2", "wait": "", "paths": "- /data", "labels": "app: app"},
+		},
+		{
+			// Templates are checked once rendered
+			args: map[string]interface{}{"mode": "{{ .Options.mode }}", "replicas": "{{ .Options.replicas }}", "paths": "{{ .Options.paths }}"},
+		},
+		{
+			args:   map[string]interface{}{"nmae": "app"},
+			errMsg: "argument nmae is not supported",
+		},
+		{
+			args:   map[string]interface{}{"name": []interface{}{"app"}},
+			errMsg: "Invalid argument name: expected a string, got \\[\\]interface {}",
+		},
+		{
+			args:   map[string]interface{}{"mode": "quick"},
+			errMsg: "Invalid argument mode: expected one of \\[fast slow\\], got quick",
+		},
+		{
+			args:   map[string]interface{}{"replicas": 1.5},
+			errMsg: "Invalid argument replicas: expected an integer, got 1.5",
+		},
+		{
+			args:   map[string]interface{}{"replicas": "two"},
+			errMsg: "Invalid argument replicas: expected an integer, got \"two\"",
+		},
+		{
+			args:   map[string]interface{}{"wait": "yes"},
+			errMsg: "Invalid argument wait: expected a boolean, got \"yes\"",
+		},
+		{
+			args:   map[string]interface{}{"paths": map[string]interface{}{}},
+			errMsg: "Invalid argument paths: expected an array, got map\\[string\\]interface {}",
+		},
+		{
+			args:   map[string]interface{}{"labels": []interface{}{}},
+			errMsg: "Invalid argument labels: expected an object, got \\[\\]interface {}",
+		},
+	} {
+		err := checkArgs(f, tc.args)
+		if tc.errMsg == "" {
+			c.Assert(err, IsNil)
+			continue
+		}
+		c.Assert(err, ErrorMatches, tc.errMsg)
+	}
+
+	// Funcs without schema are checked against their Arguments
+	err := checkArgs(&testFunc{}, map[string]interface{}{"testKey": []interface{}{}})
+	c.Assert(err, ErrorMatches, "argument testKey is not supported")
+}
+
+func (s *SchemaSuite) TestBlueprintJSONSchema(c *C) {
+	err := RegisterVersion(&typedFunc{}, "v0.0.44")
+	c.Assert(err, IsNil)
+	schema, err := BlueprintJSONSchema("v0.0.44")
+	c.Assert(err, IsNil)
+
+	defs := schema["definitions"].(map[string]interface{})
+	phase := defs["phase"].(map[string]interface{})
+	c.Assert(phase["properties"].(map[string]interface{})["func"], DeepEquals, map[string]interface{}{"enum": []string{"typedTestFunc"}})
+	c.Assert(phase["allOf"], HasLen, 1)
+
+	args := defs["typedTestFuncArgs"].(map[string]interface{})
+	c.Assert(args["required"], DeepEquals, []string{"name"})
+	props := args["properties"].(map[string]interface{})
+	c.Assert(props["replicas"], DeepEquals, map[string]interface{}{"type": []string{"integer", "string"}})
+	c.Assert(props["mode"], DeepEquals, map[string]interface{}{
+		"anyOf":   []interface{}{map[string]interface{}{"enum": []string{"fast", "slow"}}, templateJSONSchema},
+		"default": "fast",
+	})
+	c.Assert(props["any"], DeepEquals, map[string]interface{}{})
+
+	_, err = BlueprintJSONSchema("latest")
+	c.Assert(err, NotNil)
+}
@@ -45,6 +45,9 @@ const (
 	TypeKey          = "type"
 	// Location secret key to be used only for filestore location type
 	ClaimNameKey = "claimName"
+	// Location secret keys to be used only for s3 location type
+	ObjectLockModeKey            = "objectLockMode"
+	ObjectLockRetentionPeriodKey = "objectLockRetentionPeriod"
 
 	// Kopia Repository Server secret keys
 	RepoPasswordKey  = "repo-password"
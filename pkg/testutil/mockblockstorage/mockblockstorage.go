@@ -55,6 +55,12 @@ func (*mockGetter) Get(storageType blockstorage.Type, config map[string]string)
 	case blockstorage.TypeEBS:
 		fallthrough
 	case blockstorage.TypeGPD:
+		fallthrough
+	case blockstorage.TypeAD:
+		fallthrough
+	case blockstorage.TypeGeneric:
+		fallthrough
+	case blockstorage.TypeCeph:
 		return Get(storageType)
 	default:
 		return nil, errors.New("Get failed")
@@ -147,6 +147,24 @@ func ProfileSchema(p *crv1alpha1.Profile) error {
 			return errorf(validateErr, "Bucket region not specified")
 		}
 	}
+	return validateObjectLock(p.Location)
+}
+
+func validateObjectLock(l crv1alpha1.Location) error {
+	if l.ObjectLock == nil {
+		return nil
+	}
+	if l.Type != crv1alpha1.LocationTypeS3Compliant {
+		return errorf(validateErr, "Object lock is not supported for location type '%s'", l.Type)
+	}
+	switch l.ObjectLock.Mode {
+	case crv1alpha1.ObjectLockModeGovernance, crv1alpha1.ObjectLockModeCompliance:
+	default:
+		return errorf(validateErr, "Unsupported object lock mode '%s'", l.ObjectLock.Mode)
+	}
+	if l.ObjectLock.RetentionPeriod.Duration <= 0 {
+		return errorf(validateErr, "Object lock retention period must be positive")
+	}
 	return nil
 }
 
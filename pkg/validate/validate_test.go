@@ -17,6 +17,7 @@ package validate
 import (
 	"context"
 	"testing"
+	"time"
 
 	. "gopkg.in/check.v1"
 	v1 "k8s.io/api/core/v1"
@@ -594,6 +595,85 @@ func (s *ValidateSuite) TestProfileSchema(c *C) {
 			},
 			checker: NotNil,
 		},
+		// Object lock
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeS3Compliant,
+					ObjectLock: &crv1alpha1.ObjectLock{
+						Mode:            crv1alpha1.ObjectLockModeCompliance,
+						RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		// Object lock on unsupported location type
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeGCS,
+					ObjectLock: &crv1alpha1.ObjectLock{
+						Mode:            crv1alpha1.ObjectLockModeCompliance,
+						RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		// Invalid object lock mode
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeS3Compliant,
+					ObjectLock: &crv1alpha1.ObjectLock{
+						Mode:            "invalid",
+						RetentionPeriod: metav1.Duration{Duration: 24 * time.Hour},
+					},
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		// Missing object lock retention period
+		{
+			profile: &crv1alpha1.Profile{
+				Location: crv1alpha1.Location{
+					Type: crv1alpha1.LocationTypeS3Compliant,
+					ObjectLock: &crv1alpha1.ObjectLock{
+						Mode: crv1alpha1.ObjectLockModeGovernance,
+					},
+				},
+				Credential: crv1alpha1.Credential{
+					Type: crv1alpha1.CredentialTypeSecret,
+					Secret: &crv1alpha1.ObjectReference{
+						Name:      "secret-name",
+						Namespace: "secret-namespace",
+					},
+				},
+			},
+			checker: NotNil,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -9,11 +9,16 @@ import (
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blueprint"
 	"github.com/kanisterio/kanister/pkg/blueprint/validate"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 )
 
 type BlueprintValidator struct {
 	decoder *admission.Decoder
+	// CrClient gets the Blueprints and BlueprintLibraries referenced by the
+	// validated Blueprints
+	CrClient versioned.Interface
 }
 
 func (b *BlueprintValidator) Handle(ctx context.Context, r admission.Request) admission.Response {
@@ -23,6 +28,15 @@ func (b *BlueprintValidator) Handle(ctx context.Context, r admission.Request) ad
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	var g kanister.BlueprintGetter
+	if b.CrClient != nil {
+		g = blueprint.NewClientGetter(b.CrClient, r.Namespace)
+	}
+	bp, err = kanister.ResolveBlueprint(ctx, g, bp)
+	if err != nil {
+		return admission.Denied(fmt.Sprintf("Invalid blueprint, %s\n", err.Error()))
+	}
+
 	if err := validate.Do(bp, kanister.DefaultVersion); err != nil {
 		return admission.Denied(fmt.Sprintf("Invalid blueprint, %s\n", err.Error()))
 	}
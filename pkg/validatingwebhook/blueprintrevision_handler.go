@@ -0,0 +1,64 @@
+// Copyright 2023 The Kanister Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatingwebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/blueprint"
+)
+
+// BlueprintRevisionValidator denies BlueprintRevisions whose actions do not
+// match their hash, and the updates of the actions of BlueprintRevisions,
+// which are immutable.
+type BlueprintRevisionValidator struct {
+	decoder *admission.Decoder
+}
+
+func (b *BlueprintRevisionValidator) Handle(ctx context.Context, r admission.Request) admission.Response {
+	rev := &crv1alpha1.BlueprintRevision{}
+	if err := b.decoder.Decode(r, rev); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if r.Operation == admissionv1.Update {
+		old := &crv1alpha1.BlueprintRevision{}
+		if err := b.decoder.DecodeRaw(r.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if old.Blueprint != rev.Blueprint || old.Hash != rev.Hash || !reflect.DeepEqual(old.Actions, rev.Actions) {
+			return admission.Denied(fmt.Sprintf("Invalid blueprint revision, revision %s is immutable\n", rev.GetName()))
+		}
+	}
+
+	if err := blueprint.VerifyRevision(rev); err != nil {
+		return admission.Denied(fmt.Sprintf("Invalid blueprint revision, %s\n", err.Error()))
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder injects the decoder.
+func (b *BlueprintRevisionValidator) InjectDecoder(d *admission.Decoder) error {
+	b.decoder = d
+	return nil
+}